@@ -20,6 +20,9 @@ func main() {
 			commands.NamerCommand(),
 			commands.BundlerCommand(),
 			commands.BuildCommand(),
+			commands.IdentityCommand(),
+			commands.BrandCommand(),
+			commands.PlistCommand(),
 			// More commands will be added here
 		},
 	}
@@ -1,8 +1,14 @@
 package commands
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 
 	"github.com/Jerinji2016/fdawg/pkg/asset"
 	"github.com/Jerinji2016/fdawg/pkg/flutter"
@@ -49,7 +55,14 @@ func AssetCommand() *cli.Command {
 				Name:        "list",
 				Usage:       "List all assets in the project",
 				Description: "Lists all assets in the project by type",
-				Action:      listAssets,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "details",
+						Aliases: []string{"d"},
+						Usage:   "Show size, dimensions, duration and duplicate files for each asset",
+					},
+				},
+				Action: listAssets,
 			},
 			{
 				Name:        "generate-dart",
@@ -60,8 +73,72 @@ func AssetCommand() *cli.Command {
 			{
 				Name:        "migrate",
 				Usage:       "Migrate assets to organized folders by type",
-				Description: "Migrates assets to organized folders by type and cleans up empty directories",
-				Action:      migrateAssets,
+				Description: "Migrates assets to organized folders by type, updates pubspec.yaml and lib/ references, and cleans up empty directories. Use --plan to review the changes first, --apply to run a saved plan and --undo to restore the previous layout",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "plan",
+						Usage: "Write a migration plan without moving any files",
+					},
+					&cli.BoolFlag{
+						Name:  "apply",
+						Usage: "Apply the saved migration plan",
+					},
+					&cli.BoolFlag{
+						Name:  "undo",
+						Usage: "Undo the last applied migration",
+					},
+				},
+				Action: migrateAssets,
+			},
+			{
+				Name:        "dedupe",
+				Usage:       "Find and merge duplicate assets",
+				Description: "Finds byte-identical assets and visually near-identical images, then merges each group into one file and updates references in lib/. Visually similar groups are only merged with --include-similar, and always after asking",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "threshold",
+						Value: asset.DefaultPerceptualThreshold,
+						Usage: "Maximum perceptual hash distance (0-64) for near-duplicate images",
+					},
+					&cli.BoolFlag{
+						Name:  "exact-only",
+						Usage: "Only report byte-identical files",
+					},
+					&cli.BoolFlag{
+						Name:  "include-similar",
+						Usage: "Also offer to merge visually similar images; each group is confirmed even with --yes",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Report duplicates without merging them",
+					},
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "Merge every identical group without prompting, keeping the suggested file",
+					},
+				},
+				Action: dedupeAssets,
+			},
+			{
+				Name:        "watch",
+				Usage:       "Watch assets and regenerate on change",
+				Description: "Watches the assets directory and regenerates the Dart asset file and pubspec.yaml entries whenever files change",
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:    "interval",
+						Aliases: []string{"i"},
+						Value:   asset.DefaultWatchInterval,
+						Usage:   "How often to poll the assets directory",
+					},
+					&cli.DurationFlag{
+						Name:    "debounce",
+						Aliases: []string{"d"},
+						Value:   asset.DefaultWatchDebounce,
+						Usage:   "How long to wait for changes to settle before regenerating",
+					},
+				},
+				Action: watchAssets,
 			},
 		},
 	}
@@ -192,6 +269,10 @@ func listAssets(c *cli.Context) error {
 		return err
 	}
 
+	if c.Bool("details") {
+		return listAssetDetails(project.ProjectPath)
+	}
+
 	// List assets
 	utils.Info("Listing assets...")
 
@@ -227,6 +308,69 @@ func listAssets(c *cli.Context) error {
 	return nil
 }
 
+// listAssetDetails lists all assets with their metadata
+func listAssetDetails(projectPath string) error {
+	utils.Info("Reading asset details...")
+
+	metadata, err := asset.GetAssetMetadata(projectPath)
+	if err != nil {
+		utils.Error("Failed to read asset details: %v", err)
+		return err
+	}
+
+	fmt.Println(utils.Separator("=", 50))
+	utils.Success("Project Assets")
+	fmt.Println(utils.Separator("=", 50))
+
+	totalAssets := 0
+	totalSize := int64(0)
+	duplicates := 0
+	for assetType, items := range metadata {
+		fmt.Println(utils.Separator("-", 50))
+		utils.Info("%s Assets", assetType)
+
+		if len(items) == 0 {
+			fmt.Println("No assets found")
+			continue
+		}
+
+		for _, item := range items {
+			fmt.Printf("- %s (%s)\n", item.Name, formatAssetDetails(item))
+			if len(item.Duplicates) > 0 {
+				utils.Warning("  Identical to: %s", strings.Join(item.Duplicates, ", "))
+				duplicates++
+			}
+			totalSize += item.Size
+		}
+		totalAssets += len(items)
+	}
+
+	fmt.Println(utils.Separator("=", 50))
+	utils.Success("Total Assets: %d (%s)", totalAssets, utils.FormatFileSize(totalSize))
+	if duplicates > 0 {
+		utils.Warning("%d assets have identical copies", duplicates)
+	}
+
+	return nil
+}
+
+// formatAssetDetails formats the size, dimensions and duration of an asset
+func formatAssetDetails(item asset.AssetMetadata) string {
+	details := []string{utils.FormatFileSize(item.Size)}
+
+	if item.Width > 0 && item.Height > 0 {
+		details = append(details, fmt.Sprintf("%dx%d", item.Width, item.Height))
+	}
+	if item.Duration > 0 {
+		details = append(details, fmt.Sprintf("%.1fs", item.Duration))
+	}
+	if item.FrameRate > 0 {
+		details = append(details, fmt.Sprintf("%g fps", item.FrameRate))
+	}
+
+	return strings.Join(details, ", ")
+}
+
 // generateDartAssetFile generates a Dart asset file with all assets
 func generateDartAssetFile(c *cli.Context) error {
 	// Validate Flutter project
@@ -257,31 +401,266 @@ func migrateAssets(c *cli.Context) error {
 		return err
 	}
 
-	// Migrate assets
-	utils.Info("Migrating assets to organized folders...")
+	modes := 0
+	for _, flag := range []string{"plan", "apply", "undo"} {
+		if c.Bool(flag) {
+			modes++
+		}
+	}
+	if modes > 1 {
+		utils.Error("Only one of --plan, --apply and --undo can be used")
+		return fmt.Errorf("conflicting flags")
+	}
+
+	switch {
+	case c.Bool("plan"):
+		return planAssetMigration(project.ProjectPath)
+	case c.Bool("undo"):
+		utils.Info("Undoing the last asset migration...")
+
+		result, err := asset.UndoMigration(project.ProjectPath)
+		if err != nil {
+			utils.Error("Failed to undo migration: %v", err)
+			return err
+		}
+
+		utils.Success("Migration undone - %d files restored", result.FilesProcessed)
+		return nil
+	}
+
+	var result *asset.MigrationResult
+	if c.Bool("apply") {
+		plan, err := asset.LoadMigrationPlan(project.ProjectPath)
+		if err != nil {
+			utils.Error("Failed to load migration plan: %v", err)
+			return err
+		}
 
-	result, err := asset.MigrateAssets(project.ProjectPath)
+		utils.Info("Applying migration plan from %s...", plan.CreatedAt.Format("2006-01-02 15:04:05"))
+		result, err = asset.ApplyMigrationPlan(project.ProjectPath, plan)
+		if err != nil {
+			utils.Error("Failed to apply migration plan: %v", err)
+			return err
+		}
+	} else {
+		utils.Info("Migrating assets to organized folders...")
+
+		result, err = asset.MigrateAssets(project.ProjectPath)
+		if err != nil {
+			utils.Error("Failed to migrate assets: %v", err)
+			return err
+		}
+	}
+
+	if result.NoFilesToMigrate {
+		utils.Success("No assets to migrate - all assets are already organized")
+		return nil
+	}
+
+	utils.Success("Assets migrated successfully - %d files processed", result.FilesProcessed)
+	if result.ReferencesUpdated > 0 {
+		utils.Info("Updated %d asset references in lib/", result.ReferencesUpdated)
+	}
+	utils.Info("Run 'fdawg asset migrate --undo' to restore the previous layout")
+	return nil
+}
+
+// planAssetMigration writes a migration plan and prints the changes it will make
+func planAssetMigration(projectPath string) error {
+	plan, err := asset.CreateMigrationPlan(projectPath)
 	if err != nil {
-		utils.Error("Failed to migrate assets: %v", err)
+		utils.Error("Failed to create migration plan: %v", err)
 		return err
 	}
 
-	if result.NoFilesToMigrate {
+	if len(plan.Moves) == 0 {
 		utils.Success("No assets to migrate - all assets are already organized")
-	} else {
-		utils.Success("Assets migrated successfully - %d files processed", result.FilesProcessed)
+		return nil
 	}
 
-	// Generate the Dart asset file
-	utils.Info("Generating Dart asset file...")
+	utils.Info("Files to move (%d):", len(plan.Moves))
+	for _, move := range plan.Moves {
+		utils.Log("  %s -> %s", move.From, move.To)
+	}
 
-	err = asset.GenerateDartAssetFile(project.ProjectPath)
+	if len(plan.PubspecAdditions) > 0 || len(plan.PubspecRemovals) > 0 {
+		utils.Info("pubspec.yaml changes:")
+		for _, entry := range plan.PubspecAdditions {
+			utils.Log("  + %s", entry)
+		}
+		for _, entry := range plan.PubspecRemovals {
+			utils.Log("  - %s", entry)
+		}
+	}
+
+	if len(plan.References) > 0 {
+		utils.Info("References to update in lib/:")
+		for _, reference := range plan.References {
+			utils.Log("  %s: %s -> %s (%d)", reference.File, reference.From, reference.To, reference.Count)
+		}
+	}
+
+	if err := asset.SaveMigrationPlan(projectPath, plan); err != nil {
+		utils.Error("Failed to save migration plan: %v", err)
+		return err
+	}
+
+	utils.Success("Migration plan saved to %s", asset.GetMigrationPlanPath(projectPath))
+	utils.Info("Run 'fdawg asset migrate --apply' to apply it")
+	return nil
+}
+
+// dedupeAssets finds duplicate assets and offers to merge them
+func dedupeAssets(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForAsset()
 	if err != nil {
-		utils.Warning("Failed to generate Dart asset file: %v", err)
-	} else {
-		dartFilePath := filepath.Join(project.ProjectPath, "lib", "config", "asset.dart")
-		utils.Success("Dart asset file generated successfully at %s", dartFilePath)
+		return err
+	}
+
+	utils.Info("Looking for duplicate assets...")
+
+	groups, err := asset.FindDuplicates(project.ProjectPath, asset.DedupeOptions{
+		Threshold: c.Int("threshold"),
+		ExactOnly: c.Bool("exact-only"),
+	})
+	if err != nil {
+		utils.Error("Failed to find duplicates: %v", err)
+		return err
+	}
+
+	if len(groups) == 0 {
+		utils.Success("No duplicate assets found")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	merged := 0
+
+	for i, group := range groups {
+		kind := "identical"
+		if !group.Exact {
+			kind = "visually similar"
+		}
+
+		fmt.Println(utils.Separator("-", 50))
+		utils.Info("Group %d of %d (%s)", i+1, len(groups), kind)
+
+		files := append([]string{group.Keep}, group.Duplicates...)
+		for j, file := range files {
+			fmt.Printf("  [%d] %s\n", j+1, file)
+		}
+
+		if c.Bool("dry-run") {
+			continue
+		}
+
+		// Similar-looking images can be intentional variants, so they are never merged unasked
+		if !group.Exact && !c.Bool("include-similar") {
+			utils.Info("Not merged: use --include-similar to merge visually similar files")
+			continue
+		}
+
+		keepIndex := 0
+		if !c.Bool("yes") || !group.Exact {
+			fmt.Printf("Keep which file? [1-%d, Enter = 1, s = skip]: ", len(files))
+			answer, _ := reader.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+
+			if strings.ToLower(answer) == "s" {
+				utils.Info("Skipped")
+				continue
+			}
+			if answer != "" {
+				if _, err := fmt.Sscanf(answer, "%d", &keepIndex); err != nil || keepIndex < 1 || keepIndex > len(files) {
+					utils.Warning("Invalid choice, skipping group")
+					continue
+				}
+				keepIndex--
+			}
+		}
+
+		keep := files[keepIndex]
+		var duplicates []string
+		for j, file := range files {
+			if j != keepIndex {
+				duplicates = append(duplicates, file)
+			}
+		}
+
+		result, err := asset.MergeDuplicates(project.ProjectPath, keep, duplicates)
+		if err != nil {
+			utils.Error("Failed to merge duplicates: %v", err)
+			return err
+		}
+
+		utils.Success("Kept %s, removed %d files and updated %d references", keep, result.FilesRemoved, result.ReferencesUpdated)
+		for _, file := range result.UpdatedDartFiles {
+			utils.Log("  - %s", file)
+		}
+		merged++
+	}
+
+	fmt.Println(utils.Separator("=", 50))
+	if c.Bool("dry-run") {
+		utils.Success("Found %d duplicate groups", len(groups))
+		return nil
+	}
+
+	utils.Success("Merged %d of %d duplicate groups", merged, len(groups))
+	if merged > 0 {
+		utils.Info("Removed files were backed up to %s", asset.GetAssetBackupDir(project.ProjectPath))
+	}
+
+	return nil
+}
+
+// watchAssets watches the assets directory and regenerates the Dart asset file on change
+func watchAssets(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForAsset()
+	if err != nil {
+		return err
+	}
+
+	// Bring the Dart file and pubspec.yaml up to date before watching
+	if err := asset.EnsureAssetDirExists(project.ProjectPath); err != nil {
+		utils.Error("Failed to create asset directory: %v", err)
+		return err
+	}
+
+	if err := asset.RefreshAssets(project.ProjectPath); err != nil {
+		utils.Warning("Failed to regenerate assets: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dartFilePath := filepath.Join(project.ProjectPath, "lib", "config", "asset.dart")
+	utils.Info("Watching %s for changes...", asset.GetAssetDir(project.ProjectPath))
+	utils.Info("Press Ctrl+C to stop watching")
+
+	err = asset.WatchAssets(ctx, project.ProjectPath, asset.WatchOptions{
+		Interval: c.Duration("interval"),
+		Debounce: c.Duration("debounce"),
+		OnChange: func(result *asset.WatchResult) {
+			for _, path := range result.Changed {
+				utils.Log("  - %s", path)
+			}
+
+			if result.Err != nil {
+				utils.Error("%v", result.Err)
+				return
+			}
+
+			utils.Success("Regenerated %s (%d changes)", dartFilePath, len(result.Changed))
+		},
+	})
+	if err != nil {
+		utils.Error("Asset watcher stopped: %v", err)
+		return err
 	}
 
+	utils.Info("Stopped watching assets")
 	return nil
 }
@@ -0,0 +1,252 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Jerinji2016/fdawg/pkg/brand"
+	"github.com/Jerinji2016/fdawg/pkg/identity"
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// BrandCommand returns the CLI command for managing white-label brands
+func BrandCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "brand",
+		Usage:       "Manage white-label brand profiles",
+		Description: "Commands for switching the project between brands declared in brands/<name>/brand.yaml - names, bundle IDs, icon, splash, environment and asset overrides",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "list",
+				Usage:       "List all brands",
+				Description: "Lists the brands in the brands directory and marks the active one",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the brands as JSON",
+					},
+				},
+				Action: listBrands,
+			},
+			{
+				Name:        "apply",
+				Usage:       "Switch the project to a brand",
+				Description: "Sets the brand's names and bundle IDs, points the launcher icon and splash configs at its images, and copies its asset overrides over assets/. The previous brand's asset overrides are put back first. If any step fails, all changes are rolled back",
+				ArgsUsage:   "<name>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show what would change without writing anything",
+					},
+				},
+				Action: applyBrand,
+			},
+			{
+				Name:        "diff",
+				Usage:       "Show what applying a brand would change",
+				Description: "Compares the working tree with a brand and lists every name, bundle ID, icon, splash, environment and asset that would change",
+				ArgsUsage:   "<name>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the changes as JSON",
+					},
+				},
+				Action: diffBrand,
+			},
+		},
+	}
+}
+
+// listBrands lists the project's brands
+func listBrands(c *cli.Context) error {
+	project, err := validateFlutterProjectForNamer()
+	if err != nil {
+		return err
+	}
+
+	brands, err := brand.ListBrands(project.ProjectPath)
+	if err != nil {
+		utils.Error("Failed to list brands: %v", err)
+		return err
+	}
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(brands, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode brands: %v", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	if len(brands) == 0 {
+		utils.Info("No brands found. Create %s/<name>/%s to add one", brand.BrandsDirName, brand.BrandFileName)
+		return nil
+	}
+
+	fmt.Println(utils.Separator("=", 50))
+	utils.Info("Brands")
+	fmt.Println(utils.Separator("=", 50))
+	for _, info := range brands {
+		marker := " "
+		if info.Active {
+			marker = "*"
+		}
+		if info.Error != "" {
+			fmt.Printf("%s %-16s invalid: %s\n", marker, info.Name, info.Error)
+			continue
+		}
+		fmt.Printf("%s %-16s %-24s %s\n", marker, info.Name, info.AppName, info.BundleID)
+	}
+	return nil
+}
+
+// applyBrand switches the project to a brand, or shows what would change
+func applyBrand(c *cli.Context) error {
+	project, err := validateFlutterProjectForNamer()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() == 0 {
+		utils.Error("Brand name is required")
+		utils.Info("Usage: fdawg brand apply <name>")
+		return fmt.Errorf("brand name is required")
+	}
+	name := c.Args().Get(0)
+
+	if c.Bool("dry-run") {
+		plan, err := brand.PlanBrand(project.ProjectPath, name)
+		if err != nil {
+			utils.Error("%v", err)
+			return err
+		}
+		displayBrandChanges(plan)
+		if len(plan.Changes) > 0 {
+			utils.Info("Dry run: run without --dry-run to apply")
+		}
+		return nil
+	}
+
+	return switchBrand(project.ProjectPath, name)
+}
+
+// switchBrand applies a brand and reports what changed
+func switchBrand(projectPath, name string) error {
+	plan, err := brand.ApplyBrand(projectPath, name)
+	if err != nil {
+		utils.Error("Failed to apply brand %s: %v", name, err)
+		return err
+	}
+
+	displayBrandChanges(plan)
+	if len(plan.Changes) == 0 {
+		return nil
+	}
+
+	utils.Success("Brand %s applied", name)
+	for _, change := range plan.Changes {
+		if change.Kind == brand.KindIcon || change.Kind == brand.KindSplash {
+			utils.Info("Run 'dart run flutter_launcher_icons' and 'dart run flutter_native_splash:create' to regenerate the icons and splash screens, or build with 'fdawg build run'")
+			break
+		}
+	}
+	return nil
+}
+
+// diffBrand shows what applying a brand would change
+func diffBrand(c *cli.Context) error {
+	project, err := validateFlutterProjectForNamer()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() == 0 {
+		utils.Error("Brand name is required")
+		utils.Info("Usage: fdawg brand diff <name>")
+		return fmt.Errorf("brand name is required")
+	}
+
+	plan, err := brand.PlanBrand(project.ProjectPath, c.Args().Get(0))
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode changes: %v", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	displayBrandChanges(plan)
+	return nil
+}
+
+// displayBrandChanges prints a brand plan's changes, grouped by kind
+func displayBrandChanges(plan *brand.Plan) {
+	for _, warning := range plan.Warnings {
+		utils.Warning("%s", warning)
+	}
+
+	if len(plan.Changes) == 0 {
+		utils.Success("The project already matches brand %s", plan.Brand)
+		return
+	}
+
+	if plan.Previous != "" && plan.Previous != plan.Brand {
+		utils.Info("Switching from brand %s to %s", plan.Previous, plan.Brand)
+	}
+
+	sections := []struct {
+		kind  string
+		title string
+	}{
+		{identity.FieldName, "App names"},
+		{identity.FieldBundleID, "Bundle IDs"},
+		{identity.FieldAndroidSource, "Android sources"},
+		{brand.KindIcon, "Launcher icon (" + brand.LauncherIconsFileName + ")"},
+		{brand.KindSplash, "Splash screen (" + brand.NativeSplashFileName + ")"},
+		{brand.KindEnvironment, "Build environment"},
+		{brand.KindAsset, "Assets"},
+	}
+
+	for _, section := range sections {
+		var changes []brand.Change
+		for _, change := range plan.Changes {
+			if change.Kind == section.kind {
+				changes = append(changes, change)
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		fmt.Println(utils.Separator("=", 50))
+		utils.Info("%s", section.title)
+		fmt.Println(utils.Separator("=", 50))
+		for _, change := range changes {
+			switch change.Kind {
+			case brand.KindAsset:
+				fmt.Printf("  %s ← %s\n", change.Target, change.To)
+			case identity.FieldAndroidSource, brand.KindEnvironment:
+				fmt.Printf("  %s → %s\n", displayValue(change.From), displayValue(change.To))
+			default:
+				fmt.Printf("  %-10s %s → %s\n", change.Target+":", displayValue(change.From), displayValue(change.To))
+			}
+		}
+	}
+}
+
+// displayValue shows empty values as (none)
+func displayValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
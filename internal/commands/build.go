@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Jerinji2016/fdawg/pkg/brand"
 	"github.com/Jerinji2016/fdawg/pkg/build"
 	"github.com/Jerinji2016/fdawg/pkg/environment"
 	"github.com/Jerinji2016/fdawg/pkg/flutter"
@@ -57,6 +58,11 @@ func BuildCommand() *cli.Command {
 						Aliases: []string{"e"},
 						Usage:   "Environment to use for build (uses --dart-define-from-file)",
 					},
+					&cli.StringFlag{
+						Name:    "brand",
+						Aliases: []string{"b"},
+						Usage:   "Apply a brand from brands/<name> before building (its environment is used unless --env is given)",
+					},
 				},
 				Action: runBuild,
 			},
@@ -155,8 +161,31 @@ func runBuild(c *cli.Context) error {
 		return err
 	}
 
-	// Validate environment if specified
+	// Apply brand if specified
 	envName := c.String("env")
+	if brandName := c.String("brand"); brandName != "" {
+		if c.Bool("dry-run") {
+			plan, err := brand.PlanBrand(project.ProjectPath, brandName)
+			if err != nil {
+				utils.Error("%v", err)
+				return err
+			}
+			utils.Info("Dry run mode - brand %s would make these changes:", brandName)
+			displayBrandChanges(plan)
+		} else if err := switchBrand(project.ProjectPath, brandName); err != nil {
+			return err
+		}
+
+		if envName == "" {
+			selected, err := brand.LoadBrand(project.ProjectPath, brandName)
+			if err != nil {
+				return err
+			}
+			envName = selected.Environment
+		}
+	}
+
+	// Validate environment if specified
 	if envName != "" {
 		if err := validateEnvironment(project.ProjectPath, envName); err != nil {
 			utils.Error("Environment validation failed: %v", err)
@@ -178,7 +207,7 @@ func runBuild(c *cli.Context) error {
 		ContinueOnError: c.Bool("continue-on-error"),
 		DryRun:          c.Bool("dry-run"),
 		Parallel:        c.Bool("parallel"),
-		Environment:     c.String("env"),
+		Environment:     envName,
 	}
 
 	if options.DryRun {
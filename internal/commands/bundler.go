@@ -69,6 +69,10 @@ func BundlerCommand() *cli.Command {
 						Name:  "web",
 						Usage: "Bundle ID for Web platform",
 					},
+					&cli.BoolFlag{
+						Name:  "move-android-package",
+						Usage: "Move the Android Kotlin/Java sources to the new bundle ID's package and update their package declarations and manifest references",
+					},
 				},
 				Action: setBundleIDs,
 			},
@@ -168,9 +172,10 @@ func setBundleIDs(c *cli.Context) error {
 
 	// Build request
 	request := &bundler.BundleIDRequest{
-		ProjectPath: project.ProjectPath,
-		Universal:   c.String("value"),
-		Platforms:   make(map[bundler.Platform]string),
+		ProjectPath:        project.ProjectPath,
+		Universal:          c.String("value"),
+		Platforms:          make(map[bundler.Platform]string),
+		MoveAndroidPackage: c.Bool("move-android-package"),
 	}
 
 	// Add platform-specific bundle IDs
@@ -224,7 +229,7 @@ func setBundleIDs(c *cli.Context) error {
 	}
 
 	for _, bundleID := range bundleIDsToValidate {
-		if err := validateBundleIDFormat(bundleID); err != nil {
+		if err := bundler.ValidateBundleID(bundleID); err != nil {
 			utils.Error("Invalid bundle ID format: %v", err)
 			return err
 		}
@@ -280,7 +285,7 @@ func validateBundleID(c *cli.Context) error {
 
 	bundleID := c.Args().Get(0)
 
-	if err := validateBundleIDFormat(bundleID); err != nil {
+	if err := bundler.ValidateBundleID(bundleID); err != nil {
 		utils.Error("Invalid bundle ID: %v", err)
 		return err
 	}
@@ -318,60 +323,3 @@ func displayBundleIDs(result *bundler.BundleIDResult) {
 	utils.Info("")
 	utils.Info("Platforms found: %d/%d", availableCount, len(result.BundleIDs))
 }
-
-// validateBundleIDFormat validates bundle ID format and naming conventions
-func validateBundleIDFormat(bundleID string) error {
-	if bundleID == "" {
-		return fmt.Errorf("bundle ID cannot be empty")
-	}
-
-	// Check for reverse domain notation (at least one dot)
-	if !strings.Contains(bundleID, ".") {
-		return fmt.Errorf("bundle ID should follow reverse domain notation (e.g., com.company.app)")
-	}
-
-	// Check for valid characters (alphanumeric, dots, hyphens, underscores)
-	for _, char := range bundleID {
-		if !((char >= 'a' && char <= 'z') ||
-			(char >= 'A' && char <= 'Z') ||
-			(char >= '0' && char <= '9') ||
-			char == '.' || char == '-' || char == '_') {
-			return fmt.Errorf("bundle ID contains invalid character: %c. Only alphanumeric characters, dots, hyphens, and underscores are allowed", char)
-		}
-	}
-
-	// Check that it doesn't start or end with a dot
-	if strings.HasPrefix(bundleID, ".") || strings.HasSuffix(bundleID, ".") {
-		return fmt.Errorf("bundle ID cannot start or end with a dot")
-	}
-
-	// Check for consecutive dots
-	if strings.Contains(bundleID, "..") {
-		return fmt.Errorf("bundle ID cannot contain consecutive dots")
-	}
-
-	// Check minimum length
-	if len(bundleID) < 3 {
-		return fmt.Errorf("bundle ID is too short (minimum 3 characters)")
-	}
-
-	// Check maximum length (reasonable limit)
-	if len(bundleID) > 255 {
-		return fmt.Errorf("bundle ID is too long (maximum 255 characters)")
-	}
-
-	// Split by dots and validate each segment
-	segments := strings.Split(bundleID, ".")
-	for i, segment := range segments {
-		if segment == "" {
-			return fmt.Errorf("bundle ID segment %d is empty", i+1)
-		}
-
-		// Each segment should not start with a number (Java package naming convention)
-		if len(segment) > 0 && segment[0] >= '0' && segment[0] <= '9' {
-			return fmt.Errorf("bundle ID segment '%s' cannot start with a number", segment)
-		}
-	}
-
-	return nil
-}
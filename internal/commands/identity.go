@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Jerinji2016/fdawg/pkg/identity"
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// IdentityCommand returns the CLI command for managing the app's identity
+func IdentityCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "identity",
+		Usage:       "Manage the app's names, bundle IDs and package name together",
+		Description: "Commands for applying a whole app identity - display names, bundle IDs, the pubspec package name and Dart package: imports - in one step",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "show",
+				Usage:       "Show the current identity",
+				Description: "Prints the project's current identity in identity.yaml format, as a starting point for a new identity file",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the identity as JSON",
+					},
+				},
+				Action: showIdentity,
+			},
+			{
+				Name:        "apply",
+				Usage:       "Apply an identity file",
+				Description: "Sets the names, bundle IDs and package name from an identity file and rewrites package: imports. All files are backed up to .fdawg-backups/identity first and restored if any step fails",
+				ArgsUsage:   "[identity.yaml]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show what would change without writing anything",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the changes as JSON",
+					},
+				},
+				Action: applyIdentity,
+			},
+		},
+	}
+}
+
+// showIdentity prints the project's current identity
+func showIdentity(c *cli.Context) error {
+	project, err := validateFlutterProjectForNamer()
+	if err != nil {
+		return err
+	}
+
+	current, err := identity.GetIdentity(project.ProjectPath)
+	if err != nil {
+		utils.Error("Failed to read identity: %v", err)
+		return err
+	}
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode identity: %v", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(current); err != nil {
+		return fmt.Errorf("failed to encode identity: %v", err)
+	}
+	return encoder.Close()
+}
+
+// applyIdentity applies an identity file, or shows what it would change
+func applyIdentity(c *cli.Context) error {
+	project, err := validateFlutterProjectForNamer()
+	if err != nil {
+		return err
+	}
+
+	path := identity.DefaultFileName
+	if c.Args().Len() > 0 {
+		path = c.Args().Get(0)
+	}
+
+	wanted, err := identity.LoadIdentity(path)
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	var plan *identity.Plan
+	if c.Bool("dry-run") {
+		plan, err = identity.PlanIdentity(project.ProjectPath, wanted)
+	} else {
+		plan, err = identity.ApplyIdentity(project.ProjectPath, wanted)
+	}
+	if err != nil {
+		utils.Error("Failed to apply identity: %v", err)
+		return err
+	}
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode changes: %v", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	for _, warning := range plan.Warnings {
+		utils.Warning("%s", warning)
+	}
+
+	if len(plan.Changes) == 0 {
+		utils.Success("The project already has this identity")
+		return nil
+	}
+
+	displayIdentityChanges(plan)
+
+	if c.Bool("dry-run") {
+		utils.Info("Dry run: %d files would change. Run without --dry-run to apply", len(plan.Files))
+		return nil
+	}
+
+	utils.Success("Identity applied. Backups of %d files are in .fdawg-backups/identity", len(plan.Files))
+	for _, change := range plan.Changes {
+		if change.Field == identity.FieldPackage {
+			utils.Info("Run 'flutter pub get' to pick up the new package name")
+			break
+		}
+	}
+	return nil
+}
+
+// displayIdentityChanges prints an identity plan's changes, grouped by kind
+func displayIdentityChanges(plan *identity.Plan) {
+	sections := []struct {
+		field string
+		title string
+	}{
+		{identity.FieldName, "App names"},
+		{identity.FieldBundleID, "Bundle IDs"},
+		{identity.FieldAndroidSource, "Android sources"},
+		{identity.FieldPackage, "Package name"},
+		{identity.FieldImports, "Dart imports"},
+	}
+
+	for _, section := range sections {
+		var changes []identity.Change
+		for _, change := range plan.Changes {
+			if change.Field == section.field {
+				changes = append(changes, change)
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		fmt.Println(utils.Separator("=", 50))
+		utils.Info("%s", section.title)
+		fmt.Println(utils.Separator("=", 50))
+		for _, change := range changes {
+			switch change.Field {
+			case identity.FieldAndroidSource:
+				fmt.Printf("  %s → %s\n", change.From, change.To)
+			case identity.FieldImports:
+				fmt.Printf("  %s: %d imports\n", change.File, change.Count)
+			case identity.FieldPackage:
+				fmt.Printf("  %s → %s\n", change.From, change.To)
+			default:
+				fmt.Printf("  %-8s %s → %s\n", change.Platform+":", change.From, change.To)
+			}
+		}
+	}
+}
@@ -2,13 +2,17 @@ package commands
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/Jerinji2016/fdawg/pkg/flutter"
 	"github.com/Jerinji2016/fdawg/pkg/localization"
+	"github.com/Jerinji2016/fdawg/pkg/namer"
+	"github.com/Jerinji2016/fdawg/pkg/translate"
 	"github.com/Jerinji2016/fdawg/pkg/utils"
 	"github.com/urfave/cli/v2"
 )
@@ -18,13 +22,20 @@ func LangCommand() *cli.Command {
 	return &cli.Command{
 		Name:        "lang",
 		Usage:       "Manage localizations for Flutter projects",
-		Description: "Commands for managing localizations using easy_localization",
+		Description: "Commands for managing localizations using easy_localization or Flutter's gen-l10n ARB files. The backend is detected from the project",
 		Subcommands: []*cli.Command{
 			{
 				Name:        "init",
 				Usage:       "Initialize localization in the project",
-				Description: "Initializes localization using easy_localization package",
-				Action:      initLocalization,
+				Description: "Initializes localization using the easy_localization package or gen-l10n ARB files",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "backend",
+						Aliases: []string{"b"},
+						Usage:   fmt.Sprintf("Localization backend to set up (%s); detected from the project if not set", strings.Join(localization.BackendNames(), ", ")),
+					},
+				},
+				Action: initLocalization,
 			},
 			{
 				Name:        "add",
@@ -43,9 +54,23 @@ func LangCommand() *cli.Command {
 			{
 				Name:        "insert",
 				Usage:       "Add a new translation key",
-				Description: "Adds a new translation key to all languages",
+				Description: "Adds a new translation key to all languages. Use --plural or --gender to enter each form the language needs",
 				ArgsUsage:   "<key>",
-				Action:      insertTranslationKey,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "plural",
+						Usage: "Add a plural message with the CLDR plural forms of each language",
+					},
+					&cli.BoolFlag{
+						Name:  "gender",
+						Usage: "Add a gender message with male, female and other forms",
+					},
+					&cli.StringFlag{
+						Name:  "argument",
+						Usage: "ICU argument name for ARB plural and gender messages (default: count or gender)",
+					},
+				},
+				Action: insertTranslationKey,
 			},
 			{
 				Name:        "delete",
@@ -54,6 +79,196 @@ func LangCommand() *cli.Command {
 				ArgsUsage:   "<key>",
 				Action:      deleteTranslationKey,
 			},
+			{
+				Name:        "rename",
+				Usage:       "Rename or move a translation key",
+				Description: "Renames a translation key, or a subtree of keys such as home to screens.home, in all languages at once",
+				ArgsUsage:   "<old key> <new key>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "update-code",
+						Usage: "Also rewrite references to the renamed keys in lib/**/*.dart",
+					},
+				},
+				Action: renameTranslationKey,
+			},
+			{
+				Name:        "plurals",
+				Usage:       "Check plural and gender forms",
+				Description: "Checks that every plural and gender message has the forms CLDR requires for each language",
+				Action:      checkPluralForms,
+			},
+			{
+				Name:        "validate",
+				Usage:       "Check translations against the default language",
+				Description: "Reports missing, extra or renamed placeholders, empty values and values identical to the default language. Exits with an error if placeholder errors are found",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the report as JSON, e.g. for CI",
+					},
+					&cli.BoolFlag{
+						Name:  "strict",
+						Usage: "Also fail on warnings such as empty or untranslated values",
+					},
+				},
+				Action: validateTranslations,
+			},
+			{
+				Name:        "export",
+				Usage:       "Export translations for translators",
+				Description: "Exports translations as CSV, XLSX or XLIFF with nested keys flattened to dot paths",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Value:   localization.ExportCSV,
+						Usage:   fmt.Sprintf("Export format (%s)", strings.Join(localization.ExportFormats(), ", ")),
+					},
+					&cli.BoolFlag{
+						Name:  "missing-only",
+						Usage: "Only export keys missing a translation in at least one language",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file (default: translations.<format>)",
+					},
+				},
+				Action: exportTranslations,
+			},
+			{
+				Name:        "import",
+				Usage:       "Import translations from translators",
+				Description: "Merges translations from a CSV, XLSX or XLIFF file. Empty values are ignored, values that differ from existing ones are reported as conflicts, and keys not in the file are left untouched",
+				ArgsUsage:   "<file>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "overwrite",
+						Usage: "Replace existing values that differ from the imported ones",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Report what would change without writing any files",
+					},
+				},
+				Action: importTranslations,
+			},
+			{
+				Name:        "audit",
+				Usage:       "Find unused and missing translation keys",
+				Description: "Scans lib/**/*.dart for easy_localization usages ('key'.tr(), tr('key'), context.tr('key'), LocaleKeys.xxx) and reports keys that are never used and keys missing from the default language",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "prune",
+						Usage: "Delete unused keys from all languages",
+					},
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "Prune without asking for confirmation",
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Prune even when code builds keys at runtime or translates keys held in variables",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the report as JSON",
+					},
+				},
+				Action: auditTranslationKeys,
+			},
+			{
+				Name:        "translate",
+				Usage:       "Machine translate missing values",
+				Description: "Fills in every empty or missing value from the default language with a machine translation, using Google Translate, DeepL, LibreTranslate or a JSON fixture",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "missing",
+						Usage: "Translate values that are empty or missing",
+					},
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "Comma-separated languages to fill in (default: all but the default language)",
+					},
+					&cli.StringFlag{
+						Name:  "provider",
+						Usage: "Translation provider: google, deepl, libretranslate or fixture (default: from .fdawg-config)",
+					},
+					&cli.StringFlag{
+						Name:  "fixture",
+						Usage: "JSON file of canned translations, for the fixture provider",
+					},
+					&cli.Float64Flag{
+						Name:  "rate",
+						Usage: "Maximum requests per second",
+						Value: 5,
+					},
+					&cli.IntFlag{
+						Name:  "retries",
+						Usage: "Retries for rate limited or failed requests",
+						Value: 3,
+					},
+					&cli.IntFlag{
+						Name:  "batch-size",
+						Usage: "Texts sent per request",
+						Value: 50,
+					},
+				},
+				Action: translateMissing,
+			},
+			langMemoryCommand(),
+			langGlossaryCommand(),
+			langReviewCommand(),
+			langExtractCommand(),
+			{
+				Name:        "pseudo",
+				Usage:       "Generate pseudo-locale files for UI testing",
+				Description: "Generates en_XA from the default language with accented, padded and bracketed text, to catch truncation and hard-coded strings. With --rtl, also generates ar_XB with mirrored text for right-to-left layouts. Placeholders are kept intact",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "padding",
+						Usage: "Percentage of extra length added to each en_XA string",
+						Value: localization.DefaultPseudoPadding,
+					},
+					&cli.BoolFlag{
+						Name:  "rtl",
+						Usage: "Also generate the right-to-left ar_XB pseudo-locale",
+					},
+				},
+				Action: generatePseudoLocales,
+			},
+			{
+				Name:        "sync",
+				Usage:       "Rebuild translation files with the default language's keys and order",
+				Description: "Rebuilds every non-default translation file with the default language's key tree and order. Existing values are kept, missing keys and plural forms are added, and keys the default language doesn't have are moved to .fdawg/orphaned_translations.json",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "Comma-separated languages to sync (default: all but the default language)",
+					},
+					&cli.BoolFlag{
+						Name:  "use-source",
+						Usage: "Fill missing keys with the default language's text, marked as needing review, instead of empty values",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show what would change without writing anything",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the result as JSON",
+					},
+				},
+				Action: syncTranslations,
+			},
+			{
+				Name:        "generate-dart",
+				Usage:       "Generate the LocaleKeys Dart class",
+				Description: "Generates lib/config/locale_keys.dart with a LocaleKeys class for the keys in the default language, with typed helpers for keys with placeholders, plural or gender forms",
+				Action:      generateDartLocaleKeys,
+			},
 			{
 				Name:        "list",
 				Usage:       "List supported languages",
@@ -89,19 +304,30 @@ func initLocalization(c *cli.Context) error {
 		return err
 	}
 
+	backend := localization.DetectBackend(project.ProjectPath)
+	if name := c.String("backend"); name != "" {
+		backend, err = localization.NewBackend(project.ProjectPath, name)
+		if err != nil {
+			utils.Error("%v", err)
+			return err
+		}
+	}
+
 	// Initialize localization
-	utils.Info("Initializing localization using easy_localization...")
+	utils.Info("Initializing localization using %s...", backend.Name())
 
-	err = localization.InitLocalization(project.ProjectPath)
+	err = backend.Init()
 	if err != nil {
 		utils.Error("Failed to initialize localization: %v", err)
 		return err
 	}
 
+	translationsDir, _ := filepath.Rel(project.ProjectPath, backend.TranslationsDir())
+
 	utils.Success("Localization initialized successfully")
-	utils.Info("Default language: en_US")
-	utils.Info("Translations directory: %s", localization.TranslationsDir)
-	utils.Info("Run 'flutter pub get' to install the easy_localization package")
+	utils.Info("Default language: %s", backend.DefaultLanguage())
+	utils.Info("Translations directory: %s", filepath.ToSlash(translationsDir))
+	utils.Info("%s", backend.SetupHint())
 
 	return nil
 }
@@ -204,6 +430,13 @@ func removeLanguage(c *cli.Context) error {
 		return err
 	}
 
+	// App names localized for the language go with it
+	if result, err := namer.RemoveLocalizedAppNames(project.ProjectPath, langCode); err != nil {
+		utils.Warning("Failed to remove localized app names: %v", err)
+	} else if len(result.Files) > 0 {
+		utils.Info("Removed the %s app names from %s", result.Locale, strings.Join(result.Files, ", "))
+	}
+
 	utils.Success("Language %s (%s) removed successfully", langInfo.DisplayName(), langInfo.String())
 	return nil
 }
@@ -226,6 +459,12 @@ func insertTranslationKey(c *cli.Context) error {
 
 	key := c.Args().First()
 
+	// Validate the key before asking for values
+	if err := localization.ValidateTranslationKey(project.ProjectPath, key); err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
 	// Get all translation files
 	translationFiles, err := localization.ListTranslationFiles(project.ProjectPath)
 	if err != nil {
@@ -239,9 +478,22 @@ func insertTranslationKey(c *cli.Context) error {
 		return fmt.Errorf("no translation files found")
 	}
 
+	reader := bufio.NewReader(os.Stdin)
+
+	if c.Bool("plural") && c.Bool("gender") {
+		utils.Error("Use either --plural or --gender, not both")
+		return fmt.Errorf("--plural and --gender cannot be used together")
+	}
+	if c.Bool("plural") || c.Bool("gender") {
+		kind := localization.MessagePlural
+		if c.Bool("gender") {
+			kind = localization.MessageGender
+		}
+		return insertMessageKey(project.ProjectPath, key, kind, c.String("argument"), translationFiles, reader)
+	}
+
 	// Collect values for each language
 	values := make(map[string]string)
-	reader := bufio.NewReader(os.Stdin)
 
 	utils.Info("Enter translation values for each language:")
 	for _, file := range translationFiles {
@@ -261,6 +513,36 @@ func insertTranslationKey(c *cli.Context) error {
 	}
 
 	utils.Success("Translation key %s added successfully", key)
+
+	regenerateLocaleKeys(project.ProjectPath)
+	return nil
+}
+
+// insertMessageKey prompts for each plural or gender form a language needs and adds the key
+func insertMessageKey(projectPath, key, kind, argument string, translationFiles []localization.TranslationFile, reader *bufio.Reader) error {
+	messages := make(map[string]localization.Message)
+
+	utils.Info("Enter the %s forms for each language:", kind)
+	for _, file := range translationFiles {
+		message := localization.Message{Kind: kind, Forms: make(map[string]string), Argument: argument}
+		for _, form := range localization.RequiredForms(kind, file.Language) {
+			fmt.Printf("%s (%s) [%s]: ", file.Language, key, form)
+			value, _ := reader.ReadString('\n')
+			message.Forms[form] = strings.TrimSpace(value)
+		}
+		messages[file.Language] = message
+	}
+
+	utils.Info("Adding %s key %s to all languages...", kind, key)
+
+	if err := localization.SetTranslationMessages(projectPath, key, messages); err != nil {
+		utils.Error("Failed to add translation key: %v", err)
+		return err
+	}
+
+	utils.Success("Translation key %s added successfully", key)
+
+	regenerateLocaleKeys(projectPath)
 	return nil
 }
 
@@ -302,6 +584,530 @@ func deleteTranslationKey(c *cli.Context) error {
 	}
 
 	utils.Success("Translation key %s deleted successfully", key)
+
+	regenerateLocaleKeys(project.ProjectPath)
+	return nil
+}
+
+// renameTranslationKey renames a translation key or subtree in all languages
+func renameTranslationKey(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 2 {
+		utils.Error("Old and new keys are required")
+		utils.Info("Usage: fdawg lang rename [--update-code] <old key> <new key>")
+		utils.Info("Example: fdawg lang rename --update-code home screens.home")
+		return fmt.Errorf("old and new keys are required")
+	}
+
+	oldKey := c.Args().Get(0)
+	newKey := c.Args().Get(1)
+
+	utils.Info("Renaming %s to %s in all languages...", oldKey, newKey)
+
+	result, err := localization.RenameTranslationKey(project.ProjectPath, oldKey, newKey, c.Bool("update-code"))
+	if err != nil {
+		utils.Error("Failed to rename translation key: %v", err)
+		return err
+	}
+
+	for _, key := range result.Keys {
+		fmt.Printf("  %s -> %s\n", key.From, key.To)
+	}
+	utils.Success("Renamed %d key(s) in %s", len(result.Keys), strings.Join(result.Languages, ", "))
+
+	if c.Bool("update-code") {
+		if len(result.CodeFiles) == 0 {
+			if result.CodeError == "" {
+				utils.Info("No references found in lib/")
+			}
+		} else {
+			utils.Success("Rewrote %d reference(s) in %d Dart file(s)", result.CodeReferences, len(result.CodeFiles))
+			for _, file := range result.CodeFiles {
+				fmt.Printf("  %s\n", file)
+			}
+		}
+		if len(result.Candidates) > 0 {
+			utils.Warning("%d possible reference(s) left unchanged, as they aren't read from AppLocalizations; rename them by hand if they are:", len(result.Candidates))
+			for _, usage := range result.Candidates {
+				fmt.Printf("  %s:%d\n", usage.File, usage.Line)
+			}
+		}
+	} else {
+		utils.Info("Run with --update-code to also rewrite references in lib/**/*.dart")
+	}
+
+	regenerateLocaleKeys(project.ProjectPath)
+
+	if result.CodeError != "" {
+		utils.Error("The key was renamed, but updating references in lib/ failed: %s", result.CodeError)
+		utils.Info("Update the remaining references to %s by hand", oldKey)
+		return fmt.Errorf("failed to update code references: %s", result.CodeError)
+	}
+	return nil
+}
+
+// translateMissing fills in missing values with machine translations
+func translateMissing(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	if !c.Bool("missing") {
+		utils.Error("Specify which values to translate")
+		utils.Info("Usage: fdawg lang translate --missing [--to fr,de]")
+		return fmt.Errorf("--missing is required")
+	}
+
+	config, err := translate.LoadConfig(project.ProjectPath)
+	if err != nil {
+		utils.Error("Failed to load translation config: %v", err)
+		return err
+	}
+
+	// Flags override the provider saved in .fdawg-config
+	if c.IsSet("fixture") {
+		config.Provider = translate.ProviderFixture
+		config.FixturePath = c.String("fixture")
+	}
+	if c.IsSet("provider") {
+		config.Provider = c.String("provider")
+	}
+	config.Enabled = true
+
+	if !config.IsEnabled() {
+		utils.Error("Translation provider %s is not configured", config.ProviderName())
+		utils.Info("Set an API key in the web interface or in .fdawg-config, or use --provider libretranslate")
+		return fmt.Errorf("translation provider %s is not configured", config.ProviderName())
+	}
+
+	service, err := translate.NewService(config)
+	if err != nil {
+		utils.Error("Failed to create translation service: %v", err)
+		return err
+	}
+
+	rate := c.Float64("rate")
+	if !c.IsSet("rate") && config.RequestsPerSecond > 0 {
+		rate = config.RequestsPerSecond
+	}
+	service.SetRateLimit(rate)
+	service.SetRetries(c.Int("retries"))
+
+	var languages []string
+	for _, language := range strings.Split(c.String("to"), ",") {
+		if language = strings.TrimSpace(language); language != "" {
+			languages = append(languages, language)
+		}
+	}
+
+	utils.Info("Translating missing values with %s...", service.ProviderName())
+
+	progress := utils.NewProgressBar("Translating", 30)
+	result, err := localization.TranslateMissing(project.ProjectPath, service, localization.MachineTranslateOptions{
+		Languages: languages,
+		BatchSize: c.Int("batch-size"),
+		Progress:  progress.Update,
+	})
+	progress.Finish()
+	if err != nil {
+		utils.Error("Failed to translate missing values: %v", err)
+		return err
+	}
+
+	total := 0
+	for _, count := range result.Translated {
+		total += count
+	}
+
+	if total == 0 && len(result.Failures) == 0 {
+		utils.Success("No missing values to translate")
+		return nil
+	}
+
+	for _, language := range sortedKeys(result.Translated) {
+		utils.Info("%s: %d value(s) translated", language, result.Translated[language])
+	}
+
+	if len(result.Issues) > 0 {
+		fmt.Println(utils.Separator("=", 50))
+		utils.Warning("Placeholder Problems (%d)", len(result.Issues))
+		fmt.Println(utils.Separator("=", 50))
+		for _, issue := range result.Issues {
+			fmt.Printf("- %s [%s]: %s\n", issue.Key, issue.Language, issue.Message)
+		}
+		fmt.Println(utils.Separator("=", 50))
+		utils.Info("Fix these by hand, then check with 'fdawg lang validate'")
+	}
+
+	if len(result.Failures) > 0 {
+		fmt.Println(utils.Separator("=", 50))
+		utils.Error("Failed (%d)", len(result.Failures))
+		fmt.Println(utils.Separator("=", 50))
+		for _, failure := range result.Failures {
+			fmt.Printf("- %s [%s]: %s\n", failure.Key, failure.Language, failure.Error)
+		}
+		fmt.Println(utils.Separator("=", 50))
+	}
+
+	if total > 0 {
+		utils.Success("Translated %d missing value(s) from %s", total, result.SourceLanguage)
+	}
+
+	if len(result.Failures) > 0 {
+		return fmt.Errorf("%d value(s) could not be translated", len(result.Failures))
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// generateDartLocaleKeys generates the LocaleKeys Dart class
+func generateDartLocaleKeys(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	utils.Info("Generating Dart LocaleKeys file...")
+
+	if err := localization.GenerateDartLocaleKeysFile(project.ProjectPath); err != nil {
+		utils.Error("Failed to generate Dart LocaleKeys file: %v", err)
+		return err
+	}
+
+	dartFilePath := filepath.Join(project.ProjectPath, filepath.FromSlash(localization.LocaleKeysFile))
+	utils.Success("Dart LocaleKeys file generated successfully at %s", dartFilePath)
+	return nil
+}
+
+// regenerateLocaleKeys keeps the LocaleKeys class in sync after keys are added or
+// deleted. gen-l10n projects generate their own AppLocalizations class.
+func regenerateLocaleKeys(projectPath string) {
+	if localization.DetectBackend(projectPath).Name() != localization.BackendEasyLocalization {
+		return
+	}
+
+	utils.Info("Generating Dart LocaleKeys file...")
+
+	if err := localization.GenerateDartLocaleKeysFile(projectPath); err != nil {
+		utils.Warning("Failed to generate Dart LocaleKeys file: %v", err)
+	} else {
+		dartFilePath := filepath.Join(projectPath, filepath.FromSlash(localization.LocaleKeysFile))
+		utils.Success("Dart LocaleKeys file generated successfully at %s", dartFilePath)
+	}
+}
+
+// checkPluralForms reports plural and gender messages missing forms a language needs
+func checkPluralForms(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	issues, err := localization.ValidatePluralForms(project.ProjectPath)
+	if err != nil {
+		utils.Error("Failed to check plural forms: %v", err)
+		return err
+	}
+
+	if len(issues) == 0 {
+		utils.Success("All plural and gender messages have the forms each language needs")
+		return nil
+	}
+
+	fmt.Println(utils.Separator("=", 50))
+	utils.Warning("Plural and Gender Issues")
+	fmt.Println(utils.Separator("=", 50))
+
+	for _, issue := range issues {
+		fmt.Printf("- %s [%s]: %s\n", issue.Key, issue.Language, issue.Message)
+	}
+
+	fmt.Println(utils.Separator("=", 50))
+	utils.Error("Found %d issue(s)", len(issues))
+
+	return fmt.Errorf("%d plural or gender message(s) are missing forms", len(issues))
+}
+
+// validateTranslations checks every translation against the default language
+func validateTranslations(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	report, err := localization.ValidateTranslations(project.ProjectPath)
+	if err != nil {
+		utils.Error("Failed to validate translations: %v", err)
+		return err
+	}
+
+	failed := report.Errors > 0 || (c.Bool("strict") && report.Warnings > 0)
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %v", err)
+		}
+		fmt.Println(string(output))
+
+		if failed {
+			return fmt.Errorf("translation validation failed with %d error(s) and %d warning(s)", report.Errors, report.Warnings)
+		}
+		return nil
+	}
+
+	utils.Info("Checked %d keys in %d languages against %s", report.Keys, len(report.Languages), report.DefaultLanguage)
+
+	if len(report.Issues) == 0 {
+		utils.Success("No issues found")
+		return nil
+	}
+
+	// Group issues by language
+	byLanguage := make(map[string][]localization.ValidationIssue)
+	for _, issue := range report.Issues {
+		byLanguage[issue.Language] = append(byLanguage[issue.Language], issue)
+	}
+
+	for _, language := range report.Languages {
+		issues := byLanguage[language]
+		if len(issues) == 0 {
+			continue
+		}
+
+		fmt.Println(utils.Separator("=", 50))
+		utils.Info("%s (%d issues)", language, len(issues))
+		fmt.Println(utils.Separator("=", 50))
+
+		for _, issue := range issues {
+			if issue.Severity == localization.SeverityError {
+				utils.Error("%s: %s", issue.Key, issue.Message)
+			} else {
+				utils.Warning("%s: %s", issue.Key, issue.Message)
+			}
+		}
+	}
+
+	fmt.Println(utils.Separator("=", 50))
+	utils.Info("%d error(s), %d warning(s)", report.Errors, report.Warnings)
+
+	if failed {
+		return fmt.Errorf("translation validation failed with %d error(s) and %d warning(s)", report.Errors, report.Warnings)
+	}
+	return nil
+}
+
+// exportTranslations writes the project's translations to a CSV, XLSX or XLIFF file
+func exportTranslations(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(c.String("format"))
+	output := c.String("output")
+	if output == "" {
+		output = localization.ExportFileName(format)
+	}
+
+	utils.Info("Exporting translations as %s...", format)
+
+	data, err := localization.ExportTranslations(project.ProjectPath, localization.ExportOptions{
+		Format:      format,
+		MissingOnly: c.Bool("missing-only"),
+	})
+	if err != nil {
+		utils.Error("Failed to export translations: %v", err)
+		return err
+	}
+
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		utils.Error("Failed to write %s: %v", output, err)
+		return err
+	}
+
+	utils.Success("Translations exported to %s", output)
+	return nil
+}
+
+// importTranslations merges translations from a CSV, XLSX or XLIFF file
+func importTranslations(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() == 0 {
+		utils.Error("Import file is required")
+		utils.Info("Usage: fdawg lang import <file>")
+		utils.Info("Example: fdawg lang import translations.xlsx")
+		return fmt.Errorf("import file is required")
+	}
+
+	path := c.Args().First()
+	utils.Info("Importing translations from %s...", path)
+
+	result, err := localization.ImportTranslations(project.ProjectPath, path, localization.ImportOptions{
+		Overwrite: c.Bool("overwrite"),
+		DryRun:    c.Bool("dry-run"),
+	})
+	if err != nil {
+		utils.Error("Failed to import translations: %v", err)
+		return err
+	}
+
+	for _, language := range result.UnknownLanguages {
+		utils.Warning("Skipped language %s, which isn't in the project (add it with 'fdawg lang add')", language)
+	}
+	for _, key := range result.UnknownKeys {
+		utils.Warning("Skipped unknown key %s", key)
+	}
+
+	if len(result.Conflicts) > 0 {
+		fmt.Println(utils.Separator("=", 50))
+		utils.Warning("Conflicts (kept existing values)")
+		fmt.Println(utils.Separator("=", 50))
+		for _, conflict := range result.Conflicts {
+			fmt.Printf("- %s [%s]\n    current:  %q\n    imported: %q\n", conflict.Key, conflict.Language, conflict.Current, conflict.Imported)
+		}
+		fmt.Println(utils.Separator("=", 50))
+		utils.Info("Run again with --overwrite to use the imported values")
+	}
+
+	if result.DryRun {
+		utils.Info("Dry run: %d value(s) would be updated, %d unchanged, %d conflict(s)", result.Updated, result.Unchanged, len(result.Conflicts))
+		return nil
+	}
+
+	utils.Success("Imported %d value(s): %d unchanged, %d conflict(s)", result.Updated, result.Unchanged, len(result.Conflicts))
+
+	if result.Updated > 0 {
+		regenerateLocaleKeys(project.ProjectPath)
+	}
+	return nil
+}
+
+// auditTranslationKeys compares the keys used in Dart code with the translation files
+func auditTranslationKeys(c *cli.Context) error {
+	// Validate Flutter project
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	report, err := localization.AuditTranslationKeys(project.ProjectPath)
+	if err != nil {
+		utils.Error("Failed to audit translation keys: %v", err)
+		return err
+	}
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %v", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		utils.Info("Scanned %d Dart files: %d of %d keys used", report.FilesScanned, report.UsedKeys, report.Keys)
+
+		if len(report.UnusedKeys) > 0 {
+			fmt.Println(utils.Separator("=", 50))
+			utils.Warning("Unused Keys (%d)", len(report.UnusedKeys))
+			fmt.Println(utils.Separator("=", 50))
+			for _, key := range report.UnusedKeys {
+				fmt.Printf("- %s\n", key)
+			}
+		}
+
+		if len(report.MissingKeys) > 0 {
+			fmt.Println(utils.Separator("=", 50))
+			utils.Error("Keys Missing from %s (%d)", report.DefaultLanguage, len(report.MissingKeys))
+			fmt.Println(utils.Separator("=", 50))
+			for _, usage := range report.MissingKeys {
+				fmt.Printf("- %s (%s:%d)\n", usage.Key, usage.File, usage.Line)
+			}
+		}
+
+		if len(report.DynamicUsages) > 0 {
+			fmt.Println(utils.Separator("=", 50))
+			utils.Info("Dynamic Keys (%d)", len(report.DynamicUsages))
+			fmt.Println(utils.Separator("=", 50))
+			for _, usage := range report.DynamicUsages {
+				fmt.Printf("- %s (%s:%d)\n", usage.Key, usage.File, usage.Line)
+			}
+			utils.Info("Keys matching the static part of dynamic keys are treated as used")
+		}
+
+		if len(report.UnresolvedUsages) > 0 {
+			fmt.Println(utils.Separator("=", 50))
+			utils.Info("Unresolved Keys (%d)", len(report.UnresolvedUsages))
+			fmt.Println(utils.Separator("=", 50))
+			for _, usage := range report.UnresolvedUsages {
+				fmt.Printf("- %s (%s:%d)\n", usage.Key, usage.File, usage.Line)
+			}
+			utils.Info("These calls translate keys held in variables, which can't be checked")
+		}
+
+		if len(report.UnusedKeys) == 0 && len(report.MissingKeys) == 0 {
+			utils.Success("No unused or missing keys found")
+		}
+	}
+
+	if c.Bool("prune") && len(report.UnusedKeys) > 0 {
+		if count := len(report.DynamicUsages) + len(report.UnresolvedUsages); count > 0 && !c.Bool("force") {
+			utils.Error("Not pruning: %d call(s) translate keys built at runtime or held in variables, and may use keys reported as unused", count)
+			for _, usage := range append(append([]localization.KeyUsage{}, report.DynamicUsages...), report.UnresolvedUsages...) {
+				fmt.Printf("- %s (%s:%d)\n", usage.Key, usage.File, usage.Line)
+			}
+			utils.Info("Check these call sites, then run again with --force to prune anyway")
+			return fmt.Errorf("unused keys can't be pruned safely")
+		}
+
+		if !c.Bool("yes") {
+			utils.Warning("Are you sure you want to delete %d unused key(s) from all languages? (y/N): ", len(report.UnusedKeys))
+			var confirm string
+			fmt.Scanln(&confirm)
+
+			if strings.ToLower(confirm) != "y" {
+				utils.Info("Pruning cancelled")
+				return nil
+			}
+		}
+
+		deleted, err := localization.PruneUnusedKeys(project.ProjectPath, report, c.Bool("force"))
+		if err != nil {
+			utils.Error("Failed to prune unused keys: %v", err)
+			return err
+		}
+		utils.Success("Deleted %d unused key(s)", len(deleted))
+
+		regenerateLocaleKeys(project.ProjectPath)
+	}
+
+	if len(report.MissingKeys) > 0 {
+		return fmt.Errorf("%d key(s) used in code are missing from %s", len(report.MissingKeys), report.DefaultLanguage)
+	}
 	return nil
 }
 
@@ -314,7 +1120,7 @@ func listLanguages(c *cli.Context) error {
 	}
 
 	// Check if translations directory exists
-	translationsPath := filepath.Join(project.ProjectPath, localization.TranslationsDir)
+	translationsPath := localization.GetTranslationsDir(project.ProjectPath)
 	if _, err := os.Stat(translationsPath); os.IsNotExist(err) {
 		utils.Error("Translations directory not found")
 		utils.Info("Run 'fdawg lang init' to initialize localization first")
@@ -353,3 +1159,107 @@ func listLanguages(c *cli.Context) error {
 
 	return nil
 }
+
+// generatePseudoLocales writes the en_XA pseudo-locale, and ar_XB with --rtl
+func generatePseudoLocales(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	locales := []string{localization.PseudoLocaleAccented}
+	if c.Bool("rtl") {
+		locales = append(locales, localization.PseudoLocaleBidi)
+	}
+
+	for _, locale := range locales {
+		result, err := localization.GeneratePseudoLocale(project.ProjectPath, localization.PseudoOptions{
+			Locale:  locale,
+			Padding: c.Int("padding"),
+		})
+		if err != nil {
+			utils.Error("Failed to generate %s: %v", locale, err)
+			return err
+		}
+
+		relPath, _ := filepath.Rel(project.ProjectPath, result.Path)
+		utils.Success("Generated %s with %d key(s): %s", result.Locale, result.Keys, relPath)
+	}
+
+	utils.Info("Add the pseudo-locales to your app's supported locales to test them, e.g. Locale('en', 'XA')")
+	return nil
+}
+
+// syncTranslations rebuilds the non-default translation files from the default language
+func syncTranslations(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	var languages []string
+	for _, language := range strings.Split(c.String("to"), ",") {
+		if language = strings.TrimSpace(language); language != "" {
+			languages = append(languages, language)
+		}
+	}
+
+	result, err := localization.SyncTranslations(project.ProjectPath, localization.SyncOptions{
+		Languages: languages,
+		UseSource: c.Bool("use-source"),
+		DryRun:    c.Bool("dry-run"),
+	})
+	if err != nil {
+		utils.Error("Failed to sync translations: %v", err)
+		return err
+	}
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %v", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	changed := 0
+	for _, language := range result.Languages {
+		fmt.Println(utils.Separator("=", 50))
+		if !language.Changed {
+			fmt.Printf("%s: in sync\n", language.Language)
+			continue
+		}
+		changed++
+
+		fmt.Printf("%s: %d added, %d repaired, %d orphaned\n", language.Language, len(language.Added), len(language.Repaired), len(language.Orphaned))
+		for _, key := range language.Added {
+			fmt.Printf("  + %s\n", key)
+		}
+		for _, key := range language.Repaired {
+			fmt.Printf("  ~ %s\n", key)
+		}
+		for _, key := range language.Orphaned {
+			fmt.Printf("  - %s\n", key)
+		}
+	}
+	fmt.Println(utils.Separator("=", 50))
+
+	if changed == 0 {
+		utils.Success("All languages are in sync with %s", result.DefaultLanguage)
+		return nil
+	}
+
+	if c.Bool("dry-run") {
+		utils.Info("%d language(s) would be rebuilt. Run without --dry-run to apply", changed)
+		return nil
+	}
+
+	utils.Success("Synced %d language(s) with %s", changed, result.DefaultLanguage)
+	if result.OrphanReport != "" {
+		relPath, _ := filepath.Rel(project.ProjectPath, result.OrphanReport)
+		utils.Info("Orphaned values were saved to %s", relPath)
+	}
+
+	return nil
+}
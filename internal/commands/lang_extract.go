@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/localization"
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// langExtractCommand returns the lang extract command for moving hard-coded strings into translations
+func langExtractCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "extract",
+		Usage:       "Move hard-coded strings in Dart code into translations",
+		Description: "Scans lib/**/*.dart, or the given file or directory, for string literals in Text widgets and hintText, labelText and title arguments. Each string is added to the default language under a suggested key and replaced with a 'key'.tr() call",
+		ArgsUsage:   "[path]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "List the strings and suggested keys without changing anything",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Extract every string with its suggested key without prompting",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the dry-run report as JSON",
+			},
+		},
+		Action: extractHardcodedStrings,
+	}
+}
+
+// extractHardcodedStrings moves hard-coded strings into translations
+func extractHardcodedStrings(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	candidates, err := localization.FindHardcodedStrings(project.ProjectPath, c.Args().First())
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	if c.Bool("dry-run") {
+		if c.Bool("json") {
+			output, err := json.MarshalIndent(candidates, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode report: %v", err)
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+		printExtractCandidates(candidates)
+		return nil
+	}
+
+	var extractable []localization.ExtractCandidate
+	for _, candidate := range candidates {
+		if candidate.Skipped != "" {
+			utils.Warning("Skipping %s:%d %q: %s", candidate.File, candidate.Line, candidate.Text, candidate.Skipped)
+			continue
+		}
+		extractable = append(extractable, candidate)
+	}
+	candidates = extractable
+
+	if len(candidates) == 0 {
+		utils.Success("No hard-coded strings found")
+		return nil
+	}
+
+	selected := candidates
+	if !c.Bool("yes") {
+		if selected, err = promptExtractCandidates(project.ProjectPath, candidates); err != nil {
+			utils.Error("%v", err)
+			return err
+		}
+	}
+
+	if len(selected) == 0 {
+		utils.Info("Nothing extracted")
+		return nil
+	}
+
+	result, err := localization.ApplyExtraction(project.ProjectPath, selected)
+	if err != nil {
+		utils.Error("Failed to extract strings: %v", err)
+		return err
+	}
+
+	utils.Success("Extracted %d string(s) into %d new key(s) across %d file(s)", result.Replacements, len(result.Keys), len(result.Files))
+	for _, file := range result.Files {
+		fmt.Printf("  %s\n", file)
+	}
+	utils.Info("Translate the new keys with 'fdawg lang translate --missing'")
+
+	regenerateLocaleKeys(project.ProjectPath)
+	return nil
+}
+
+// printExtractCandidates prints the dry-run report, grouped by file
+func printExtractCandidates(candidates []localization.ExtractCandidate) {
+	if len(candidates) == 0 {
+		utils.Success("No hard-coded strings found")
+		return
+	}
+
+	file := ""
+	skipped := 0
+	for _, candidate := range candidates {
+		if candidate.File != file {
+			file = candidate.File
+			fmt.Println(utils.Separator("=", 50))
+			fmt.Println(file)
+			fmt.Println(utils.Separator("=", 50))
+		}
+
+		if candidate.Skipped != "" {
+			skipped++
+			fmt.Printf("%4d  %-9s %q\n      ✗ skipped: %s\n", candidate.Line, candidate.Widget, candidate.Text, candidate.Skipped)
+			continue
+		}
+
+		reused := ""
+		if candidate.Existing {
+			reused = " (existing key)"
+		}
+		fmt.Printf("%4d  %-9s %q\n      → %s%s\n", candidate.Line, candidate.Widget, candidate.Text, candidate.Key, reused)
+	}
+	fmt.Println(utils.Separator("=", 50))
+	if skipped > 0 {
+		utils.Info("Found %d hard-coded string(s), %d skipped. Run without --dry-run to extract them", len(candidates), skipped)
+		return
+	}
+	utils.Info("Found %d hard-coded string(s). Run without --dry-run to extract them", len(candidates))
+}
+
+// promptExtractCandidates asks which strings to extract and lets the suggested keys be
+// edited. End of input stops prompting and keeps what was accepted.
+func promptExtractCandidates(projectPath string, candidates []localization.ExtractCandidate) ([]localization.ExtractCandidate, error) {
+	utils.Info("Found %d hard-coded string(s)", len(candidates))
+	utils.Info("[y]es, [n]o, [e]dit key, [a]ll remaining, [q]uit")
+
+	reader := bufio.NewReader(os.Stdin)
+	var selected []localization.ExtractCandidate
+	// Edited keys apply to later strings with the same suggestion and text
+	edited := make(map[string]string)
+
+	for i := 0; i < len(candidates); i++ {
+		candidate := candidates[i]
+		if key, ok := edited[candidate.Key+"\x00"+candidate.Text]; ok {
+			candidate.Key = key
+		}
+
+		fmt.Println(utils.Separator("-", 50))
+		fmt.Printf("[%d/%d] %s:%d  %s\n", i+1, len(candidates), candidate.File, candidate.Line, candidate.Widget)
+		fmt.Printf("  text: %q\n  key:  %s\n", candidate.Text, candidate.Key)
+
+		fmt.Print("Extract [y/n/e/a/q]: ")
+		input, err := reader.ReadString('\n')
+		action := strings.ToLower(strings.TrimSpace(input))
+		if action == "" && err != nil {
+			action = "q"
+		}
+
+		switch action {
+		case "y":
+			selected = append(selected, candidate)
+		case "n":
+		case "e":
+			fmt.Print("Key: ")
+			key, _ := reader.ReadString('\n')
+			key = strings.TrimSpace(key)
+			if key == "" {
+				i--
+				continue
+			}
+			if _, err := localization.CheckExtractKey(projectPath, key, candidate.Text); err != nil {
+				utils.Warning("%v", err)
+				i--
+				continue
+			}
+			edited[candidates[i].Key+"\x00"+candidate.Text] = key
+			candidate.Key = key
+			selected = append(selected, candidate)
+		case "a":
+			selected = append(selected, candidate)
+			for _, rest := range candidates[i+1:] {
+				if key, ok := edited[rest.Key+"\x00"+rest.Text]; ok {
+					rest.Key = key
+				}
+				selected = append(selected, rest)
+			}
+			return selected, nil
+		case "q":
+			return selected, nil
+		default:
+			utils.Warning("Unknown action %q", action)
+			i--
+		}
+	}
+
+	return selected, nil
+}
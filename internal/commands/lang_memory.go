@@ -0,0 +1,413 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/localization"
+	"github.com/Jerinji2016/fdawg/pkg/translate"
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// langMemoryCommand returns the lang memory command for editing the translation memory
+func langMemoryCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "memory",
+		Usage:       "Manage the translation memory",
+		Description: "The translation memory in .fdawg/translation_memory.json caches machine translations, and is checked before calling the translation provider",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List cached translations",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "Only list translations into this language",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the entries as JSON",
+					},
+				},
+				Action: listTranslationMemory,
+			},
+			{
+				Name:      "set",
+				Usage:     "Add or correct a cached translation",
+				ArgsUsage: "<source text> <translation>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "to",
+						Usage:    "Language of the translation",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "from",
+						Usage: "Language of the source text (default: the default language)",
+					},
+				},
+				Action: setTranslationMemory,
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete a cached translation",
+				ArgsUsage: "<source text>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "to",
+						Usage:    "Language of the translation",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "from",
+						Usage: "Language of the source text (default: the default language)",
+					},
+				},
+				Action: deleteTranslationMemory,
+			},
+			{
+				Name:  "clear",
+				Usage: "Delete cached translations",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "Only delete translations into this language",
+					},
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "Clear without asking for confirmation",
+					},
+				},
+				Action: clearTranslationMemory,
+			},
+		},
+	}
+}
+
+// langGlossaryCommand returns the lang glossary command for editing the glossary
+func langGlossaryCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "glossary",
+		Usage:       "Manage terms protected from machine translation",
+		Description: "The glossary in .fdawg/glossary.json lists terms that are never translated, such as brand names, and terms with forced translations",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List glossary terms",
+				Action: listGlossary,
+			},
+			{
+				Name:      "add",
+				Usage:     "Add a term, or replace its translations",
+				ArgsUsage: "<term>",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "translation",
+						Aliases: []string{"t"},
+						Usage:   "Forced translation as language=text, e.g. fr=Panier. Terms without translations are kept as is",
+					},
+				},
+				Action: addGlossaryTerm,
+			},
+			{
+				Name:      "remove",
+				Usage:     "Remove a term",
+				ArgsUsage: "<term>",
+				Action:    removeGlossaryTerm,
+			},
+		},
+	}
+}
+
+// listTranslationMemory lists the entries of the translation memory
+func listTranslationMemory(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	memory, err := translate.LoadMemory(project.ProjectPath)
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	entries := []translate.MemoryEntry{}
+	for _, entry := range memory.Entries() {
+		if c.String("to") == "" || entry.TargetLang == c.String("to") {
+			entries = append(entries, entry)
+		}
+	}
+
+	if c.Bool("json") {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		utils.Info("The translation memory is empty")
+		return nil
+	}
+
+	fmt.Println(utils.Separator("=", 50))
+	fmt.Println("Translation Memory")
+	fmt.Println(utils.Separator("=", 50))
+	for _, entry := range entries {
+		fmt.Printf("[%s -> %s] %q\n    %q (%s)\n", entry.SourceLang, entry.TargetLang, entry.Source, entry.Target, entry.Provider)
+	}
+	fmt.Println(utils.Separator("=", 50))
+	fmt.Printf("Total: %d entries\n", len(entries))
+
+	return nil
+}
+
+// setTranslationMemory adds or corrects a translation memory entry
+func setTranslationMemory(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 2 {
+		utils.Error("Source text and translation are required")
+		utils.Info("Usage: fdawg lang memory set --to <language> <source text> <translation>")
+		utils.Info("Example: fdawg lang memory set --to fr \"Add to cart\" \"Ajouter au panier\"")
+		return fmt.Errorf("source text and translation are required")
+	}
+
+	memory, err := translate.LoadMemory(project.ProjectPath)
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	sourceLang := memorySourceLanguage(c, project.ProjectPath)
+	memory.Set(sourceLang, c.String("to"), c.Args().Get(0), c.Args().Get(1), translate.ManualProvider, "")
+
+	if err := memory.Save(); err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	utils.Success("Saved the %s translation of %q", c.String("to"), c.Args().Get(0))
+	return nil
+}
+
+// deleteTranslationMemory deletes a translation memory entry
+func deleteTranslationMemory(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 1 {
+		utils.Error("Source text is required")
+		utils.Info("Usage: fdawg lang memory delete --to <language> <source text>")
+		return fmt.Errorf("source text is required")
+	}
+
+	memory, err := translate.LoadMemory(project.ProjectPath)
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	sourceLang := memorySourceLanguage(c, project.ProjectPath)
+	if !memory.Delete(sourceLang, c.String("to"), c.Args().First()) {
+		utils.Error("No %s translation of %q in the translation memory", c.String("to"), c.Args().First())
+		return fmt.Errorf("translation memory entry not found")
+	}
+
+	if err := memory.Save(); err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	utils.Success("Deleted the %s translation of %q", c.String("to"), c.Args().First())
+	return nil
+}
+
+// clearTranslationMemory deletes all entries, or those for one language
+func clearTranslationMemory(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	memory, err := translate.LoadMemory(project.ProjectPath)
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	scope := "all languages"
+	if c.String("to") != "" {
+		scope = c.String("to")
+	}
+
+	if !c.Bool("yes") {
+		utils.Warning("Are you sure you want to clear the translation memory for %s? (y/N): ", scope)
+		var confirm string
+		fmt.Scanln(&confirm)
+
+		if strings.ToLower(confirm) != "y" {
+			utils.Info("Clear cancelled")
+			return nil
+		}
+	}
+
+	removed := memory.Clear(c.String("to"))
+	if err := memory.Save(); err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	utils.Success("Deleted %d translation memory entries for %s", removed, scope)
+	return nil
+}
+
+// memorySourceLanguage returns the --from language, defaulting to the project's default language
+func memorySourceLanguage(c *cli.Context, projectPath string) string {
+	if c.String("from") != "" {
+		return c.String("from")
+	}
+	return localization.DetectBackend(projectPath).DefaultLanguage()
+}
+
+// listGlossary lists the glossary terms
+func listGlossary(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	glossary, err := translate.LoadGlossary(project.ProjectPath)
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	if len(glossary.Terms) == 0 {
+		utils.Info("The glossary is empty")
+		utils.Info("Add a term with: fdawg lang glossary add <term>")
+		return nil
+	}
+
+	fmt.Println(utils.Separator("=", 50))
+	fmt.Println("Glossary")
+	fmt.Println(utils.Separator("=", 50))
+	for _, term := range glossary.Terms {
+		if len(term.Translations) == 0 {
+			fmt.Printf("- %s (never translated)\n", term.Term)
+			continue
+		}
+		fmt.Printf("- %s\n", term.Term)
+		for _, language := range sortedStringKeys(term.Translations) {
+			fmt.Printf("    %s: %s\n", language, term.Translations[language])
+		}
+	}
+	fmt.Println(utils.Separator("=", 50))
+	fmt.Printf("Total: %d terms\n", len(glossary.Terms))
+
+	return nil
+}
+
+// addGlossaryTerm adds a glossary term or replaces its translations
+func addGlossaryTerm(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 1 {
+		utils.Error("Term is required")
+		utils.Info("Usage: fdawg lang glossary add [--translation <language>=<text>] <term>")
+		utils.Info("Example: fdawg lang glossary add FDAWG")
+		utils.Info("Example: fdawg lang glossary add -t fr=Panier -t de=Warenkorb Cart")
+		return fmt.Errorf("term is required")
+	}
+
+	translations := make(map[string]string)
+	for _, value := range c.StringSlice("translation") {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			utils.Error("Invalid translation %q, expected language=text", value)
+			return fmt.Errorf("invalid translation %q", value)
+		}
+		translations[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	if len(translations) == 0 {
+		translations = nil
+	}
+
+	glossary, err := translate.LoadGlossary(project.ProjectPath)
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	if err := glossary.Set(c.Args().First(), translations); err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	if err := glossary.Save(); err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	utils.Success("Glossary term %s saved", c.Args().First())
+	utils.Info("Cached translations aren't affected; run 'fdawg lang memory clear' to translate them again")
+	return nil
+}
+
+// removeGlossaryTerm removes a glossary term
+func removeGlossaryTerm(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 1 {
+		utils.Error("Term is required")
+		utils.Info("Usage: fdawg lang glossary remove <term>")
+		return fmt.Errorf("term is required")
+	}
+
+	glossary, err := translate.LoadGlossary(project.ProjectPath)
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	if !glossary.Remove(c.Args().First()) {
+		utils.Error("Term %s is not in the glossary", c.Args().First())
+		return fmt.Errorf("glossary term not found")
+	}
+
+	if err := glossary.Save(); err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	utils.Success("Glossary term %s removed", c.Args().First())
+	return nil
+}
+
+// sortedStringKeys returns the keys of a map in order
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/localization"
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// langReviewCommand returns the lang review command for stepping through translations awaiting review
+func langReviewCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "review",
+		Usage:       "Review machine translations and flagged values",
+		Description: "Steps through translations with a review status of machine or needs-review, to accept, edit or flag each one. Statuses are kept in .fdawg/translation_status.json",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "Only review this language",
+			},
+			&cli.StringFlag{
+				Name:  "status",
+				Usage: "Comma-separated statuses to review: machine, needs-review or reviewed",
+				Value: localization.StatusMachine + "," + localization.StatusNeedsReview,
+			},
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "List the translations and review counts without prompting",
+			},
+		},
+		Action: reviewTranslations,
+	}
+}
+
+// reviewTranslations steps through translations awaiting review
+func reviewTranslations(c *cli.Context) error {
+	project, err := validateFlutterProjectForLocalization()
+	if err != nil {
+		return err
+	}
+
+	var statuses []string
+	for _, status := range strings.Split(c.String("status"), ",") {
+		status = strings.TrimSpace(status)
+		if status == "" {
+			continue
+		}
+		if !localization.IsValidReviewStatus(status) {
+			utils.Error("Invalid status %s, expected one of: %s", status, strings.Join(localization.ReviewStatuses, ", "))
+			return fmt.Errorf("invalid status %s", status)
+		}
+		statuses = append(statuses, status)
+	}
+
+	items, err := localization.ListReviewItems(project.ProjectPath, statuses, c.String("to"))
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	if c.Bool("list") {
+		return listReviewItems(project.ProjectPath, items)
+	}
+
+	if len(items) == 0 {
+		utils.Success("Nothing to review")
+		return nil
+	}
+
+	utils.Info("Reviewing %d translation(s)", len(items))
+	utils.Info("[a]ccept, [e]dit, [f]lag for review, [s]kip, [q]uit")
+
+	reader := bufio.NewReader(os.Stdin)
+	counts := make(map[string]int)
+
+	for i, item := range items {
+		fmt.Println(utils.Separator("-", 50))
+		fmt.Printf("[%d/%d] %s  %s  (%s)\n", i+1, len(items), item.Language, item.Key, item.Status)
+		printReviewValue("source", item.Source)
+		printReviewValue(item.Language, item.Value)
+
+		action := promptReviewAction(reader)
+		switch action {
+		case "a":
+			if err := localization.SetReviewStatus(project.ProjectPath, item.Language, item.Key, localization.StatusReviewed); err != nil {
+				utils.Error("%v", err)
+				return err
+			}
+			counts["accepted"]++
+		case "e":
+			if err := editReviewItem(project.ProjectPath, item, reader); err != nil {
+				utils.Error("%v", err)
+				return err
+			}
+			counts["edited"]++
+		case "f":
+			if err := localization.SetReviewStatus(project.ProjectPath, item.Language, item.Key, localization.StatusNeedsReview); err != nil {
+				utils.Error("%v", err)
+				return err
+			}
+			counts["flagged"]++
+		case "s":
+			counts["skipped"]++
+		case "q":
+			fmt.Println(utils.Separator("-", 50))
+			printReviewSummary(counts)
+			return nil
+		}
+	}
+
+	fmt.Println(utils.Separator("-", 50))
+	printReviewSummary(counts)
+	return nil
+}
+
+// promptReviewAction asks for a review action until a valid one is entered.
+// End of input quits.
+func promptReviewAction(reader *bufio.Reader) string {
+	for {
+		fmt.Print("Action [a/e/f/s/q]: ")
+		input, err := reader.ReadString('\n')
+		action := strings.ToLower(strings.TrimSpace(input))
+		if action == "" && err != nil {
+			return "q"
+		}
+
+		switch action {
+		case "a", "e", "f", "s", "q":
+			return action
+		}
+		utils.Warning("Unknown action %q", action)
+	}
+}
+
+// editReviewItem prompts for a corrected value and writes it. Plural and gender
+// forms are prompted one at a time; an empty answer keeps the current text.
+func editReviewItem(projectPath string, item localization.ReviewItem, reader *bufio.Reader) error {
+	messages, err := localization.GetTranslationMessages(projectPath, item.Key)
+	if err != nil {
+		return err
+	}
+	message := messages[item.Language]
+
+	if message.Kind == localization.MessageText {
+		fmt.Print("New value (empty keeps the current one): ")
+		value, _ := reader.ReadString('\n')
+		if value = strings.TrimSpace(value); value != "" {
+			message.Text = value
+		}
+	} else {
+		forms := make(map[string]string)
+		for _, form := range localization.SortForms(message.Forms) {
+			fmt.Printf("%s [%s]: ", form, message.Forms[form])
+			value, _ := reader.ReadString('\n')
+			if value = strings.TrimSpace(value); value != "" {
+				forms[form] = value
+			} else {
+				forms[form] = message.Forms[form]
+			}
+		}
+		message.Forms = forms
+	}
+
+	if err := localization.ReviseTranslation(projectPath, item.Language, item.Key, message); err != nil {
+		return err
+	}
+
+	utils.Success("Saved %s (%s)", item.Key, item.Language)
+	return nil
+}
+
+// printReviewValue prints a value under a label, indenting the lines of plural and gender forms
+func printReviewValue(label, value string) {
+	lines := strings.Split(value, "\n")
+	fmt.Printf("  %-8s %s\n", label+":", lines[0])
+	for _, line := range lines[1:] {
+		fmt.Printf("  %-8s %s\n", "", line)
+	}
+}
+
+// printReviewSummary prints what was done in a review session
+func printReviewSummary(counts map[string]int) {
+	utils.Info("Accepted: %d, edited: %d, flagged: %d, skipped: %d", counts["accepted"], counts["edited"], counts["flagged"], counts["skipped"])
+}
+
+// listReviewItems prints the review counts per language and the matching translations
+func listReviewItems(projectPath string, items []localization.ReviewItem) error {
+	translationFiles, err := localization.ListTranslationFiles(projectPath)
+	if err != nil {
+		utils.Error("Failed to list translation files: %v", err)
+		return err
+	}
+
+	state, err := localization.LoadReviewState(projectPath)
+	if err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	counts := localization.ReviewStatusCounts(translationFiles, localization.DetectBackend(projectPath).DefaultLanguage(), state)
+
+	fmt.Println(utils.Separator("=", 50))
+	fmt.Println("Review Status")
+	fmt.Println(utils.Separator("=", 50))
+	languages := make([]string, 0, len(counts))
+	for language := range counts {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	for _, language := range languages {
+		fmt.Printf("%-8s machine: %d, needs-review: %d, reviewed: %d\n", language,
+			counts[language][localization.StatusMachine],
+			counts[language][localization.StatusNeedsReview],
+			counts[language][localization.StatusReviewed])
+	}
+	fmt.Println(utils.Separator("=", 50))
+
+	if len(items) == 0 {
+		utils.Info("No translations with the given status")
+		return nil
+	}
+
+	for _, item := range items {
+		fmt.Printf("[%s] %s  (%s)\n", item.Language, item.Key, item.Status)
+		printReviewValue("source", item.Source)
+		printReviewValue(item.Language, item.Value)
+	}
+	fmt.Printf("Total: %d translation(s)\n", len(items))
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -33,8 +34,14 @@ func NamerCommand() *cli.Command {
 			{
 				Name:        "set",
 				Usage:       "Set app names",
-				Description: "Sets app names for specified platforms",
+				Description: "Sets app names for specified platforms. With --locale, sets the name shown to users of that language on Android, iOS and macOS",
+				ArgsUsage:   "[app name]",
 				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "locale",
+						Aliases: []string{"l"},
+						Usage:   "Set the app name for one of the project's languages, e.g. ja or pt_BR",
+					},
 					&cli.StringFlag{
 						Name:    "value",
 						Aliases: []string{"v"},
@@ -72,6 +79,39 @@ func NamerCommand() *cli.Command {
 				},
 				Action: setAppNames,
 			},
+			{
+				Name:        "unset",
+				Usage:       "Remove localized app names",
+				Description: "Removes the app names set for a locale with 'namer set --locale', so the default name is shown",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "locale",
+						Aliases:  []string{"l"},
+						Usage:    "Locale to remove the app names of",
+						Required: true,
+					},
+				},
+				Action: unsetLocalizedAppNames,
+			},
+			{
+				Name:        "validate",
+				Usage:       "Check app names against platform rules",
+				Description: "Checks the current app names, or the given name, against store length limits and the characters each platform's build files can hold. Exits with an error if any name would be rejected",
+				ArgsUsage:   "[app name]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "platforms",
+						Aliases: []string{"p"},
+						Usage:   "Platforms to check (android, ios, macos, linux, windows, web)",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the report as JSON",
+					},
+				},
+				Action: validateAppNames,
+			},
+			namerWebCommand(),
 			{
 				Name:        "list",
 				Usage:       "List current app names",
@@ -122,6 +162,7 @@ func getAppNames(c *cli.Context) error {
 
 	// Display results
 	displayAppNames(result)
+	displayLocalizedAppNames(project.ProjectPath)
 	return nil
 }
 
@@ -138,6 +179,12 @@ func setAppNames(c *cli.Context) error {
 		ProjectPath: project.ProjectPath,
 		Universal:   c.String("value"),
 		Platforms:   make(map[namer.Platform]string),
+		Locale:      c.String("locale"),
+	}
+
+	// The name may also be given as an argument
+	if request.Universal == "" && c.Args().Len() > 0 {
+		request.Universal = c.Args().First()
 	}
 
 	// Check for platform-specific names
@@ -178,9 +225,26 @@ func setAppNames(c *cli.Context) error {
 		utils.Info("  fdawg namer set --value \"My App\"")
 		utils.Info("  fdawg namer set --android \"Android App\" --ios \"iOS App\"")
 		utils.Info("  fdawg namer set --value \"My App\" --platforms android,ios")
+		utils.Info("  fdawg namer set --locale ja \"マイアプリ\"")
 		return fmt.Errorf("no app name provided")
 	}
 
+	// Report problems before anything is written; errors stop the update
+	report, err := namer.ValidateAppNames(request)
+	if err != nil {
+		utils.Error("Failed to set app names: %v", err)
+		return err
+	}
+	printNamerIssues(report.Issues)
+	if err := report.Err(); err != nil {
+		utils.Error("%v", err)
+		return err
+	}
+
+	if request.Locale != "" {
+		return setLocalizedAppNames(request)
+	}
+
 	// Set app names
 	utils.Info("Setting app names...")
 	if err := namer.SetAppNames(request); err != nil {
@@ -200,6 +264,117 @@ func setAppNames(c *cli.Context) error {
 	return nil
 }
 
+// setLocalizedAppNames sets the app names of one locale
+func setLocalizedAppNames(request *namer.SetAppNameRequest) error {
+	utils.Info("Setting %s app names...", request.Locale)
+	result, err := namer.SetLocalizedAppNames(request)
+	if err != nil {
+		utils.Error("Failed to set app names: %v", err)
+		return err
+	}
+
+	for _, file := range result.Files {
+		fmt.Printf("  %s\n", file)
+	}
+	for _, warning := range result.Warnings {
+		utils.Warning("%s", warning)
+	}
+
+	utils.Success("%s app names updated successfully", result.Locale)
+	return nil
+}
+
+// unsetLocalizedAppNames removes the app names of one locale
+func unsetLocalizedAppNames(c *cli.Context) error {
+	project, err := validateFlutterProjectForNamer()
+	if err != nil {
+		return err
+	}
+
+	result, err := namer.RemoveLocalizedAppNames(project.ProjectPath, c.String("locale"))
+	if err != nil {
+		utils.Error("Failed to remove app names: %v", err)
+		return err
+	}
+
+	if len(result.Files) == 0 {
+		utils.Info("No %s app names are set", result.Locale)
+		return nil
+	}
+
+	for _, file := range result.Files {
+		fmt.Printf("  %s\n", file)
+	}
+	utils.Success("%s app names removed", result.Locale)
+	return nil
+}
+
+// validateAppNames checks the current app names, or a proposed one, against each platform's rules
+func validateAppNames(c *cli.Context) error {
+	project, err := validateFlutterProjectForNamer()
+	if err != nil {
+		return err
+	}
+
+	platforms, err := parsePlatforms(c.StringSlice("platforms"))
+	if err != nil {
+		return err
+	}
+
+	var report *namer.ValidationReport
+	if c.Args().Len() > 0 {
+		request := &namer.SetAppNameRequest{
+			ProjectPath: project.ProjectPath,
+			Universal:   c.Args().First(),
+		}
+		if len(platforms) > 0 {
+			request.Universal = ""
+			request.Platforms = make(map[namer.Platform]string)
+			for _, platform := range platforms {
+				request.Platforms[platform] = c.Args().First()
+			}
+		}
+		report, err = namer.ValidateAppNames(request)
+	} else {
+		report, err = namer.ValidateCurrentAppNames(project.ProjectPath, platforms)
+	}
+	if err != nil {
+		utils.Error("Failed to validate app names: %v", err)
+		return err
+	}
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %v", err)
+		}
+		fmt.Println(string(output))
+	} else if len(report.Issues) == 0 {
+		utils.Success("No issues found")
+	} else {
+		fmt.Println(utils.Separator("=", 50))
+		printNamerIssues(report.Issues)
+		fmt.Println(utils.Separator("=", 50))
+		utils.Info("%d error(s), %d warning(s)", report.Errors, report.Warnings)
+	}
+
+	if report.Errors > 0 {
+		return fmt.Errorf("app name validation failed with %d error(s)", report.Errors)
+	}
+	return nil
+}
+
+// printNamerIssues prints app name issues, one line each
+func printNamerIssues(issues []namer.ValidationIssue) {
+	for _, issue := range issues {
+		if issue.Severity == namer.SeverityError {
+			utils.Error("%s (%s): %s", issue.Platform, issue.Field, issue.Message)
+		} else {
+			utils.Warning("%s (%s): %s", issue.Platform, issue.Field, issue.Message)
+		}
+	}
+}
+
 // listAppNames lists all current app names (alias for get with no platforms)
 func listAppNames(c *cli.Context) error {
 	// Validate Flutter project
@@ -218,6 +393,7 @@ func listAppNames(c *cli.Context) error {
 
 	// Display results
 	displayAppNames(result)
+	displayLocalizedAppNames(project.ProjectPath)
 	return nil
 }
 
@@ -279,3 +455,17 @@ func displayAppNames(result *namer.AppNameResult) {
 	fmt.Println(utils.Separator("=", 60))
 	utils.Success("Available Platforms: %d", availableCount)
 }
+
+// displayLocalizedAppNames displays the app names set per locale, if any
+func displayLocalizedAppNames(projectPath string) {
+	names, err := namer.GetLocalizedAppNames(projectPath)
+	if err != nil || len(names) == 0 {
+		return
+	}
+
+	utils.Info("Localized App Names")
+	for _, name := range names {
+		fmt.Printf("  %-8s %-8s %s\n", name.Locale, name.Platform, name.DisplayName)
+	}
+	fmt.Println(utils.Separator("=", 60))
+}
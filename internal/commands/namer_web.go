@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Jerinji2016/fdawg/pkg/namer"
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// webMetadataFlags maps the namer web set flags to their metadata fields
+var webMetadataFlags = []struct {
+	name  string
+	usage string
+	field func(update *namer.WebMetadataUpdate) **string
+}{
+	{"name", "Manifest name", func(u *namer.WebMetadataUpdate) **string { return &u.Name }},
+	{"short-name", "Manifest short_name, shown on home screens (empty removes it)", func(u *namer.WebMetadataUpdate) **string { return &u.ShortName }},
+	{"description", "Manifest description (empty removes it)", func(u *namer.WebMetadataUpdate) **string { return &u.Description }},
+	{"theme-color", "Manifest theme_color, e.g. #0175C2 (empty removes it)", func(u *namer.WebMetadataUpdate) **string { return &u.ThemeColor }},
+	{"background-color", "Manifest background_color, e.g. #FFFFFF (empty removes it)", func(u *namer.WebMetadataUpdate) **string { return &u.BackgroundColor }},
+	{"title", "index.html <title>", func(u *namer.WebMetadataUpdate) **string { return &u.Title }},
+	{"apple-title", "index.html apple-mobile-web-app-title, the name of the app added to an iOS home screen", func(u *namer.WebMetadataUpdate) **string { return &u.AppleTitle }},
+	{"meta-description", "index.html meta description", func(u *namer.WebMetadataUpdate) **string { return &u.MetaDescription }},
+}
+
+// namerWebCommand returns the namer web command for managing web app metadata
+func namerWebCommand() *cli.Command {
+	var setFlags []cli.Flag
+	for _, flag := range webMetadataFlags {
+		setFlags = append(setFlags, &cli.StringFlag{Name: flag.name, Usage: flag.usage})
+	}
+
+	return &cli.Command{
+		Name:        "web",
+		Usage:       "Manage web app metadata",
+		Description: "Commands for the web app's name, short name, description and colors in web/manifest.json, and its title and meta tags in web/index.html",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "get",
+				Usage:       "Show web app metadata",
+				Description: "Shows the metadata in web/manifest.json and web/index.html",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the metadata as JSON",
+					},
+				},
+				Action: getWebMetadata,
+			},
+			{
+				Name:        "set",
+				Usage:       "Set web app metadata",
+				Description: "Sets the given fields. manifest.json keeps its key order and other fields, and missing index.html meta tags are added",
+				Flags:       setFlags,
+				Action:      setWebMetadata,
+			},
+		},
+	}
+}
+
+// getWebMetadata prints the web app metadata
+func getWebMetadata(c *cli.Context) error {
+	project, err := validateFlutterProjectForNamer()
+	if err != nil {
+		return err
+	}
+
+	metadata, err := namer.GetWebMetadata(project.ProjectPath)
+	if err != nil {
+		utils.Error("Failed to read web metadata: %v", err)
+		return err
+	}
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(metadata, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode metadata: %v", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	displayWebMetadata(metadata)
+	return nil
+}
+
+// setWebMetadata writes the web app metadata fields given as flags
+func setWebMetadata(c *cli.Context) error {
+	project, err := validateFlutterProjectForNamer()
+	if err != nil {
+		return err
+	}
+
+	var update namer.WebMetadataUpdate
+	changed := 0
+	for _, flag := range webMetadataFlags {
+		if c.IsSet(flag.name) {
+			value := c.String(flag.name)
+			*flag.field(&update) = &value
+			changed++
+		}
+	}
+
+	if changed == 0 {
+		utils.Error("No metadata fields provided")
+		utils.Info("Usage examples:")
+		utils.Info("  fdawg namer web set --short-name \"My App\" --theme-color \"#0175C2\"")
+		utils.Info("  fdawg namer web set --description \"A Flutter app\" --meta-description \"A Flutter app\"")
+		return fmt.Errorf("no metadata fields provided")
+	}
+
+	report, err := namer.SetWebMetadata(project.ProjectPath, update)
+	if report != nil {
+		printNamerIssues(report.Issues)
+	}
+	if err != nil {
+		utils.Error("Failed to set web metadata: %v", err)
+		return err
+	}
+
+	utils.Success("Web metadata updated successfully")
+	if metadata, err := namer.GetWebMetadata(project.ProjectPath); err == nil {
+		displayWebMetadata(metadata)
+	}
+	return nil
+}
+
+// displayWebMetadata prints the web app metadata, grouped by file
+func displayWebMetadata(metadata *namer.WebMetadata) {
+	fmt.Println(utils.Separator("=", 50))
+	utils.Info("web/manifest.json")
+	fmt.Println(utils.Separator("=", 50))
+	fmt.Printf("  name:             %s\n", metadata.Name)
+	fmt.Printf("  short_name:       %s\n", metadata.ShortName)
+	fmt.Printf("  description:      %s\n", metadata.Description)
+	fmt.Printf("  theme_color:      %s\n", metadata.ThemeColor)
+	fmt.Printf("  background_color: %s\n", metadata.BackgroundColor)
+
+	fmt.Println(utils.Separator("=", 50))
+	utils.Info("web/index.html")
+	fmt.Println(utils.Separator("=", 50))
+	fmt.Printf("  title:                      %s\n", metadata.Title)
+	fmt.Printf("  apple-mobile-web-app-title: %s\n", metadata.AppleTitle)
+	fmt.Printf("  description:                %s\n", metadata.MetaDescription)
+}
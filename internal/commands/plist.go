@@ -0,0 +1,605 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/flutter"
+	"github.com/Jerinji2016/fdawg/pkg/plist"
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// plistFileFlags are the flags choosing which plist files a command works on
+var plistFileFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:    "platform",
+		Aliases: []string{"p"},
+		Usage:   "Platforms to use (ios, macos); defaults to every platform in the project",
+	},
+	&cli.BoolFlag{
+		Name:  "entitlements",
+		Usage: "Use the entitlements files instead of Info.plist",
+	},
+	&cli.StringFlag{
+		Name:  "file",
+		Usage: "Use this plist file instead of the platforms' files",
+	},
+}
+
+// PlistCommand returns the CLI command for editing iOS and macOS plist files
+func PlistCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "plist",
+		Usage:       "Read and edit Info.plist and entitlements files",
+		Description: "Commands for reading and editing the iOS and macOS Info.plist and entitlements files. Comments, key order and formatting are kept. Keys inside dicts and arrays are addressed with colons, e.g. NSAppTransportSecurity:NSAllowsArbitraryLoads",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "get",
+				Usage:     "Get a value",
+				ArgsUsage: "<key>",
+				Flags: append([]cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the values as JSON",
+					},
+				}, plistFileFlags...),
+				Action: getPlistValue,
+			},
+			{
+				Name:        "set",
+				Usage:       "Set a value",
+				Description: "Sets a key, adding it and any missing dicts on the way. Without --type, an existing value keeps its type and new values are strings. Arrays take each remaining argument as a string element",
+				ArgsUsage:   "<key> [value...]",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:    "type",
+						Aliases: []string{"t"},
+						Usage:   "Value type (string, bool, integer, real, date, data, array, dict)",
+					},
+				}, plistFileFlags...),
+				Action: setPlistValue,
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete a key",
+				ArgsUsage: "<key>",
+				Flags:     plistFileFlags,
+				Action:    deletePlistValue,
+			},
+			{
+				Name:        "usage",
+				Usage:       "Manage permission usage descriptions",
+				Description: "Commands for the purpose strings iOS and macOS show when asking for a permission, such as NSCameraUsageDescription",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "List the usage descriptions that are set, or all known permissions",
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:    "platform",
+								Aliases: []string{"p"},
+								Usage:   "Platforms to use (ios, macos); defaults to every platform in the project",
+							},
+							&cli.BoolFlag{
+								Name:  "known",
+								Usage: "List the known permissions and their keys instead",
+							},
+							&cli.BoolFlag{
+								Name:  "json",
+								Usage: "Print the descriptions as JSON",
+							},
+						},
+						Action: listUsageDescriptions,
+					},
+					{
+						Name:        "set",
+						Usage:       "Set a permission's usage description",
+						Description: "Sets the purpose string for a permission, given by short name (camera) or Info.plist key (NSCameraUsageDescription). On macOS the permission's sandbox entitlement is enabled too",
+						ArgsUsage:   "<permission> <description>",
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:    "platform",
+								Aliases: []string{"p"},
+								Usage:   "Platforms to use (ios, macos); defaults to every platform in the project",
+							},
+						},
+						Action: setUsageDescription,
+					},
+					{
+						Name:        "remove",
+						Usage:       "Remove a permission's usage description",
+						Description: "Removes the purpose string for a permission, and on macOS its sandbox entitlement unless another permission still needs it",
+						ArgsUsage:   "<permission>",
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:    "platform",
+								Aliases: []string{"p"},
+								Usage:   "Platforms to use (ios, macos); defaults to every platform in the project",
+							},
+						},
+						Action: removeUsageDescription,
+					},
+				},
+			},
+		},
+	}
+}
+
+// getPlistValue prints a key's value in each chosen file
+func getPlistValue(c *cli.Context) error {
+	project, err := validateFlutterProjectForPlist()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 1 {
+		utils.Error("Key is required")
+		utils.Info("Usage: fdawg plist get <key>")
+		return fmt.Errorf("key is required")
+	}
+	key := c.Args().Get(0)
+
+	files, err := resolvePlistFiles(c, project.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{})
+	var found []string
+	for _, file := range files {
+		if _, err := os.Stat(file); os.IsNotExist(err) && c.String("file") == "" && c.Bool("entitlements") {
+			// An entitlements file that doesn't exist yet has no values
+			continue
+		}
+		doc, err := plist.Load(file)
+		if err != nil {
+			utils.Error("%v", err)
+			return err
+		}
+
+		value, ok, err := doc.Get(key)
+		if err != nil {
+			utils.Error("%v", err)
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		label := relativePlistPath(project.ProjectPath, file)
+		values[label] = value.Interface()
+		found = append(found, label)
+	}
+
+	if len(found) == 0 {
+		utils.Error("%s is not set", key)
+		return fmt.Errorf("%s is not set", key)
+	}
+
+	if c.Bool("json") {
+		output, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode values: %v", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	for _, label := range found {
+		text, err := formatPlistValue(values[label])
+		if err != nil {
+			return err
+		}
+		if len(files) == 1 {
+			fmt.Println(text)
+		} else {
+			fmt.Printf("%s: %s\n", label, text)
+		}
+	}
+	return nil
+}
+
+// setPlistValue sets a key in each chosen file
+func setPlistValue(c *cli.Context) error {
+	project, err := validateFlutterProjectForPlist()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() < 1 {
+		utils.Error("Key is required")
+		utils.Info("Usage: fdawg plist set [--type bool] <key> <value>")
+		return fmt.Errorf("key is required")
+	}
+	key := c.Args().Get(0)
+	args := c.Args().Slice()[1:]
+
+	// Build the value for each file, since an existing value decides the default type
+	valueFor := func(existing *plist.Value) (*plist.Value, error) {
+		kind := plist.Kind(strings.ToLower(c.String("type")))
+		if kind == "" {
+			kind = plist.KindString
+			if existing != nil && !existing.IsContainer() {
+				kind = existing.Kind
+			}
+		}
+		if kind == "boolean" {
+			kind = plist.KindBool
+		}
+		return buildPlistValue(kind, args)
+	}
+
+	var written []string
+	if c.Bool("entitlements") && c.String("file") == "" {
+		platforms, err := resolvePlistPlatforms(c, project.ProjectPath)
+		if err != nil {
+			return err
+		}
+		for _, platform := range platforms {
+			existing, err := plist.GetEntitlement(project.ProjectPath, platform, key)
+			if err != nil {
+				utils.Error("%v", err)
+				return err
+			}
+			var current *plist.Value
+			if len(existing) > 0 {
+				current = existing[0].Value
+			}
+			value, err := valueFor(current)
+			if err != nil {
+				utils.Error("%v", err)
+				return err
+			}
+			files, err := plist.SetEntitlement(project.ProjectPath, platform, key, value)
+			if err != nil {
+				utils.Error("Failed to set %s: %v", key, err)
+				return err
+			}
+			written = append(written, files...)
+		}
+	} else {
+		files, err := resolvePlistFiles(c, project.ProjectPath)
+		if err != nil {
+			return err
+		}
+		batch := plist.NewBatch()
+		for _, file := range files {
+			// A file given with --file is created if needed
+			err := batch.Edit(file, c.String("file") != "", func(doc *plist.Document) error {
+				existing, _, err := doc.Get(key)
+				if err != nil {
+					return err
+				}
+				value, err := valueFor(existing)
+				if err != nil {
+					return err
+				}
+				return doc.Set(key, value)
+			})
+			if err != nil {
+				utils.Error("Failed to set %s: %v", key, err)
+				return err
+			}
+		}
+		if written, err = batch.Save(); err != nil {
+			utils.Error("Failed to save: %v", err)
+			return err
+		}
+	}
+
+	if len(written) == 0 {
+		utils.Success("%s already has this value", key)
+		return nil
+	}
+	for _, file := range written {
+		utils.Success("Updated %s", relativePlistPath(project.ProjectPath, file))
+	}
+	return nil
+}
+
+// deletePlistValue removes a key from each chosen file
+func deletePlistValue(c *cli.Context) error {
+	project, err := validateFlutterProjectForPlist()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 1 {
+		utils.Error("Key is required")
+		utils.Info("Usage: fdawg plist delete <key>")
+		return fmt.Errorf("key is required")
+	}
+	key := c.Args().Get(0)
+
+	files, err := resolvePlistFiles(c, project.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	batch := plist.NewBatch()
+	for _, file := range files {
+		err := batch.Edit(file, false, func(doc *plist.Document) error {
+			_, err := doc.Delete(key)
+			return err
+		})
+		if err != nil {
+			utils.Error("Failed to delete %s: %v", key, err)
+			return err
+		}
+	}
+
+	written, err := batch.Save()
+	if err != nil {
+		utils.Error("Failed to save: %v", err)
+		return err
+	}
+
+	if len(written) == 0 {
+		utils.Warning("%s is not set", key)
+		return nil
+	}
+	for _, file := range written {
+		utils.Success("Removed %s from %s", key, relativePlistPath(project.ProjectPath, file))
+	}
+	return nil
+}
+
+// listUsageDescriptions prints the usage descriptions set on each platform, or the known permissions
+func listUsageDescriptions(c *cli.Context) error {
+	if c.Bool("known") {
+		if c.Bool("json") {
+			output, err := json.MarshalIndent(plist.UsageDescriptions, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode permissions: %v", err)
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+
+		fmt.Println(utils.Separator("=", 50))
+		utils.Info("Known permissions")
+		fmt.Println(utils.Separator("=", 50))
+		for _, usage := range plist.UsageDescriptions {
+			note := ""
+			if usage.IOSOnly {
+				note = " (iOS only)"
+			}
+			fmt.Printf("  %-16s %s%s\n", usage.Name, usage.Key, note)
+		}
+		return nil
+	}
+
+	project, err := validateFlutterProjectForPlist()
+	if err != nil {
+		return err
+	}
+
+	platforms, err := resolvePlistPlatforms(c, project.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	var all []plist.UsageDescriptionValue
+	for _, platform := range platforms {
+		values, err := plist.GetUsageDescriptions(project.ProjectPath, platform)
+		if err != nil {
+			utils.Error("%v", err)
+			return err
+		}
+		all = append(all, values...)
+	}
+
+	if c.Bool("json") {
+		if all == nil {
+			all = []plist.UsageDescriptionValue{}
+		}
+		output, err := json.MarshalIndent(all, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode descriptions: %v", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	for _, platform := range platforms {
+		fmt.Println(utils.Separator("=", 50))
+		utils.Info("%s usage descriptions", strings.ToUpper(string(platform)))
+		fmt.Println(utils.Separator("=", 50))
+
+		count := 0
+		for _, value := range all {
+			if value.Platform != platform {
+				continue
+			}
+			fmt.Printf("  %s\n    %s\n", value.Key, value.Description)
+			count++
+		}
+		if count == 0 {
+			fmt.Println("  (none)")
+		}
+	}
+	return nil
+}
+
+// setUsageDescription sets a permission's purpose string
+func setUsageDescription(c *cli.Context) error {
+	project, err := validateFlutterProjectForPlist()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 2 {
+		utils.Error("Permission and description are required")
+		utils.Info("Usage: fdawg plist usage set camera \"Used to scan QR codes\"")
+		return fmt.Errorf("permission and description are required")
+	}
+
+	platforms, err := resolvePlistPlatforms(c, project.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	result, err := plist.SetUsageDescription(project.ProjectPath, platforms, c.Args().Get(0), c.Args().Get(1))
+	if err != nil {
+		utils.Error("Failed to set usage description: %v", err)
+		return err
+	}
+
+	displayUsageResult(project.ProjectPath, c.Args().Get(0), result)
+	return nil
+}
+
+// removeUsageDescription removes a permission's purpose string
+func removeUsageDescription(c *cli.Context) error {
+	project, err := validateFlutterProjectForPlist()
+	if err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 1 {
+		utils.Error("Permission is required")
+		utils.Info("Usage: fdawg plist usage remove camera")
+		return fmt.Errorf("permission is required")
+	}
+
+	platforms, err := resolvePlistPlatforms(c, project.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	result, err := plist.RemoveUsageDescription(project.ProjectPath, platforms, c.Args().Get(0))
+	if err != nil {
+		utils.Error("Failed to remove usage description: %v", err)
+		return err
+	}
+
+	displayUsageResult(project.ProjectPath, c.Args().Get(0), result)
+	return nil
+}
+
+// displayUsageResult prints the files a usage description change wrote
+func displayUsageResult(projectPath, permission string, result *plist.UsageResult) {
+	for _, platform := range result.Skipped {
+		utils.Warning("%s is not available on %s, skipped", permission, platform)
+	}
+	if len(result.Files) == 0 {
+		utils.Success("Nothing to change")
+		return
+	}
+	for _, file := range result.Files {
+		utils.Success("Updated %s", relativePlistPath(projectPath, file))
+	}
+}
+
+// resolvePlistFiles returns the plist files chosen by the --file, --entitlements and --platform flags
+func resolvePlistFiles(c *cli.Context, projectPath string) ([]string, error) {
+	if file := c.String("file"); file != "" {
+		return []string{file}, nil
+	}
+
+	platforms, err := resolvePlistPlatforms(c, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, platform := range platforms {
+		if c.Bool("entitlements") {
+			files = append(files, plist.EntitlementsPaths(projectPath, platform)...)
+		} else {
+			files = append(files, plist.InfoPlistPath(projectPath, platform))
+		}
+	}
+	return files, nil
+}
+
+// resolvePlistPlatforms returns the platforms given with --platform, or every platform in the project
+func resolvePlistPlatforms(c *cli.Context, projectPath string) ([]plist.Platform, error) {
+	names := c.StringSlice("platform")
+	if len(names) == 0 {
+		platforms := plist.AvailablePlatforms(projectPath)
+		if len(platforms) == 0 {
+			utils.Error("No iOS or macOS Info.plist found in this project")
+			return nil, fmt.Errorf("no iOS or macOS platform found")
+		}
+		return platforms, nil
+	}
+
+	var platforms []plist.Platform
+	for _, name := range names {
+		for _, part := range strings.Split(name, ",") {
+			platform, err := plist.ParsePlatform(strings.TrimSpace(part))
+			if err != nil {
+				utils.Error("%v", err)
+				return nil, err
+			}
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms, nil
+}
+
+// buildPlistValue builds a value of a kind from command line arguments
+func buildPlistValue(kind plist.Kind, args []string) (*plist.Value, error) {
+	switch kind {
+	case plist.KindArray:
+		items := make([]*plist.Value, 0, len(args))
+		for _, arg := range args {
+			items = append(items, plist.NewString(arg))
+		}
+		return plist.NewArray(items...), nil
+	case plist.KindDict:
+		if len(args) > 0 {
+			return nil, fmt.Errorf("a dict takes no value; set its keys with parent:key")
+		}
+		return plist.NewDict(), nil
+	}
+
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected one value, got %d", len(args))
+	}
+	return plist.ParseValue(kind, args[0])
+}
+
+// formatPlistValue formats a value for printing: scalars as they are, arrays and dicts as JSON
+func formatPlistValue(value interface{}) (string, error) {
+	switch value.(type) {
+	case []interface{}, map[string]interface{}:
+		output, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode value: %v", err)
+		}
+		return string(output), nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// relativePlistPath returns a file's path relative to the project, for display
+func relativePlistPath(projectPath, file string) string {
+	if absFile, err := filepath.Abs(file); err == nil {
+		if relPath, err := filepath.Rel(projectPath, absFile); err == nil && !strings.HasPrefix(relPath, "..") {
+			return filepath.ToSlash(relPath)
+		}
+	}
+	return file
+}
+
+// validateFlutterProjectForPlist validates that we're in a Flutter project directory
+func validateFlutterProjectForPlist() (*flutter.ValidationResult, error) {
+	result, err := flutter.ValidateProject(".")
+	if err != nil {
+		utils.Error("Not a valid Flutter project: %v", err)
+		return nil, err
+	}
+
+	if !result.IsValid {
+		utils.Error("Not a valid Flutter project: %s", result.ErrorMessage)
+		return nil, fmt.Errorf("not a valid Flutter project: %s", result.ErrorMessage)
+	}
+
+	return result, nil
+}
@@ -22,6 +22,10 @@ func ServeCommand() *cli.Command {
                 Usage:   "Port to run the server on",
                 EnvVars: []string{"FDAWG_PORT"},
             },
+            &cli.BoolFlag{
+                Name:  "watch-assets",
+                Usage: "Regenerate the Dart asset file and pubspec.yaml entries when assets change",
+            },
         },
         Action: func(c *cli.Context) error {
             // Get directory from arguments or use current directory
@@ -55,7 +59,9 @@ func ServeCommand() *cli.Command {
             utils.Info("Server will run on port: %s", port)
             
             // Start the server with the project info
-            return server.Start(port, result)
+            return server.Start(port, result, server.Options{
+                WatchAssets: c.Bool("watch-assets"),
+            })
         },
     }
 }
@@ -1,12 +1,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/Jerinji2016/fdawg/pkg/asset"
 	"github.com/Jerinji2016/fdawg/pkg/flutter"
@@ -29,8 +32,10 @@ func (api *AssetAPI) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/assets/upload", api.handleUploadAsset)
 	mux.HandleFunc("/api/assets/delete", api.handleDeleteAsset)
 	mux.HandleFunc("/api/assets/download", api.handleDownloadAsset)
+	mux.HandleFunc("/api/assets/preview", api.handlePreviewAsset)
 	mux.HandleFunc("/api/assets/list", api.handleListAssets)
 	mux.HandleFunc("/api/assets/migrate", api.handleMigrateAssets)
+	mux.HandleFunc("/api/assets/events", api.handleAssetEvents)
 }
 
 // handleUploadAsset handles POST requests to upload assets
@@ -278,13 +283,42 @@ func (api *AssetAPI) handleDownloadAsset(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Set headers for file download
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", assetName))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filepath.Base(assetPath)))
 	w.Header().Set("Content-Type", "application/octet-stream")
 
 	// Serve the file
 	http.ServeFile(w, r, assetPath)
 }
 
+// handlePreviewAsset handles GET requests to display an asset inline in the browser
+func (api *AssetAPI) handlePreviewAsset(w http.ResponseWriter, r *http.Request) {
+	assetName := r.URL.Query().Get("asset_name")
+	assetType := asset.AssetType(r.URL.Query().Get("asset_type"))
+	if assetName == "" || assetType == "" {
+		http.Error(w, "Asset name and type are required", http.StatusBadRequest)
+		return
+	}
+	if !asset.IsValidAssetType(assetType) {
+		http.Error(w, "Unknown asset type", http.StatusBadRequest)
+		return
+	}
+
+	// Only serve files from inside the asset directory
+	assetDir := asset.GetAssetDir(api.project.ProjectPath)
+	assetPath := filepath.Join(assetDir, string(assetType), filepath.FromSlash(assetName))
+	if relPath, err := filepath.Rel(assetDir, assetPath); err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		http.Error(w, "Invalid asset path", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := os.Stat(assetPath); os.IsNotExist(err) {
+		http.Error(w, "Asset not found", http.StatusNotFound)
+		return
+	}
+
+	http.ServeFile(w, r, assetPath)
+}
+
 // handleListAssets handles GET requests to list assets
 func (api *AssetAPI) handleListAssets(w http.ResponseWriter, r *http.Request) {
 	// List assets
@@ -299,12 +333,29 @@ func (api *AssetAPI) handleListAssets(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Return assets as JSON
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	response := map[string]interface{}{
 		"success": true,
 		"assets":  assets,
-	})
+	}
+
+	// Include size, dimensions, duration and duplicates when requested
+	if r.URL.Query().Get("details") == "true" {
+		metadata, err := asset.GetAssetMetadata(api.project.ProjectPath)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   fmt.Sprintf("Failed to read asset details: %v", err),
+			})
+			return
+		}
+		response["metadata"] = metadata
+	}
+
+	// Return assets as JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
 // handleMigrateAssets handles POST requests to migrate assets
@@ -326,22 +377,123 @@ func (api *AssetAPI) handleMigrateAssets(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Generate the Dart asset file
-	err = asset.GenerateDartAssetFile(api.project.ProjectPath)
-	if err != nil {
-		// Just log the error, don't fail the whole operation
-		fmt.Printf("Warning: Failed to generate Dart asset file: %v\n", err)
-	}
-
 	// Return success response
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":             true,
 		"files_processed":     result.FilesProcessed,
 		"no_files_to_migrate": result.NoFilesToMigrate,
+		"references_updated":  result.ReferencesUpdated,
 	})
 }
 
+// assetEventBroker fans out asset change notifications to connected event stream clients
+type assetEventBroker struct {
+	mu      sync.Mutex
+	clients map[chan string]struct{}
+}
+
+// assetEvents is shared by the asset API routes and the asset watcher
+var assetEvents = &assetEventBroker{
+	clients: make(map[chan string]struct{}),
+}
+
+// subscribe registers a new client and returns its message channel
+func (b *assetEventBroker) subscribe() chan string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan string, 8)
+	b.clients[ch] = struct{}{}
+	return ch
+}
+
+// unsubscribe removes a client
+func (b *assetEventBroker) unsubscribe(ch chan string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.clients, ch)
+}
+
+// publish sends a message to every connected client, dropping it for clients that are not keeping up
+func (b *assetEventBroker) publish(message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.clients {
+		select {
+		case ch <- message:
+		default:
+		}
+	}
+}
+
+// handleAssetEvents handles Server-Sent Events notifying the assets page of changes
+func (api *AssetAPI) handleAssetEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Set headers for Server-Sent Events
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events := assetEvents.subscribe()
+	defer assetEvents.unsubscribe(events)
+
+	fmt.Fprintf(w, "data: %s\n\n", `{"type":"connected"}`)
+	flusher.Flush()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			// Client disconnected
+			return
+		case message := <-events:
+			fmt.Fprintf(w, "data: %s\n\n", message)
+			flusher.Flush()
+		}
+	}
+}
+
+// StartAssetWatcher watches the project's assets directory in the background,
+// regenerating the Dart asset file on change and notifying connected assets pages
+func StartAssetWatcher(ctx context.Context, project *flutter.ValidationResult) {
+	go func() {
+		err := asset.WatchAssets(ctx, project.ProjectPath, asset.WatchOptions{
+			OnChange: func(result *asset.WatchResult) {
+				event := map[string]interface{}{
+					"type":    "assets_changed",
+					"changed": result.Changed,
+				}
+				if result.Err != nil {
+					fmt.Printf("Warning: Asset watcher: %v\n", result.Err)
+					event["error"] = result.Err.Error()
+				}
+
+				data, err := json.Marshal(event)
+				if err != nil {
+					return
+				}
+				assetEvents.publish(string(data))
+			},
+		})
+		if err != nil {
+			fmt.Printf("Warning: Asset watcher stopped: %v\n", err)
+		}
+	}()
+}
+
 // SetupAssetAPIRoutes sets up asset API routes
 func SetupAssetAPIRoutes(project *flutter.ValidationResult) {
 	assetAPI := NewAssetAPI(project)
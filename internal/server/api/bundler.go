@@ -37,8 +37,9 @@ type GetBundleIDsAPIRequest struct {
 }
 
 type SetBundleIDsAPIRequest struct {
-	Universal string            `json:"universal,omitempty"`
-	Platforms map[string]string `json:"platforms,omitempty"`
+	Universal          string            `json:"universal,omitempty"`
+	Platforms          map[string]string `json:"platforms,omitempty"`
+	MoveAndroidPackage bool              `json:"move_android_package,omitempty"`
 }
 
 type ValidateBundleIDAPIRequest struct {
@@ -99,9 +100,10 @@ func (api *BundlerAPI) handleSetBundleIDs(w http.ResponseWriter, r *http.Request
 
 	// Convert to bundler request format
 	bundlerRequest := &bundler.BundleIDRequest{
-		ProjectPath: api.project.ProjectPath,
-		Universal:   req.Universal,
-		Platforms:   make(map[bundler.Platform]string),
+		ProjectPath:        api.project.ProjectPath,
+		Universal:          req.Universal,
+		Platforms:          make(map[bundler.Platform]string),
+		MoveAndroidPackage: req.MoveAndroidPackage,
 	}
 
 	// Convert platform map
@@ -3,14 +3,17 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/Jerinji2016/fdawg/internal/server/helpers"
 	"github.com/Jerinji2016/fdawg/pkg/flutter"
 	"github.com/Jerinji2016/fdawg/pkg/localization"
+	"github.com/Jerinji2016/fdawg/pkg/namer"
 )
 
 // LocalizationAPI handles localization-related API endpoints
@@ -35,7 +38,14 @@ func (api *LocalizationAPI) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/localizations/download/", api.handleDownloadLanguage)
 	mux.HandleFunc("/api/localizations/add-key", api.handleAddKey)
 	mux.HandleFunc("/api/localizations/delete-key", api.handleDeleteKey)
+	mux.HandleFunc("/api/localizations/rename-key", api.handleRenameKey)
 	mux.HandleFunc("/api/localizations/update-translations", api.handleUpdateTranslations)
+	mux.HandleFunc("/api/localizations/update-forms", api.handleUpdateForms)
+	mux.HandleFunc("/api/localizations/review-status", api.handleReviewStatus)
+	mux.HandleFunc("/api/localizations/validate", api.handleValidate)
+	mux.HandleFunc("/api/localizations/export", api.handleExport)
+	mux.HandleFunc("/api/localizations/import", api.handleImport)
+	mux.HandleFunc("/api/localizations/sync", api.handleSync)
 }
 
 // handleStatus handles GET requests to check initialization status
@@ -58,8 +68,13 @@ func (api *LocalizationAPI) handleInit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Initialize localization
-	err := localization.InitLocalization(api.project.ProjectPath)
+	// Initialize localization, using the requested backend if one was chosen
+	var err error
+	if backend := r.FormValue("backend"); backend != "" {
+		err = localization.InitLocalizationWithBackend(api.project.ProjectPath, backend)
+	} else {
+		err = localization.InitLocalization(api.project.ProjectPath)
+	}
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -92,6 +107,19 @@ func (api *LocalizationAPI) handleData(w http.ResponseWriter, r *http.Request) {
 
 	// Build response data
 	data := helpers.BuildLocalizationData(translationFiles)
+	backend := localization.DetectBackend(api.project.ProjectPath)
+	data.Backend = backend.Name()
+	data.DefaultLanguage = backend.DefaultLanguage()
+
+	// Review statuses are shown as badges and filters in the table
+	if state, err := localization.LoadReviewState(api.project.ProjectPath); err == nil {
+		helpers.ApplyReviewStatuses(&data, translationFiles, state)
+	}
+
+	// Placeholder and untranslated value warnings are shown inline in the table
+	if report, err := localization.CheckTranslations(translationFiles, data.DefaultLanguage); err == nil {
+		data.ValidationIssues = report.Issues
+	}
 
 	// Set content type
 	w.Header().Set("Content-Type", "application/json")
@@ -158,6 +186,9 @@ func (api *LocalizationAPI) handleDeleteLanguage(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// App names localized for the language go with it
+	namer.RemoveLocalizedAppNames(api.project.ProjectPath, languageCode)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -174,16 +205,15 @@ func (api *LocalizationAPI) handleDownloadLanguage(w http.ResponseWriter, r *htt
 
 	// Extract language code from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/api/localizations/download/")
-	languageCode := strings.TrimSuffix(path, ".json")
+	languageCode := strings.TrimSuffix(strings.TrimSuffix(path, ".json"), ".arb")
 
-	if languageCode == "" {
+	if languageCode == "" || strings.ContainsAny(languageCode, "/\\") {
 		http.Error(w, "Language code is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get translation file path
-	translationsDir := filepath.Join(api.project.ProjectPath, "assets/translations")
-	filePath := filepath.Join(translationsDir, languageCode+".json")
+	filePath := localization.GetTranslationFilePath(api.project.ProjectPath, languageCode)
 
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
@@ -192,7 +222,7 @@ func (api *LocalizationAPI) handleDownloadLanguage(w http.ResponseWriter, r *htt
 	}
 
 	// Set headers for file download
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.json", languageCode))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filepath.Base(filePath)))
 	w.Header().Set("Content-Type", "application/json")
 
 	// Serve the file
@@ -223,6 +253,8 @@ func (api *LocalizationAPI) handleAddKey(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	api.regenerateLocaleKeys()
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -254,6 +286,8 @@ func (api *LocalizationAPI) handleDeleteKey(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	api.regenerateLocaleKeys()
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -261,6 +295,47 @@ func (api *LocalizationAPI) handleDeleteKey(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+// handleRenameKey handles POST requests to rename a translation key or subtree in all languages
+func (api *LocalizationAPI) handleRenameKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	oldKey := r.FormValue("old_key")
+	newKey := r.FormValue("new_key")
+	if oldKey == "" || newKey == "" {
+		http.Error(w, "Old and new keys are required", http.StatusBadRequest)
+		return
+	}
+
+	updateCode := r.FormValue("update_code") == "true"
+
+	result, err := localization.RenameTranslationKey(api.project.ProjectPath, oldKey, newKey, updateCode)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to rename translation key: %v", err),
+		})
+		return
+	}
+
+	api.regenerateLocaleKeys()
+
+	message := fmt.Sprintf("Renamed %d key(s) from %s to %s", len(result.Keys), oldKey, newKey)
+	if result.CodeError != "" {
+		message += fmt.Sprintf(", but updating references in lib/ failed: %s", result.CodeError)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": message,
+		"result":  result,
+	})
+}
+
 // handleUpdateTranslations handles POST requests to update translations
 func (api *LocalizationAPI) handleUpdateTranslations(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -287,8 +362,20 @@ func (api *LocalizationAPI) handleUpdateTranslations(w http.ResponseWriter, r *h
 		return
 	}
 
-	// Update translations using the localization package
-	err := localization.InsertTranslationKey(api.project.ProjectPath, translationKey, translations)
+	// Plural and gender keys are edited one "form: text" per line, so parse the
+	// lines back into forms rather than replacing the forms with a string
+	var err error
+	messages, _ := localization.GetTranslationMessages(api.project.ProjectPath, translationKey)
+	if kind, argument := localization.MessageKind(messages); kind != localization.MessageText {
+		updated := make(map[string]localization.Message)
+		for language, text := range translations {
+			updated[language] = localization.ParseFormsText(kind, argument, text)
+		}
+		err = localization.SetTranslationMessages(api.project.ProjectPath, translationKey, updated)
+	} else {
+		// Update translations using the localization package
+		err = localization.InsertTranslationKey(api.project.ProjectPath, translationKey, translations)
+	}
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -298,6 +385,18 @@ func (api *LocalizationAPI) handleUpdateTranslations(w http.ResponseWriter, r *h
 		return
 	}
 
+	// Languages filled in by machine translation are sent as a JSON array
+	var machineLanguages []string
+	if data := r.FormValue("machine_languages"); data != "" {
+		if err := json.Unmarshal([]byte(data), &machineLanguages); err != nil {
+			http.Error(w, "Invalid machine languages JSON", http.StatusBadRequest)
+			return
+		}
+	}
+	api.updateReviewStatuses(translationKey, messages, machineLanguages)
+
+	api.regenerateLocaleKeys()
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -305,6 +404,270 @@ func (api *LocalizationAPI) handleUpdateTranslations(w http.ResponseWriter, r *h
 	})
 }
 
+// handleUpdateForms handles POST requests to set the forms of a plural or gender key
+func (api *LocalizationAPI) handleUpdateForms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	translationKey := r.FormValue("translation_key")
+	if translationKey == "" {
+		http.Error(w, "Translation key is required", http.StatusBadRequest)
+		return
+	}
+
+	kind := r.FormValue("kind")
+	if kind != localization.MessagePlural && kind != localization.MessageGender {
+		http.Error(w, "Kind must be plural or gender", http.StatusBadRequest)
+		return
+	}
+
+	// Forms are sent as {"<language>": {"<form>": "<text>"}}
+	var forms map[string]map[string]string
+	if err := json.Unmarshal([]byte(r.FormValue("forms")), &forms); err != nil {
+		http.Error(w, "Invalid forms JSON", http.StatusBadRequest)
+		return
+	}
+
+	messages := make(map[string]localization.Message)
+	for language, languageForms := range forms {
+		messages[language] = localization.Message{
+			Kind:     kind,
+			Forms:    languageForms,
+			Argument: r.FormValue("argument"),
+		}
+	}
+
+	previous, _ := localization.GetTranslationMessages(api.project.ProjectPath, translationKey)
+
+	if err := localization.SetTranslationMessages(api.project.ProjectPath, translationKey, messages); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to update forms: %v", err),
+		})
+		return
+	}
+
+	api.updateReviewStatuses(translationKey, previous, nil)
+
+	api.regenerateLocaleKeys()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Forms for %s updated successfully", translationKey),
+	})
+}
+
+// handleReviewStatus handles POST requests to set the review status of a translation
+func (api *LocalizationAPI) handleReviewStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	translationKey := r.FormValue("translation_key")
+	language := r.FormValue("language")
+	status := r.FormValue("status")
+	if translationKey == "" || language == "" || status == "" {
+		http.Error(w, "Translation key, language and status are required", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := localization.SetReviewStatus(api.project.ProjectPath, language, translationKey, status); err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("%s (%s) marked as %s", translationKey, language, status),
+	})
+}
+
+// updateReviewStatuses marks machine translated values after an update, and flags
+// the other translations of a key for review when its default language text changed
+func (api *LocalizationAPI) updateReviewStatuses(key string, previous map[string]localization.Message, machineLanguages []string) {
+	defaultLanguage := localization.DetectBackend(api.project.ProjectPath).DefaultLanguage()
+
+	if before, ok := previous[defaultLanguage]; ok {
+		current, _ := localization.GetTranslationMessages(api.project.ProjectPath, key)
+		if !reflect.DeepEqual(before, current[defaultLanguage]) {
+			if err := localization.MarkSourceChanged(api.project.ProjectPath, key); err != nil {
+				fmt.Printf("Warning: Failed to flag translations for review: %v\n", err)
+			}
+		}
+	}
+
+	if err := localization.MarkReviewStatus(api.project.ProjectPath, key, machineLanguages, localization.StatusMachine); err != nil {
+		fmt.Printf("Warning: Failed to record machine translations: %v\n", err)
+	}
+}
+
+// regenerateLocaleKeys keeps the generated LocaleKeys class in sync after keys or
+// default language values change
+func (api *LocalizationAPI) regenerateLocaleKeys() {
+	if localization.DetectBackend(api.project.ProjectPath).Name() != localization.BackendEasyLocalization {
+		return
+	}
+
+	if err := localization.GenerateDartLocaleKeysFile(api.project.ProjectPath); err != nil {
+		// Just log the error, don't fail the whole operation
+		fmt.Printf("Warning: Failed to generate Dart LocaleKeys file: %v\n", err)
+	}
+}
+
+// handleValidate handles GET requests to check translations against the default language
+func (api *LocalizationAPI) handleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := localization.ValidateTranslations(api.project.ProjectPath)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to validate translations: %v", err),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"report":  report,
+	})
+}
+
+// handleExport handles GET requests to download translations as CSV, XLSX or XLIFF
+func (api *LocalizationAPI) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = localization.ExportCSV
+	}
+
+	data, err := localization.ExportTranslations(api.project.ProjectPath, localization.ExportOptions{
+		Format:      format,
+		MissingOnly: r.URL.Query().Get("missing_only") == "true",
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to export translations: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	contentTypes := map[string]string{
+		localization.ExportCSV:   "text/csv; charset=utf-8",
+		localization.ExportXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		localization.ExportXLIFF: "application/xliff+xml",
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", localization.ExportFileName(format)))
+	w.Header().Set("Content-Type", contentTypes[format])
+	w.Write(data)
+}
+
+// handleImport handles POST requests to merge an uploaded CSV, XLSX or XLIFF file
+func (api *LocalizationAPI) handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// Parse multipart form data (max 32MB)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to parse form data: %v", err),
+		})
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   "Import file is required",
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to read import file: %v", err),
+		})
+		return
+	}
+
+	result, err := localization.ImportTranslationData(api.project.ProjectPath, header.Filename, data, localization.ImportOptions{
+		Overwrite: r.FormValue("overwrite") == "true",
+		DryRun:    r.FormValue("dry_run") == "true",
+	})
+	if err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to import translations: %v", err),
+		})
+		return
+	}
+
+	if !result.DryRun && result.Updated > 0 {
+		api.regenerateLocaleKeys()
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"result":  result,
+	})
+}
+
+// handleSync handles POST requests to rebuild the non-default languages from the default language
+func (api *LocalizationAPI) handleSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	result, err := localization.SyncTranslations(api.project.ProjectPath, localization.SyncOptions{
+		UseSource: r.FormValue("use_source") == "true",
+		DryRun:    r.FormValue("dry_run") == "true",
+	})
+	if err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to sync translations: %v", err),
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"result":  result,
+	})
+}
+
 // SetupLocalizationAPIRoutes sets up localization API routes
 func SetupLocalizationAPIRoutes(project *flutter.ValidationResult) {
 	localizationAPI := NewLocalizationAPI(project)
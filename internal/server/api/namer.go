@@ -3,10 +3,12 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/Jerinji2016/fdawg/internal/server/helpers"
 	"github.com/Jerinji2016/fdawg/pkg/flutter"
+	"github.com/Jerinji2016/fdawg/pkg/localization"
 	"github.com/Jerinji2016/fdawg/pkg/namer"
 )
 
@@ -27,6 +29,9 @@ func (api *NamerAPI) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/namer/get", api.handleGetAppNames)
 	mux.HandleFunc("/api/namer/set", api.handleSetAppNames)
 	mux.HandleFunc("/api/namer/platforms", api.handleGetPlatforms)
+	mux.HandleFunc("/api/namer/locales", api.handleLocales)
+	mux.HandleFunc("/api/namer/validate", api.handleValidate)
+	mux.HandleFunc("/api/namer/web", api.handleWebMetadata)
 }
 
 // GetAppNamesRequest represents a request to get app names
@@ -38,6 +43,7 @@ type GetAppNamesRequest struct {
 type SetAppNamesAPIRequest struct {
 	Universal string            `json:"universal,omitempty"`
 	Platforms map[string]string `json:"platforms,omitempty"`
+	Locale    string            `json:"locale,omitempty"`
 }
 
 // PlatformsResponse represents available platforms response
@@ -116,6 +122,36 @@ func (api *NamerAPI) handleSetAppNames(w http.ResponseWriter, r *http.Request) {
 		namerRequest.Platforms[platform] = appName
 	}
 
+	namerRequest.Locale = req.Locale
+
+	// Names that would break a build or be rejected by a store are refused before anything is written
+	report, err := namer.ValidateAppNames(namerRequest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := report.Err(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Localized names report what they couldn't apply, e.g. on web
+	if req.Locale != "" {
+		result, err := namer.SetLocalizedAppNames(namerRequest)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "success",
+			"result":   result,
+			"warnings": report.Issues,
+		})
+		return
+	}
+
 	// Set app names
 	if err := namer.SetAppNames(namerRequest); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -136,6 +172,49 @@ func (api *NamerAPI) handleSetAppNames(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":    "success",
 		"app_names": updatedResult,
+		"warnings":  report.Issues,
+	})
+}
+
+// handleValidate handles POST requests to check app names against platform rules.
+// With no names in the body, the project's current names are checked.
+func (api *NamerAPI) handleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SetAppNamesAPIRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var report *namer.ValidationReport
+	var err error
+	if req.Universal == "" && len(req.Platforms) == 0 {
+		report, err = namer.ValidateCurrentAppNames(api.project.ProjectPath, nil)
+	} else {
+		namerRequest := &namer.SetAppNameRequest{
+			ProjectPath: api.project.ProjectPath,
+			Universal:   req.Universal,
+			Platforms:   make(map[namer.Platform]string),
+			Locale:      req.Locale,
+		}
+		for platformStr, appName := range req.Platforms {
+			namerRequest.Platforms[namer.Platform(strings.ToLower(platformStr))] = appName
+		}
+		report, err = namer.ValidateAppNames(namerRequest)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"report": report,
 	})
 }
 
@@ -201,6 +280,103 @@ func (api *NamerAPI) handleGetPlatforms(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
+// handleLocales lists the project's languages with their localized app names on
+// GET, and removes the names of a locale on POST
+func (api *NamerAPI) handleLocales(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		names, err := namer.GetLocalizedAppNames(api.project.ProjectPath)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		languages := []string{}
+		if localization.IsInitialized(api.project.ProjectPath).IsInitialized {
+			if files, err := localization.ListTranslationFiles(api.project.ProjectPath); err == nil {
+				for _, file := range files {
+					languages = append(languages, file.Language)
+				}
+			}
+		}
+		sort.Strings(languages)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"languages":             languages,
+			"localized_names":       names,
+			"localizable_platforms": namer.LocalizablePlatforms,
+		})
+	case http.MethodPost:
+		locale := r.FormValue("locale")
+		if locale == "" {
+			http.Error(w, "Locale is required", http.StatusBadRequest)
+			return
+		}
+
+		result, err := namer.RemoveLocalizedAppNames(api.project.ProjectPath, locale)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"result": result,
+		})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleWebMetadata handles GET requests for the web app metadata and POST requests to
+// change it. POST bodies list only the fields to change.
+func (api *NamerAPI) handleWebMetadata(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		metadata, err := namer.GetWebMetadata(api.project.ProjectPath)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metadata)
+	case http.MethodPost:
+		var update namer.WebMetadataUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		report, err := namer.SetWebMetadata(api.project.ProjectPath, update)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if report != nil && report.Errors > 0 {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		metadata, err := namer.GetWebMetadata(api.project.ProjectPath)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "success",
+			"metadata": metadata,
+			"warnings": report.Issues,
+		})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 // SetupNamerAPIRoutes sets up namer API routes
 func SetupNamerAPIRoutes(project *flutter.ValidationResult) {
 	namerAPI := NewNamerAPI(project)
@@ -4,15 +4,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/Jerinji2016/fdawg/pkg/config"
 	"github.com/Jerinji2016/fdawg/pkg/flutter"
+	"github.com/Jerinji2016/fdawg/pkg/localization"
 	"github.com/Jerinji2016/fdawg/pkg/translate"
 )
 
 // TranslationAPI handles translation-related API endpoints
 type TranslationAPI struct {
 	project *flutter.ValidationResult
+
+	// glossaryMutex keeps concurrent glossary edits from overwriting each other
+	glossaryMutex sync.Mutex
 }
 
 // NewTranslationAPI creates a new TranslationAPI instance
@@ -28,6 +34,8 @@ func (api *TranslationAPI) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/localizations/translate-cell", api.handleTranslateCell)
 	mux.HandleFunc("/api/localizations/translate-row", api.handleTranslateRow)
 	mux.HandleFunc("/api/localizations/update-api-key", api.handleUpdateAPIKey)
+	mux.HandleFunc("/api/localizations/memory", api.handleMemory)
+	mux.HandleFunc("/api/localizations/glossary", api.handleGlossary)
 }
 
 // handleTranslateConfig handles GET requests to get translation configuration
@@ -108,6 +116,15 @@ func (api *TranslationAPI) handleTranslateCell(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Plural and gender keys are translated one form at a time
+	if messages, err := localization.GetTranslationMessages(api.project.ProjectPath, translationKey); err == nil {
+		if kind, _ := localization.MessageKind(messages); kind != localization.MessageText {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(translateMessageCell(service, translationKey, kind, sourceLanguage, targetLanguage, messages))
+			return
+		}
+	}
+
 	// Create translation request
 	req := translate.CellTranslationRequest{
 		Key:                  translationKey,
@@ -199,6 +216,15 @@ func (api *TranslationAPI) handleTranslateRow(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Plural and gender keys are translated one form at a time
+	if messages, err := localization.GetTranslationMessages(api.project.ProjectPath, translationKey); err == nil {
+		if kind, _ := localization.MessageKind(messages); kind != localization.MessageText {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(translateMessageRow(service, translationKey, kind, sourceLanguage, targetLanguages, messages))
+			return
+		}
+	}
+
 	// Create translation request
 	req := translate.RowTranslationRequest{
 		Key:                  translationKey,
@@ -270,6 +296,245 @@ func (api *TranslationAPI) handleUpdateAPIKey(w http.ResponseWriter, r *http.Req
 	})
 }
 
+// handleMemory handles GET requests to list the translation memory and POST
+// requests to set, delete or clear its entries
+func (api *TranslationAPI) handleMemory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	memory, err := translate.LoadMemory(api.project.ProjectPath)
+	if err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"entries": memory.Entries(),
+		})
+		return
+	}
+
+	sourceLanguage := r.FormValue("source_language")
+	if sourceLanguage == "" {
+		sourceLanguage = localization.DetectBackend(api.project.ProjectPath).DefaultLanguage()
+	}
+	targetLanguage := r.FormValue("target_language")
+	source := r.FormValue("source")
+
+	var message string
+	switch r.FormValue("action") {
+	case "set":
+		target := r.FormValue("target")
+		if targetLanguage == "" || source == "" || target == "" {
+			http.Error(w, "Target language, source and target are required", http.StatusBadRequest)
+			return
+		}
+		memory.Set(sourceLanguage, targetLanguage, source, target, translate.ManualProvider, "")
+		message = fmt.Sprintf("Saved the %s translation of \"%s\"", targetLanguage, source)
+	case "delete":
+		if targetLanguage == "" || source == "" {
+			http.Error(w, "Target language and source are required", http.StatusBadRequest)
+			return
+		}
+		if !memory.Delete(sourceLanguage, targetLanguage, source) {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   "Translation memory entry not found",
+			})
+			return
+		}
+		message = fmt.Sprintf("Deleted the %s translation of \"%s\"", targetLanguage, source)
+	case "clear":
+		removed := memory.Clear(targetLanguage)
+		message = fmt.Sprintf("Deleted %d translation memory entries", removed)
+	default:
+		http.Error(w, "Action must be set, delete or clear", http.StatusBadRequest)
+		return
+	}
+
+	if err := memory.Save(); err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": message,
+		"entries": memory.Entries(),
+	})
+}
+
+// handleGlossary handles GET requests to list the glossary and POST requests to
+// add or remove terms
+func (api *TranslationAPI) handleGlossary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	api.glossaryMutex.Lock()
+	defer api.glossaryMutex.Unlock()
+
+	glossary, err := translate.LoadGlossary(api.project.ProjectPath)
+	if err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"terms":   glossary.Terms,
+		})
+		return
+	}
+
+	term := strings.TrimSpace(r.FormValue("term"))
+	if term == "" {
+		http.Error(w, "Term is required", http.StatusBadRequest)
+		return
+	}
+
+	var message string
+	switch r.FormValue("action") {
+	case "add":
+		// Translations are optional; without them the term is kept as is
+		var translations map[string]string
+		if data := r.FormValue("translations"); data != "" {
+			if err := json.Unmarshal([]byte(data), &translations); err != nil {
+				http.Error(w, "Invalid translations JSON", http.StatusBadRequest)
+				return
+			}
+			for language, translation := range translations {
+				if strings.TrimSpace(translation) == "" {
+					delete(translations, language)
+				}
+			}
+			if len(translations) == 0 {
+				translations = nil
+			}
+		}
+		if err := glossary.Set(term, translations); err != nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		message = fmt.Sprintf("Glossary term %s saved", term)
+	case "remove":
+		if !glossary.Remove(term) {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   fmt.Sprintf("Term %s is not in the glossary", term),
+			})
+			return
+		}
+		message = fmt.Sprintf("Glossary term %s removed", term)
+	default:
+		http.Error(w, "Action must be add or remove", http.StatusBadRequest)
+		return
+	}
+
+	if err := glossary.Save(); err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": message,
+		"terms":   glossary.Terms,
+	})
+}
+
+// translateMessageCell translates the forms of a plural or gender key into one language.
+// The result is formatted one "form: text" per line, as the translation table shows it.
+func translateMessageCell(service *translate.Service, key, kind, sourceLanguage, targetLanguage string, messages map[string]localization.Message) *translate.CellTranslationResponse {
+	response := &translate.CellTranslationResponse{
+		Key:            key,
+		TargetLanguage: targetLanguage,
+	}
+
+	// If source language is not specified, use the first language with text
+	if sourceLanguage == "" {
+		for lang, message := range messages {
+			if lang != targetLanguage && localization.FormatFormsText(message) != "" {
+				sourceLanguage = lang
+				break
+			}
+		}
+	}
+
+	source, ok := messages[sourceLanguage]
+	if !ok || source.Kind != kind || localization.FormatFormsText(source) == "" {
+		response.Error = fmt.Sprintf("no source text found for language: %s", sourceLanguage)
+		return response
+	}
+	response.SourceLanguage = sourceLanguage
+
+	forms, err := service.TranslateForms(source.Forms, sourceLanguage, targetLanguage, localization.RequiredForms(kind, targetLanguage))
+	if err != nil {
+		response.Error = err.Error()
+		return response
+	}
+
+	response.TranslatedText = localization.FormatFormsText(localization.Message{Kind: kind, Forms: forms, Argument: source.Argument})
+	response.Success = true
+
+	return response
+}
+
+// translateMessageRow translates the forms of a plural or gender key into every
+// target language that has no text yet
+func translateMessageRow(service *translate.Service, key, kind, sourceLanguage string, targetLanguages []string, messages map[string]localization.Message) *translate.RowTranslationResponse {
+	response := &translate.RowTranslationResponse{
+		Key:          key,
+		Translations: make(map[string]translate.TranslationResult),
+		Errors:       make(map[string]string),
+	}
+
+	for _, targetLanguage := range targetLanguages {
+		if targetLanguage == sourceLanguage || strings.TrimSpace(localization.FormatFormsText(messages[targetLanguage])) != "" {
+			continue
+		}
+
+		cell := translateMessageCell(service, key, kind, sourceLanguage, targetLanguage, messages)
+		response.Translations[targetLanguage] = translate.TranslationResult{
+			Text:       cell.TranslatedText,
+			SourceLang: sourceLanguage,
+			TargetLang: targetLanguage,
+			Success:    cell.Success,
+			Error:      cell.Error,
+		}
+		if !cell.Success {
+			response.Errors[targetLanguage] = cell.Error
+		}
+	}
+
+	return response
+}
+
 // SetupTranslationAPIRoutes sets up translation API routes
 func SetupTranslationAPIRoutes(project *flutter.ValidationResult) {
 	translationAPI := NewTranslationAPI(project)
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/Jerinji2016/fdawg/pkg/localization"
@@ -9,9 +10,13 @@ import (
 
 // LocalizationData represents the data structure for localization API responses
 type LocalizationData struct {
-	Languages       []LanguageInfo    `json:"languages"`
-	TranslationKeys []TranslationKey  `json:"translationKeys"`
-	Stats           LocalizationStats `json:"stats"`
+	Backend          string                         `json:"backend"`
+	DefaultLanguage  string                         `json:"defaultLanguage"`
+	Languages        []LanguageInfo                 `json:"languages"`
+	TranslationKeys  []TranslationKey               `json:"translationKeys"`
+	Stats            LocalizationStats              `json:"stats"`
+	PluralIssues     []localization.PluralIssue     `json:"pluralIssues"`
+	ValidationIssues []localization.ValidationIssue `json:"validationIssues"`
 }
 
 // LanguageInfo represents information about a supported language
@@ -21,12 +26,22 @@ type LanguageInfo struct {
 	Flag           string `json:"flag"`
 	CompletionRate int    `json:"completionRate"`
 	MissingKeys    int    `json:"missingKeys"`
+	// StatusCounts counts the language's translated values by review status
+	StatusCounts map[string]int `json:"statusCounts,omitempty"`
 }
 
 // TranslationKey represents a translation key with its values across languages
 type TranslationKey struct {
 	Key          string            `json:"key"`
 	Translations map[string]string `json:"translations"`
+	Description  string            `json:"description,omitempty"`
+	Placeholders []string          `json:"placeholders,omitempty"`
+	// Kind is plural or gender for keys with several forms, which are shown one "form: text" per line
+	Kind string `json:"kind,omitempty"`
+	// RequiredForms lists the forms each language needs for plural and gender keys
+	RequiredForms map[string][]string `json:"requiredForms,omitempty"`
+	// Statuses is the review status of each translated value but the default language's
+	Statuses map[string]string `json:"statuses,omitempty"`
 }
 
 // LocalizationStats represents overall localization statistics
@@ -35,6 +50,10 @@ type LocalizationStats struct {
 	TranslationKeys      int `json:"translationKeys"`
 	MissingTranslations  int `json:"missingTranslations"`
 	CompletionRate       int `json:"completionRate"`
+	// StatusCounts counts the translated values of every language but the default one by review status
+	StatusCounts map[string]int `json:"statusCounts"`
+	// ReviewedRate is the percentage of values in every language but the default one that are translated and reviewed
+	ReviewedRate int `json:"reviewedRate"`
 }
 
 // BuildLocalizationData builds the localization data from translation files
@@ -81,9 +100,20 @@ func BuildLocalizationData(translationFiles []localization.TranslationFile) Loca
 			Translations: make(map[string]string),
 		}
 
+		messages := make(map[string]localization.Message)
 		for _, file := range translationFiles {
 			value := getValueFromData(file.Data, key)
 			translationKey.Translations[file.Language] = value
+			messages[file.Language] = localization.ParseMessage(getRawValueFromData(file.Data, key))
+			applyKeyMetadata(&translationKey, file.Metadata[key])
+		}
+
+		if kind, _ := localization.MessageKind(messages); kind != localization.MessageText {
+			translationKey.Kind = kind
+			translationKey.RequiredForms = make(map[string][]string)
+			for _, file := range translationFiles {
+				translationKey.RequiredForms[file.Language] = localization.RequiredForms(kind, file.Language)
+			}
 		}
 
 		data.TranslationKeys = append(data.TranslationKeys, translationKey)
@@ -125,6 +155,8 @@ func BuildLocalizationData(translationFiles []localization.TranslationFile) Loca
 		data.Languages = append(data.Languages, *langInfo)
 	}
 
+	data.PluralIssues = localization.CheckPluralForms(translationFiles)
+
 	// Calculate overall stats
 	data.Stats.SupportedLanguages = len(translationFiles)
 	data.Stats.TranslationKeys = totalKeys
@@ -142,6 +174,62 @@ func BuildLocalizationData(translationFiles []localization.TranslationFile) Loca
 	return data
 }
 
+// ApplyReviewStatuses adds the review status of each translated value, and counts
+// by status per language and overall, to the localization data
+func ApplyReviewStatuses(data *LocalizationData, translationFiles []localization.TranslationFile, state *localization.ReviewState) {
+	statuses := localization.TranslationReviewStatuses(translationFiles, data.DefaultLanguage, state)
+	counts := localization.ReviewStatusCounts(translationFiles, data.DefaultLanguage, state)
+
+	for i := range data.TranslationKeys {
+		translationKey := &data.TranslationKeys[i]
+		for language, keys := range statuses {
+			if status, ok := keys[translationKey.Key]; ok {
+				if translationKey.Statuses == nil {
+					translationKey.Statuses = make(map[string]string)
+				}
+				translationKey.Statuses[language] = status
+			}
+		}
+	}
+
+	data.Stats.StatusCounts = make(map[string]int)
+	for _, status := range localization.ReviewStatuses {
+		data.Stats.StatusCounts[status] = 0
+	}
+	for i := range data.Languages {
+		languageCounts, ok := counts[data.Languages[i].Code]
+		if !ok {
+			continue
+		}
+		data.Languages[i].StatusCounts = languageCounts
+		for status, count := range languageCounts {
+			data.Stats.StatusCounts[status] += count
+		}
+	}
+
+	if possible := len(counts) * data.Stats.TranslationKeys; possible > 0 {
+		data.Stats.ReviewedRate = data.Stats.StatusCounts[localization.StatusReviewed] * 100 / possible
+	}
+}
+
+// applyKeyMetadata copies the description and placeholder names from ARB @key metadata
+func applyKeyMetadata(translationKey *TranslationKey, metadata map[string]interface{}) {
+	if metadata == nil {
+		return
+	}
+
+	if description, ok := metadata["description"].(string); ok && translationKey.Description == "" {
+		translationKey.Description = description
+	}
+
+	if placeholders, ok := metadata["placeholders"].(map[string]interface{}); ok && len(translationKey.Placeholders) == 0 {
+		for name := range placeholders {
+			translationKey.Placeholders = append(translationKey.Placeholders, name)
+		}
+		sort.Strings(translationKey.Placeholders)
+	}
+}
+
 // collectKeys recursively collects all keys from a nested map
 func collectKeys(data map[string]interface{}, prefix string, keys map[string]bool) {
 	for key, value := range data {
@@ -150,7 +238,7 @@ func collectKeys(data map[string]interface{}, prefix string, keys map[string]boo
 			fullKey = prefix + "." + key
 		}
 
-		if nestedMap, ok := value.(map[string]interface{}); ok {
+		if nestedMap, ok := value.(map[string]interface{}); ok && !localization.IsMessageMap(nestedMap) {
 			collectKeys(nestedMap, fullKey, keys)
 		} else {
 			keys[fullKey] = true
@@ -158,8 +246,25 @@ func collectKeys(data map[string]interface{}, prefix string, keys map[string]boo
 	}
 }
 
-// getValueFromData retrieves a value from nested map using dot notation
+// getValueFromData retrieves a value from nested map using dot notation.
+// Plural and gender messages are formatted as one "form: text" line per form.
 func getValueFromData(data map[string]interface{}, key string) string {
+	value := getRawValueFromData(data, key)
+	if value == nil {
+		return ""
+	}
+
+	if message := localization.ParseMessage(value); message.Kind != localization.MessageText {
+		return localization.FormatFormsText(message)
+	}
+	if str, ok := value.(string); ok {
+		return str
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// getRawValueFromData retrieves the unformatted value from nested map using dot notation
+func getRawValueFromData(data map[string]interface{}, key string) interface{} {
 	parts := strings.Split(key, ".")
 	current := data
 
@@ -167,23 +272,20 @@ func getValueFromData(data map[string]interface{}, key string) string {
 		if i == len(parts)-1 {
 			// Last part, get the value
 			if value, exists := current[part]; exists {
-				if str, ok := value.(string); ok {
-					return str
-				}
-				return fmt.Sprintf("%v", value)
+				return value
 			}
-			return ""
+			return nil
 		} else {
 			// Navigate deeper
 			if nestedMap, ok := current[part].(map[string]interface{}); ok {
 				current = nestedMap
 			} else {
-				return ""
+				return nil
 			}
 		}
 	}
 
-	return ""
+	return nil
 }
 
 // GetLanguageName returns the display name for a language code
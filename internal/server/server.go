@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"io/fs"
@@ -20,8 +21,14 @@ type ServerData struct {
 	SelectedEnvFile *environment.EnvFile
 }
 
+// Options contains optional server features
+type Options struct {
+	// WatchAssets regenerates the Dart asset file whenever the assets directory changes
+	WatchAssets bool
+}
+
 // Start initializes and starts the HTTP server
-func Start(port string, project *flutter.ValidationResult) error {
+func Start(port string, project *flutter.ValidationResult, options Options) error {
 	// Create base server data
 	baseData := &ServerData{
 		Project: project,
@@ -30,6 +37,12 @@ func Start(port string, project *flutter.ValidationResult) error {
 	// Set up API routes
 	api.SetupAPIRoutes(project)
 
+	// Start background watchers
+	if options.WatchAssets {
+		utils.Info("Watching assets directory for changes")
+		api.StartAssetWatcher(context.Background(), project)
+	}
+
 	// Set up routes
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		data := *baseData
@@ -218,6 +218,32 @@ document.addEventListener('DOMContentLoaded', function() {
     // Load assets on page load
     loadAssets();
 
+    // Reload assets when the server's asset watcher reports changes
+    if (window.EventSource) {
+        const assetEventSource = new EventSource('/api/assets/events');
+
+        assetEventSource.onmessage = function(event) {
+            try {
+                const data = JSON.parse(event.data);
+                if (data.type !== 'assets_changed') return;
+
+                loadAssets();
+
+                if (data.error) {
+                    showErrorToast(`Failed to regenerate assets: ${data.error}`);
+                } else {
+                    showInfoToast(`${data.changed.length} asset change(s) detected. Dart asset file regenerated.`, 'Assets Updated');
+                }
+            } catch (error) {
+                console.error('Error parsing asset event:', error);
+            }
+        };
+
+        window.addEventListener('beforeunload', function() {
+            assetEventSource.close();
+        });
+    }
+
     // Search functionality
     const searchInput = document.getElementById('asset-search');
 
@@ -292,7 +318,7 @@ document.addEventListener('DOMContentLoaded', function() {
         };
 
         // Fetch assets from the server
-        fetch('/api/assets/list')
+        fetch('/api/assets/list?details=true')
             .then(response => response.json())
             .then(data => {
                 // Hide loading indicator
@@ -310,9 +336,15 @@ document.addEventListener('DOMContentLoaded', function() {
                         assetCounts[type] = fileArray.length;
                         totalAssets += fileArray.length;
 
+                        // Index metadata by file name for this type
+                        const metadataByName = {};
+                        ((data.metadata && data.metadata[type]) || []).forEach(item => {
+                            metadataByName[item.name] = item;
+                        });
+
                         // Add rows for each asset
                         fileArray.forEach(file => {
-                            const row = createAssetRow(file, type);
+                            const row = createAssetRow(file, type, metadataByName[file]);
                             tableBody.appendChild(row);
                         });
                     }
@@ -340,7 +372,7 @@ document.addEventListener('DOMContentLoaded', function() {
     }
 
     // Function to create an asset row
-    function createAssetRow(fileName, assetType) {
+    function createAssetRow(fileName, assetType, metadata) {
         const row = document.createElement('tr');
         row.className = 'asset-row';
 
@@ -377,6 +409,20 @@ document.addEventListener('DOMContentLoaded', function() {
 
         previewCell.innerHTML = `<i class="fas ${iconClass}"></i>`;
 
+        // Show a thumbnail for images and SVGs
+        if (assetType === 'images' || assetType === 'svgs') {
+            const thumbnail = document.createElement('img');
+            thumbnail.src = `/api/assets/preview?asset_name=${encodeURIComponent(fileName)}&asset_type=${encodeURIComponent(assetType)}`;
+            thumbnail.alt = fileName;
+            thumbnail.loading = 'lazy';
+            thumbnail.style.maxWidth = '48px';
+            thumbnail.style.maxHeight = '48px';
+            thumbnail.addEventListener('load', function() {
+                previewCell.innerHTML = '';
+                previewCell.appendChild(thumbnail);
+            });
+        }
+
         // Name cell
         const nameCell = document.createElement('td');
         nameCell.className = 'asset-name';
@@ -392,6 +438,33 @@ document.addEventListener('DOMContentLoaded', function() {
         pathCell.className = 'asset-path';
         pathCell.textContent = `assets/${assetType}/${fileName}`;
 
+        // Details cell
+        const detailsCell = document.createElement('td');
+        detailsCell.className = 'asset-details';
+        if (metadata) {
+            const details = [formatAssetSize(metadata.size)];
+            if (metadata.width && metadata.height) {
+                details.push(`${metadata.width}×${metadata.height}`);
+            }
+            if (metadata.duration) {
+                details.push(`${metadata.duration.toFixed(1)}s`);
+            }
+            if (metadata.frame_rate) {
+                details.push(`${metadata.frame_rate} fps`);
+            }
+            detailsCell.textContent = details.join(' · ');
+
+            // Flag files with identical content
+            if (metadata.duplicates && metadata.duplicates.length > 0) {
+                const duplicateBadge = document.createElement('span');
+                duplicateBadge.className = 'status-badge warning';
+                duplicateBadge.style.marginLeft = '8px';
+                duplicateBadge.textContent = 'Duplicate';
+                duplicateBadge.title = `Identical to:\n${metadata.duplicates.join('\n')}`;
+                detailsCell.appendChild(duplicateBadge);
+            }
+        }
+
         // Actions cell
         const actionsCell = document.createElement('td');
         actionsCell.className = 'asset-actions';
@@ -420,11 +493,25 @@ document.addEventListener('DOMContentLoaded', function() {
         row.appendChild(nameCell);
         row.appendChild(typeCell);
         row.appendChild(pathCell);
+        row.appendChild(detailsCell);
         row.appendChild(actionsCell);
 
         return row;
     }
 
+    // Function to format a file size in bytes
+    function formatAssetSize(bytes) {
+        if (bytes < 1024) return `${bytes} B`;
+        const units = ['KB', 'MB', 'GB'];
+        let size = bytes / 1024;
+        let unitIndex = 0;
+        while (size >= 1024 && unitIndex < units.length - 1) {
+            size /= 1024;
+            unitIndex++;
+        }
+        return `${size.toFixed(1)} ${units[unitIndex]}`;
+    }
+
     // Function to update asset counts in the UI
     function updateAssetCounts() {
         document.getElementById('total-assets-count').textContent = assetCounts.total;
@@ -607,7 +694,11 @@ document.addEventListener('DOMContentLoaded', function() {
                 if (data.no_files_to_migrate) {
                     showSuccessToast('No assets to migrate - all assets are already organized!');
                 } else {
-                    showSuccessToast(`Assets migrated successfully! ${data.files_processed} files processed.`);
+                    let message = `Assets migrated successfully! ${data.files_processed} files processed.`;
+                    if (data.references_updated > 0) {
+                        message += ` ${data.references_updated} references updated in lib/.`;
+                    }
+                    showSuccessToast(message);
                 }
             } else {
                 console.error('Failed to migrate assets:', data);
@@ -314,6 +314,11 @@ class BundlerManager {
     async executeSetBundleIDs(request) {
         this.showLoading();
 
+        const moveAndroidPackage = document.getElementById('move-android-package');
+        if (moveAndroidPackage && moveAndroidPackage.checked) {
+            request.move_android_package = true;
+        }
+
         try {
             const response = await fetch('/api/bundler/set', {
                 method: 'POST',
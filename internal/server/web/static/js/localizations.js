@@ -26,6 +26,7 @@ document.addEventListener('DOMContentLoaded', function() {
         hasTranslationsDir: false,
         hasTranslationFiles: false,
         hasEasyLocalization: false,
+        hasGenL10n: false,
         hasIOSConfig: false
     };
 
@@ -57,10 +58,41 @@ document.addEventListener('DOMContentLoaded', function() {
     }
 
     // Search functionality
+    const exportTranslationsBtn = document.getElementById('export-translations-btn');
+    if (exportTranslationsBtn) {
+        exportTranslationsBtn.addEventListener('click', exportTranslations);
+    }
+
+    const importTranslationsBtn = document.getElementById('import-translations-btn');
+    const importTranslationsInput = document.getElementById('import-translations-input');
+    if (importTranslationsBtn && importTranslationsInput) {
+        importTranslationsBtn.addEventListener('click', function() {
+            importTranslationsInput.click();
+        });
+        importTranslationsInput.addEventListener('change', function() {
+            if (this.files.length > 0) {
+                importTranslations(this.files[0], false);
+            }
+            this.value = '';
+        });
+    }
+
+    const syncTranslationsBtn = document.getElementById('sync-translations-btn');
+    if (syncTranslationsBtn) {
+        syncTranslationsBtn.addEventListener('click', previewSyncTranslations);
+    }
+
     const searchInput = document.getElementById('translation-search');
     if (searchInput) {
         searchInput.addEventListener('input', function() {
-            filterTranslationKeys(this.value);
+            filterTranslationKeys();
+        });
+    }
+
+    const reviewStatusFilter = document.getElementById('review-status-filter');
+    if (reviewStatusFilter) {
+        reviewStatusFilter.addEventListener('change', function() {
+            filterTranslationKeys();
         });
     }
 
@@ -165,13 +197,22 @@ document.addEventListener('DOMContentLoaded', function() {
         const statusList = document.getElementById('init-status-list');
         if (!statusList) return;
 
+        const dependencyItem = initializationStatus.backend === 'arb'
+            ? { key: 'hasGenL10n', label: 'flutter_localizations and gen-l10n enabled' }
+            : { key: 'hasEasyLocalization', label: 'easy_localization dependency added' };
+
         const statusItems = [
             { key: 'hasTranslationsDir', label: 'Translations directory created' },
             { key: 'hasTranslationFiles', label: 'Translation files exist' },
-            { key: 'hasEasyLocalization', label: 'easy_localization dependency added' },
+            dependencyItem,
             { key: 'hasIOSConfig', label: 'iOS Info.plist configured (optional)' }
         ];
 
+        const backendSelect = document.getElementById('localization-backend-select');
+        if (backendSelect && initializationStatus.backend) {
+            backendSelect.value = initializationStatus.backend;
+        }
+
         let statusHTML = '';
         statusItems.forEach(item => {
             const isComplete = initializationStatus[item.key];
@@ -195,8 +236,15 @@ document.addEventListener('DOMContentLoaded', function() {
             initBtn.innerHTML = '<i class="fas fa-spinner fa-spin"></i> Initializing...';
         }
 
+        const formData = new FormData();
+        const backendSelect = document.getElementById('localization-backend-select');
+        if (backendSelect) {
+            formData.append('backend', backendSelect.value);
+        }
+
         fetch('/api/localizations/init', {
-            method: 'POST'
+            method: 'POST',
+            body: formData
         })
         .then(response => response.json())
         .then(data => {
@@ -293,6 +341,10 @@ document.addEventListener('DOMContentLoaded', function() {
         document.getElementById('translation-keys-count').textContent = stats.translationKeys;
         document.getElementById('missing-translations-count').textContent = stats.missingTranslations;
         document.getElementById('completion-rate').textContent = stats.completionRate + '%';
+
+        const statusCounts = stats.statusCounts || {};
+        document.getElementById('awaiting-review-count').textContent = (statusCounts['machine'] || 0) + (statusCounts['needs-review'] || 0);
+        document.getElementById('reviewed-rate').textContent = (stats.reviewedRate || 0) + '%';
     }
 
     // Function to update language cards
@@ -404,12 +456,40 @@ document.addEventListener('DOMContentLoaded', function() {
 
         noDataMessage.style.display = 'none';
 
+        // Index plural, gender and placeholder issues by key and language.
+        // Empty values are already shown as missing, so they aren't repeated.
+        const cellIssues = {};
+        const addCellIssue = (issue, severity) => {
+            const id = `${issue.key}|${issue.language}`;
+            cellIssues[id] = cellIssues[id] || { severity: 'warning', messages: [] };
+            cellIssues[id].messages.push(issue.message);
+            if (severity === 'error') {
+                cellIssues[id].severity = 'error';
+            }
+        };
+        (localizationData.pluralIssues || []).forEach(issue => addCellIssue(issue, 'warning'));
+        (localizationData.validationIssues || [])
+            .filter(issue => issue.type !== 'empty_value')
+            .forEach(issue => addCellIssue(issue, issue.severity));
+
         // Build table rows
         let rowsHTML = '';
         translationKeys.forEach(keyData => {
+            // ARB metadata is shown as a tooltip on the key
+            let keyTitle = 'Double-click to edit key';
+            if (keyData.kind) {
+                keyTitle = `${keyData.kind === 'plural' ? 'Plural' : 'Gender'} message, edited one "form: text" per line\n${keyTitle}`;
+            }
+            if (keyData.placeholders && keyData.placeholders.length > 0) {
+                keyTitle = `Placeholders: ${keyData.placeholders.map(name => `{${name}}`).join(', ')}\n${keyTitle}`;
+            }
+            if (keyData.description) {
+                keyTitle = `${keyData.description}\n${keyTitle}`;
+            }
+
             rowsHTML += `
                 <tr data-key="${keyData.key}">
-                    <td class="editable-key" data-original-key="${keyData.key}" title="Double-click to edit key">
+                    <td class="editable-key" data-original-key="${keyData.key}" title="${keyTitle.replace(/"/g, '&quot;')}">
                         <span class="key-text">${keyData.key}</span>
                         <button class="expand-btn" style="display: none;">...</button>
                         <div class="edit-container" style="display: none;">
@@ -425,18 +505,47 @@ document.addEventListener('DOMContentLoaded', function() {
             languages.forEach(language => {
                 const value = keyData.translations[language.code] || '';
                 const isEmpty = !value.trim();
+
+                // Plural and gender cells list their forms one per line
+                let cellTitle = 'Double-click to edit translation';
+                let editValue = value;
+                if (keyData.kind) {
+                    const requiredForms = (keyData.requiredForms && keyData.requiredForms[language.code]) || [];
+                    cellTitle = `Double-click to edit ${keyData.kind} forms\nRequired forms: ${requiredForms.join(', ')}`;
+                    if (isEmpty) {
+                        editValue = requiredForms.map(form => `${form}: `).join('\n');
+                    }
+                }
+
+                // Machine translations and flagged values get a badge that marks them reviewed when clicked
+                let statusHTML = '';
+                const status = keyData.statuses && keyData.statuses[language.code];
+                if (status === 'machine' && !isEmpty) {
+                    statusHTML = `<i class="fas fa-robot review-status-badge" data-status="machine" style="color: #888; margin-left: 5px; cursor: pointer;" title="Machine translation, click to mark as reviewed"></i>`;
+                } else if (status === 'needs-review' && !isEmpty) {
+                    statusHTML = `<i class="fas fa-flag review-status-badge" data-status="needs-review" style="color: #f0ad4e; margin-left: 5px; cursor: pointer;" title="Needs review, click to mark as reviewed"></i>`;
+                }
+
+                let issueHTML = '';
+                const cellIssue = cellIssues[`${keyData.key}|${language.code}`];
+                if (cellIssue && !isEmpty) {
+                    const color = cellIssue.severity === 'error' ? '#e74c3c' : '#f0ad4e';
+                    const issueTitle = cellIssue.messages.join('\n').replace(/"/g, '&quot;');
+                    issueHTML = `<i class="fas fa-exclamation-triangle translation-warning" style="color: ${color}; margin-left: 5px;" title="${issueTitle}"></i>`;
+                }
+
                 rowsHTML += `
                     <td class="editable-translation ${isEmpty ? 'empty-translation' : ''}"
                         data-key="${keyData.key}"
                         data-language="${language.code}"
-                        title="Double-click to edit translation">
-                        <span class="translation-text">${isEmpty ? '<em>Missing</em>' : value}</span>
+                        title="${cellTitle}">
+                        <span class="translation-text"${keyData.kind ? ' style="white-space: pre-line;"' : ''}>${isEmpty ? '<em>Missing</em>' : value}</span>${statusHTML}${issueHTML}
                         <button class="expand-btn" style="display: none;">...</button>
                         <button class="table-btn translate-btn" style="display: none;" title="Translate to ${language.name}">
                             <i class="fas fa-language"></i>
                         </button>
                         <div class="edit-container" style="display: none;">
-                            <textarea class="translation-input" rows="3">${value}</textarea>
+                            <textarea class="translation-input" rows="3">${editValue}</textarea>
                             <button class="table-btn save-translation-btn" title="Save translation">
                                 <i class="fas fa-check"></i>
                             </button>
@@ -463,6 +572,9 @@ document.addEventListener('DOMContentLoaded', function() {
         // Add event listeners to table elements
         addTableEventListeners();
 
+        // Keep the search and status filter across reloads
+        filterTranslationKeys();
+
         // Update translate button states
         updateTranslateButtonStates();
 
@@ -603,6 +715,15 @@ document.addEventListener('DOMContentLoaded', function() {
                 });
             }
 
+            // Review status badge
+            const statusBadge = cell.querySelector('.review-status-badge');
+            if (statusBadge) {
+                statusBadge.addEventListener('click', function(e) {
+                    e.stopPropagation();
+                    setReviewStatus(cell.getAttribute('data-key'), cell.getAttribute('data-language'), 'reviewed');
+                });
+            }
+
             // Translate button
             const translateBtn = cell.querySelector('.translate-btn');
             if (translateBtn) {
@@ -639,18 +760,60 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     }
 
-    // Function to filter translation keys
-    function filterTranslationKeys(searchTerm) {
+    // Function to filter translation keys by the search term and review status
+    function filterTranslationKeys() {
         const rows = document.querySelectorAll('#translation-table-body tr');
-        const term = searchTerm.toLowerCase();
+        const term = (document.getElementById('translation-search').value || '').toLowerCase();
+        const status = document.getElementById('review-status-filter').value;
 
         rows.forEach(row => {
-            const key = row.getAttribute('data-key').toLowerCase();
-            const shouldShow = key.includes(term);
+            const key = row.getAttribute('data-key');
+            let shouldShow = key.toLowerCase().includes(term);
+
+            if (shouldShow && status) {
+                const keyData = localizationData.translationKeys.find(k => k.key === key);
+                shouldShow = keyData ? keyHasReviewStatus(keyData, status) : false;
+            }
+
             row.style.display = shouldShow ? '' : 'none';
         });
     }
 
+    // Function to check whether any translation of a key but the default language's has a review status.
+    // Missing matches keys with an empty translation in any language.
+    function keyHasReviewStatus(keyData, status) {
+        if (status === 'missing') {
+            return localizationData.languages.some(language => !(keyData.translations[language.code] || '').trim());
+        }
+        return Object.values(keyData.statuses || {}).includes(status);
+    }
+
+    // Function to set the review status of a translation
+    function setReviewStatus(key, language, status) {
+        const formData = new FormData();
+        formData.append('translation_key', key);
+        formData.append('language', language);
+        formData.append('status', status);
+
+        fetch('/api/localizations/review-status', {
+            method: 'POST',
+            body: formData
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (data.success) {
+                showSuccessToast(data.message, 'Review Status Updated');
+                loadLocalizationData();
+            } else {
+                showErrorToast(data.error || 'Failed to update review status', 'Error');
+            }
+        })
+        .catch(error => {
+            console.error('Error updating review status:', error);
+            showErrorToast('Failed to update review status', 'Error');
+        });
+    }
+
     // Helper function to enter edit mode
     function enterEditMode(cell, textElement, editContainer, inputElement, saveBtn) {
         // Store original value for cancellation
@@ -821,6 +984,9 @@ document.addEventListener('DOMContentLoaded', function() {
                 exitEditMode(cell, textElement, editContainer, inputElement, saveBtn, newValue);
 
                 showSuccessToast(`Translation for "${language}" updated successfully`, 'Success');
+
+                // Reload to refresh validation warnings and the forms of plural and gender keys
+                loadLocalizationData();
             } else {
                 showErrorToast(data.error || 'Failed to update translation', 'Error');
                 // Exit edit mode with original value
@@ -837,39 +1003,32 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     }
 
-    // Function to update a translation key
-    function updateTranslationKey(originalKey, newKey, cell, textElement, editContainer, inputElement, saveBtn) {
-        // First, get all current translations for this key
-        const keyData = localizationData.translationKeys.find(k => k.key === originalKey);
-        if (!keyData) {
-            showErrorToast('Translation key not found', 'Error');
-            exitEditMode(cell, textElement, editContainer, inputElement, saveBtn, originalKey);
-            return;
-        }
+    // Function to parse "form: text" lines of a plural or gender cell into forms
+    function parseFormsText(text) {
+        const forms = {};
+        let current = 'other';
+        (text || '').split('\n').forEach(line => {
+            const match = line.match(/^\s*(=\d+|zero|one|two|few|many|other|male|female)\s*:\s?(.*)$/);
+            if (match) {
+                current = match[1];
+                forms[current] = match[2].trim();
+            } else if (line.trim() !== '' || current in forms) {
+                forms[current] = current in forms ? `${forms[current]}\n${line}` : line;
+            }
+        });
+        return forms;
+    }
 
-        // Delete the old key first
-        const deleteFormData = new FormData();
-        deleteFormData.append('translation_key', originalKey);
+    // Function to update a translation key, renaming it in every language
+    function updateTranslationKey(originalKey, newKey, cell, textElement, editContainer, inputElement, saveBtn) {
+        const formData = new FormData();
+        formData.append('old_key', originalKey);
+        formData.append('new_key', newKey);
+        formData.append('update_code', document.getElementById('rename-update-code')?.checked ? 'true' : 'false');
 
-        fetch('/api/localizations/delete-key', {
+        fetch('/api/localizations/rename-key', {
             method: 'POST',
-            body: deleteFormData
-        })
-        .then(response => response.json())
-        .then(data => {
-            if (data.success) {
-                // Now add the new key with all the translations
-                const addFormData = new FormData();
-                addFormData.append('translation_key', newKey);
-                addFormData.append('translations', JSON.stringify(keyData.translations));
-
-                return fetch('/api/localizations/update-translations', {
-                    method: 'POST',
-                    body: addFormData
-                });
-            } else {
-                throw new Error(data.error || 'Failed to delete old key');
-            }
+            body: formData
         })
         .then(response => response.json())
         .then(data => {
@@ -894,17 +1053,25 @@ document.addEventListener('DOMContentLoaded', function() {
                 // Exit edit mode with new key
                 exitEditMode(cell, textElement, editContainer, inputElement, saveBtn, newKey);
 
-                showSuccessToast(`Translation key updated successfully`, 'Success');
+                let message = 'Translation key updated successfully';
+                if (data.result && data.result.codeReferences > 0) {
+                    message += `, ${data.result.codeReferences} reference(s) updated in ${data.result.codeFiles.length} Dart file(s)`;
+                }
+                if (data.result && data.result.codeError) {
+                    showWarningToast(`${message}, but updating references in lib/ failed: ${data.result.codeError}`, 'Warning');
+                } else {
+                    showSuccessToast(message, 'Success');
+                }
 
                 // Reload data to ensure consistency
                 loadLocalizationData();
             } else {
-                throw new Error(data.error || 'Failed to create new key');
+                throw new Error(data.error || 'Failed to rename key');
             }
         })
         .catch(error => {
             console.error('Error updating translation key:', error);
-            showErrorToast('Failed to update translation key', 'Error');
+            showErrorToast(error.message || 'Failed to update translation key', 'Error');
             // Exit edit mode with original key
             exitEditMode(cell, textElement, editContainer, inputElement, saveBtn, originalKey);
         });
@@ -974,6 +1141,132 @@ document.addEventListener('DOMContentLoaded', function() {
         );
     }
 
+    // Function to download translations as CSV, XLSX or XLIFF for translators
+    function exportTranslations() {
+        const format = document.getElementById('export-format-select').value;
+        const missingOnly = document.getElementById('export-missing-only').checked;
+
+        const link = document.createElement('a');
+        link.href = `/api/localizations/export?format=${encodeURIComponent(format)}&missing_only=${missingOnly}`;
+        document.body.appendChild(link);
+        link.click();
+        document.body.removeChild(link);
+    }
+
+    // Function to merge an uploaded CSV, XLSX or XLIFF file into the translations
+    function importTranslations(file, overwrite) {
+        const formData = new FormData();
+        formData.append('file', file);
+        formData.append('overwrite', overwrite ? 'true' : 'false');
+
+        fetch('/api/localizations/import', {
+            method: 'POST',
+            body: formData
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (!data.success) {
+                showErrorToast(data.error || 'Failed to import translations', 'Error');
+                return;
+            }
+
+            const result = data.result;
+            let message = `${result.updated} value(s) imported, ${result.unchanged} unchanged.`;
+            if (result.unknownKeys.length > 0) {
+                message += ` Skipped ${result.unknownKeys.length} unknown key(s).`;
+            }
+            if (result.unknownLanguages.length > 0) {
+                message += ` Skipped languages not in the project: ${result.unknownLanguages.join(', ')}.`;
+            }
+            showSuccessToast(message, 'Import Complete');
+
+            if (result.updated > 0) {
+                loadLocalizationData();
+            }
+
+            // Conflicting values are kept until the user chooses to overwrite them
+            if (result.conflicts.length > 0) {
+                const examples = result.conflicts.slice(0, 3).map(c => `${c.key} [${c.language}]`).join(', ');
+                showConfirmationToast(
+                    `${result.conflicts.length} imported value(s) differ from the current translations, e.g. ${examples}. Overwrite them with the imported values?`,
+                    'Import Conflicts',
+                    {
+                        confirmText: 'Overwrite',
+                        cancelText: 'Keep Current',
+                        confirmButtonClass: 'primary-btn',
+                        onConfirm: () => {
+                            importTranslations(file, true);
+                        }
+                    }
+                );
+            }
+        })
+        .catch(error => {
+            console.error('Error importing translations:', error);
+            showErrorToast('Failed to import translations', 'Error');
+        });
+    }
+
+    // Function to post a sync request; a dry run previews the changes
+    function requestSyncTranslations(dryRun) {
+        const formData = new FormData();
+        formData.append('dry_run', dryRun ? 'true' : 'false');
+        formData.append('use_source', document.getElementById('sync-use-source').checked ? 'true' : 'false');
+
+        return fetch('/api/localizations/sync', {
+            method: 'POST',
+            body: formData
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (!data.success) {
+                throw new Error(data.error || 'Failed to sync translations');
+            }
+            return data.result;
+        });
+    }
+
+    // Function to preview a sync and ask before rebuilding the translation files
+    function previewSyncTranslations() {
+        requestSyncTranslations(true)
+        .then(result => {
+            const changed = result.languages.filter(language => language.changed);
+            if (changed.length === 0) {
+                showSuccessToast(`All languages are in sync with ${result.default_language}`, 'Sync');
+                return;
+            }
+
+            const summary = changed.map(language =>
+                `${language.language}: ${language.added.length} added, ${language.repaired.length} repaired, ${language.orphaned.length} orphaned`
+            ).join('; ');
+
+            showConfirmationToast(
+                `Rebuild ${changed.length} language(s) with the keys and order of ${result.default_language}? ${summary}. Orphaned values are saved to .fdawg/orphaned_translations.json.`,
+                'Sync Keys',
+                {
+                    confirmText: 'Sync',
+                    cancelText: 'Cancel',
+                    confirmButtonClass: 'primary-btn',
+                    onConfirm: () => {
+                        requestSyncTranslations(false)
+                        .then(() => {
+                            showSuccessToast(`Synced ${changed.length} language(s) with ${result.default_language}`, 'Sync Complete');
+                            loadLocalizationData();
+                        })
+                        .catch(error => {
+                            console.error('Error syncing translations:', error);
+                            showErrorToast(error.message, 'Error');
+                        });
+                    }
+                }
+            );
+        })
+        .catch(error => {
+            console.error('Error syncing translations:', error);
+            showErrorToast(error.message, 'Error');
+        });
+    }
+
     function downloadLanguageFile(languageCode) {
         const language = localizationData.languages.find(lang => lang.code === languageCode);
         const languageName = language ? language.name : languageCode;
@@ -982,7 +1275,8 @@ document.addEventListener('DOMContentLoaded', function() {
         const downloadUrl = `/api/localizations/download/${languageCode}`;
         const link = document.createElement('a');
         link.href = downloadUrl;
-        link.download = `${languageCode}.json`;
+        // Let the server name the file, as it may be JSON or ARB
+        link.download = '';
         document.body.appendChild(link);
         link.click();
         document.body.removeChild(link);
@@ -1213,8 +1507,8 @@ document.addEventListener('DOMContentLoaded', function() {
                 // Update local data
                 keyData.translations[targetLanguage] = data.translated_text;
 
-                // Save the translation to the server
-                updateSingleTranslationFromAPI(key, targetLanguage, data.translated_text);
+                // Save the translation to the server, marked as a machine translation
+                updateSingleTranslationFromAPI(key, targetLanguage, data.translated_text, null, [targetLanguage]);
 
                 showSuccessToast(`Translated to ${getLanguageName(targetLanguage)}`, 'Translation Complete');
             } else {
@@ -1269,6 +1563,7 @@ document.addEventListener('DOMContentLoaded', function() {
             if (data.translations) {
                 let successCount = 0;
                 let errorCount = 0;
+                const translatedLanguages = [];
 
                 // Update each translation
                 Object.entries(data.translations).forEach(([targetLang, result]) => {
@@ -1280,6 +1575,7 @@ document.addEventListener('DOMContentLoaded', function() {
                             translationText.textContent = result.text;
                             cell.classList.remove('empty-translation');
                             keyData.translations[targetLang] = result.text;
+                            translatedLanguages.push(targetLang);
                             successCount++;
                         } else {
                             translationText.innerHTML = cellData.originalContent;
@@ -1290,7 +1586,7 @@ document.addEventListener('DOMContentLoaded', function() {
 
                 // Save all successful translations
                 if (successCount > 0) {
-                    updateSingleTranslationFromAPI(key, null, null, keyData.translations);
+                    updateSingleTranslationFromAPI(key, null, null, keyData.translations, translatedLanguages);
                 }
 
                 if (successCount > 0 && errorCount === 0) {
@@ -1385,9 +1681,12 @@ document.addEventListener('DOMContentLoaded', function() {
         return langInfo ? langInfo.name : languageCode;
     }
 
-    function updateSingleTranslationFromAPI(key, language, newValue, allTranslations = null) {
+    function updateSingleTranslationFromAPI(key, language, newValue, allTranslations = null, machineLanguages = null) {
         const formData = new FormData();
         formData.append('translation_key', key);
+        if (machineLanguages) {
+            formData.append('machine_languages', JSON.stringify(machineLanguages));
+        }
 
         if (allTranslations) {
             // Update all translations for the key
@@ -1408,6 +1707,9 @@ document.addEventListener('DOMContentLoaded', function() {
         .then(data => {
             if (!data.success) {
                 console.error('Failed to save translation:', data.error);
+            } else if (machineLanguages) {
+                // Reload to show the machine translation badges
+                loadLocalizationData();
             }
         })
         .catch(error => {
@@ -1530,9 +1832,342 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     }
 
+    // Glossary and translation memory functions
+    let glossaryTerms = [];
+    let memoryEntries = [];
+
+    function escapeHtml(text) {
+        const div = document.createElement('div');
+        div.textContent = text == null ? '' : String(text);
+        return div.innerHTML;
+    }
+
+    function showGlossaryModal() {
+        fetch('/api/localizations/glossary')
+            .then(response => response.json())
+            .then(data => {
+                if (!data.success) {
+                    showErrorToast(data.error || 'Failed to load glossary', 'Glossary Error');
+                    return;
+                }
+                glossaryTerms = data.terms || [];
+
+                const modalContent = `
+                    <div class="translation-config-modal">
+                        <div class="form-hint" style="margin-bottom: 12px;">
+                            Glossary terms are protected from machine translation. Terms without translations,
+                            such as brand names, are kept as is; otherwise the translation for the target language is used.
+                            Cached translations aren't affected, so clear the translation memory after changing a term.
+                        </div>
+                        <div id="glossary-list" style="max-height: 300px; overflow-y: auto; margin-bottom: 16px;"></div>
+                        <div class="form-group">
+                            <label for="glossary-term-input">Term:</label>
+                            <input type="text" id="glossary-term-input" placeholder="e.g. FDAWG" />
+                        </div>
+                        <div class="form-group">
+                            <label for="glossary-translations-input">Forced translations (optional, one per line):</label>
+                            <textarea id="glossary-translations-input" rows="3" placeholder="fr=Panier&#10;de=Warenkorb"></textarea>
+                        </div>
+                        <div class="form-actions">
+                            <button class="secondary-btn" onclick="closeConfigModal()">Close</button>
+                            <button class="primary-btn" onclick="saveGlossaryTerm()"><i class="fas fa-save"></i> Save Term</button>
+                        </div>
+                    </div>
+                `;
+
+                showCustomDialog('Glossary', modalContent);
+                renderGlossaryList();
+            })
+            .catch(error => {
+                console.error('Error loading glossary:', error);
+                showErrorToast('Failed to load glossary', 'Glossary Error');
+            });
+    }
+
+    function renderGlossaryList() {
+        const container = document.getElementById('glossary-list');
+        if (!container) {
+            return;
+        }
+
+        if (glossaryTerms.length === 0) {
+            container.innerHTML = '<p class="form-hint">The glossary is empty.</p>';
+            return;
+        }
+
+        const rows = glossaryTerms.map((term, index) => {
+            const translations = Object.keys(term.translations || {}).sort()
+                .map(language => `${escapeHtml(language)}: ${escapeHtml(term.translations[language])}`)
+                .join(', ');
+            return `
+                <tr>
+                    <td style="padding: 6px 8px;"><strong>${escapeHtml(term.term)}</strong></td>
+                    <td style="padding: 6px 8px;">${translations || '<em>never translated</em>'}</td>
+                    <td style="padding: 6px 8px; text-align: right; white-space: nowrap;">
+                        <button class="secondary-btn glossary-edit-btn" data-index="${index}" title="Edit"><i class="fas fa-edit"></i></button>
+                        <button class="secondary-btn glossary-remove-btn" data-index="${index}" title="Remove"><i class="fas fa-trash"></i></button>
+                    </td>
+                </tr>
+            `;
+        }).join('');
+
+        container.innerHTML = `<table style="width: 100%; border-collapse: collapse;">${rows}</table>`;
+
+        container.querySelectorAll('.glossary-edit-btn').forEach(button => {
+            button.addEventListener('click', () => {
+                const term = glossaryTerms[button.dataset.index];
+                document.getElementById('glossary-term-input').value = term.term;
+                document.getElementById('glossary-translations-input').value = Object.keys(term.translations || {}).sort()
+                    .map(language => `${language}=${term.translations[language]}`)
+                    .join('\n');
+            });
+        });
+        container.querySelectorAll('.glossary-remove-btn').forEach(button => {
+            button.addEventListener('click', () => {
+                updateGlossary('remove', glossaryTerms[button.dataset.index].term);
+            });
+        });
+    }
+
+    function saveGlossaryTerm() {
+        const term = document.getElementById('glossary-term-input').value.trim();
+        if (!term) {
+            showErrorToast('Please enter a term', 'Glossary Error');
+            return;
+        }
+
+        const translations = {};
+        const lines = document.getElementById('glossary-translations-input').value.split('\n');
+        for (const line of lines) {
+            if (!line.trim()) {
+                continue;
+            }
+            const separator = line.indexOf('=');
+            if (separator <= 0 || !line.slice(separator + 1).trim()) {
+                showErrorToast(`Invalid translation "${line.trim()}", expected language=text`, 'Glossary Error');
+                return;
+            }
+            translations[line.slice(0, separator).trim()] = line.slice(separator + 1).trim();
+        }
+
+        updateGlossary('add', term, translations);
+    }
+
+    function updateGlossary(action, term, translations) {
+        const formData = new FormData();
+        formData.append('action', action);
+        formData.append('term', term);
+        if (translations) {
+            formData.append('translations', JSON.stringify(translations));
+        }
+
+        fetch('/api/localizations/glossary', {
+            method: 'POST',
+            body: formData
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (data.success) {
+                glossaryTerms = data.terms || [];
+                renderGlossaryList();
+                if (action === 'add') {
+                    document.getElementById('glossary-term-input').value = '';
+                    document.getElementById('glossary-translations-input').value = '';
+                }
+                showSuccessToast(data.message, 'Glossary Updated');
+            } else {
+                showErrorToast(data.error || 'Failed to update glossary', 'Glossary Error');
+            }
+        })
+        .catch(error => {
+            console.error('Error updating glossary:', error);
+            showErrorToast('Failed to update glossary', 'Glossary Error');
+        });
+    }
+
+    function showMemoryModal() {
+        fetch('/api/localizations/memory')
+            .then(response => response.json())
+            .then(data => {
+                if (!data.success) {
+                    showErrorToast(data.error || 'Failed to load translation memory', 'Translation Memory Error');
+                    return;
+                }
+                memoryEntries = data.entries || [];
+
+                const languageOptions = localizationData.languages
+                    .map(language => `<option value="${escapeHtml(language.code)}">${escapeHtml(language.name)} (${escapeHtml(language.code)})</option>`)
+                    .join('');
+
+                const modalContent = `
+                    <div class="translation-config-modal">
+                        <div class="form-hint" style="margin-bottom: 12px;">
+                            Machine translations are cached in .fdawg/translation_memory.json and reused instead of
+                            calling the translation provider again. Correct an entry to change future translations of the same text.
+                        </div>
+                        <div class="form-group">
+                            <label for="memory-language-filter">Language:</label>
+                            <select id="memory-language-filter" onchange="renderMemoryList()">
+                                <option value="">All languages</option>
+                                ${languageOptions}
+                            </select>
+                        </div>
+                        <div id="memory-list" style="max-height: 300px; overflow-y: auto; margin-bottom: 16px;"></div>
+                        <div class="form-group">
+                            <label for="memory-source-input">Source text:</label>
+                            <input type="text" id="memory-source-input" placeholder="Text in the default language" />
+                        </div>
+                        <div class="form-group">
+                            <label for="memory-target-input">Translation:</label>
+                            <input type="text" id="memory-target-input" placeholder="Translation for the selected language" />
+                        </div>
+                        <div class="form-actions">
+                            <button class="secondary-btn" onclick="closeConfigModal()">Close</button>
+                            <button class="secondary-btn" onclick="clearTranslationMemory()"><i class="fas fa-trash"></i> Clear</button>
+                            <button class="primary-btn" onclick="saveMemoryEntry()"><i class="fas fa-save"></i> Save Entry</button>
+                        </div>
+                    </div>
+                `;
+
+                showCustomDialog('Translation Memory', modalContent);
+                renderMemoryList();
+            })
+            .catch(error => {
+                console.error('Error loading translation memory:', error);
+                showErrorToast('Failed to load translation memory', 'Translation Memory Error');
+            });
+    }
+
+    function renderMemoryList() {
+        const container = document.getElementById('memory-list');
+        if (!container) {
+            return;
+        }
+
+        const language = document.getElementById('memory-language-filter').value;
+        const entries = memoryEntries
+            .map((entry, index) => ({ entry, index }))
+            .filter(item => !language || item.entry.target_lang === language);
+
+        if (entries.length === 0) {
+            container.innerHTML = '<p class="form-hint">No cached translations.</p>';
+            return;
+        }
+
+        const rows = entries.map(({ entry, index }) => `
+            <tr>
+                <td style="padding: 6px 8px; white-space: nowrap;">${escapeHtml(entry.source_lang)} → ${escapeHtml(entry.target_lang)}</td>
+                <td style="padding: 6px 8px;">${escapeHtml(entry.source)}</td>
+                <td style="padding: 6px 8px;">${escapeHtml(entry.target)}</td>
+                <td style="padding: 6px 8px; color: #888;">${escapeHtml(entry.provider)}</td>
+                <td style="padding: 6px 8px; text-align: right; white-space: nowrap;">
+                    <button class="secondary-btn memory-edit-btn" data-index="${index}" title="Edit"><i class="fas fa-edit"></i></button>
+                    <button class="secondary-btn memory-delete-btn" data-index="${index}" title="Delete"><i class="fas fa-trash"></i></button>
+                </td>
+            </tr>
+        `).join('');
+
+        container.innerHTML = `<table style="width: 100%; border-collapse: collapse;">${rows}</table>`;
+
+        container.querySelectorAll('.memory-edit-btn').forEach(button => {
+            button.addEventListener('click', () => {
+                const entry = memoryEntries[button.dataset.index];
+                document.getElementById('memory-language-filter').value = entry.target_lang;
+                document.getElementById('memory-source-input').value = entry.source;
+                document.getElementById('memory-target-input').value = entry.target;
+                renderMemoryList();
+            });
+        });
+        container.querySelectorAll('.memory-delete-btn').forEach(button => {
+            button.addEventListener('click', () => {
+                const entry = memoryEntries[button.dataset.index];
+                updateTranslationMemory({
+                    action: 'delete',
+                    source_language: entry.source_lang,
+                    target_language: entry.target_lang,
+                    source: entry.source
+                });
+            });
+        });
+    }
+
+    function saveMemoryEntry() {
+        const language = document.getElementById('memory-language-filter').value;
+        const source = document.getElementById('memory-source-input').value;
+        const target = document.getElementById('memory-target-input').value;
+
+        if (!language) {
+            showErrorToast('Please select the language of the translation', 'Translation Memory Error');
+            return;
+        }
+        if (!source.trim() || !target.trim()) {
+            showErrorToast('Please enter the source text and its translation', 'Translation Memory Error');
+            return;
+        }
+
+        updateTranslationMemory({
+            action: 'set',
+            target_language: language,
+            source: source,
+            target: target
+        });
+    }
+
+    function clearTranslationMemory() {
+        const language = document.getElementById('memory-language-filter').value;
+        const scope = language ? `${language} translations` : 'all cached translations';
+
+        showConfirmationToast(
+            `This will delete ${scope} from the translation memory. They will be translated by the provider again.`,
+            'Clear Translation Memory?',
+            {
+                confirmText: 'Clear',
+                cancelText: 'Cancel',
+                confirmButtonClass: 'primary-btn',
+                onConfirm: () => {
+                    updateTranslationMemory({ action: 'clear', target_language: language });
+                }
+            }
+        );
+    }
+
+    function updateTranslationMemory(params) {
+        const formData = new FormData();
+        Object.keys(params).forEach(name => formData.append(name, params[name]));
+
+        fetch('/api/localizations/memory', {
+            method: 'POST',
+            body: formData
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (data.success) {
+                memoryEntries = data.entries || [];
+                renderMemoryList();
+                if (params.action === 'set') {
+                    document.getElementById('memory-source-input').value = '';
+                    document.getElementById('memory-target-input').value = '';
+                }
+                showSuccessToast(data.message, 'Translation Memory Updated');
+            } else {
+                showErrorToast(data.error || 'Failed to update translation memory', 'Translation Memory Error');
+            }
+        })
+        .catch(error => {
+            console.error('Error updating translation memory:', error);
+            showErrorToast('Failed to update translation memory', 'Translation Memory Error');
+        });
+    }
+
     // Make functions globally available
     window.showTranslationConfigModal = showTranslationConfigModal;
     window.closeConfigModal = closeConfigModal;
     window.saveApiKey = saveApiKey;
+    window.showGlossaryModal = showGlossaryModal;
+    window.saveGlossaryTerm = saveGlossaryTerm;
+    window.showMemoryModal = showMemoryModal;
+    window.renderMemoryList = renderMemoryList;
+    window.saveMemoryEntry = saveMemoryEntry;
+    window.clearTranslationMemory = clearTranslationMemory;
 
 });
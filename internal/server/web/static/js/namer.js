@@ -3,6 +3,8 @@ class NamerManager {
     constructor() {
         this.platforms = [];
         this.currentNames = {};
+        this.localizedNames = [];
+        this.webMetadata = null;
         this.init();
     }
 
@@ -10,6 +12,8 @@ class NamerManager {
         this.bindEvents();
         this.loadPlatforms();
         this.loadCurrentNames();
+        this.loadLocales();
+        this.loadWebMetadata();
     }
 
     bindEvents() {
@@ -17,6 +21,8 @@ class NamerManager {
         document.getElementById('refresh-names-btn').addEventListener('click', () => {
             this.loadCurrentNames();
             this.loadPlatforms();
+            this.loadLocales();
+            this.loadWebMetadata();
         });
 
         // Toggle platform information section
@@ -28,6 +34,16 @@ class NamerManager {
         document.getElementById('set-universal-btn').addEventListener('click', () => {
             this.setUniversalName();
         });
+
+        // Localized name setting
+        document.getElementById('set-localized-btn').addEventListener('click', () => {
+            this.setLocalizedName();
+        });
+
+        // Web metadata
+        document.getElementById('save-web-metadata-btn').addEventListener('click', () => {
+            this.saveWebMetadata();
+        });
     }
 
     async loadPlatforms() {
@@ -76,6 +92,148 @@ class NamerManager {
 
 
 
+    async loadLocales() {
+        try {
+            const response = await fetch('/api/namer/locales');
+            if (!response.ok) {
+                throw new Error('Failed to load localized names');
+            }
+
+            const data = await response.json();
+            this.localizedNames = data.localized_names || [];
+            this.renderLocaleOptions(data.languages || []);
+            this.renderLocalizedNames();
+        } catch (error) {
+            console.error('Error loading localized names:', error);
+            showToast('Failed to load localized app names', 'error');
+        }
+    }
+
+    renderLocaleOptions(languages) {
+        const select = document.getElementById('localized-name-locale');
+        const selected = select.value;
+
+        if (languages.length === 0) {
+            select.innerHTML = '<option value="">No languages</option>';
+            select.disabled = true;
+            document.getElementById('set-localized-btn').disabled = true;
+            return;
+        }
+
+        select.innerHTML = languages.map(language =>
+            `<option value="${language}" ${language === selected ? 'selected' : ''}>${language}</option>`
+        ).join('');
+        select.disabled = false;
+        document.getElementById('set-localized-btn').disabled = false;
+    }
+
+    renderLocalizedNames() {
+        const container = document.getElementById('localized-names-list');
+
+        if (this.localizedNames.length === 0) {
+            container.innerHTML = '<div class="form-help">No localized names set</div>';
+            return;
+        }
+
+        // Group the names by locale
+        const byLocale = {};
+        this.localizedNames.forEach(name => {
+            (byLocale[name.locale] = byLocale[name.locale] || []).push(name);
+        });
+
+        container.innerHTML = Object.keys(byLocale).map(locale => `
+            <div style="display: flex; align-items: center; gap: 10px; padding: 6px 0; border-bottom: 1px solid rgba(0, 0, 0, 0.08);">
+                <strong style="min-width: 60px;">${locale}</strong>
+                <div style="flex: 1;">
+                    ${byLocale[locale].map(name => `
+                        <span style="margin-right: 12px;" title="${this.escapeHtml(name.path)}">
+                            <i class="${this.getPlatformIcon(name.platform)}"></i> ${this.escapeHtml(name.display_name)}
+                        </span>
+                    `).join('')}
+                </div>
+                <button class="cancel-edit-btn" onclick="namerManager.removeLocalizedName('${locale}')" title="Remove ${locale} names">
+                    <i class="fas fa-trash"></i>
+                </button>
+            </div>
+        `).join('');
+    }
+
+    async setLocalizedName() {
+        const locale = document.getElementById('localized-name-locale').value;
+        const name = document.getElementById('localized-name').value.trim();
+
+        if (!locale || !name) {
+            showToast('Please choose a language and enter an app name', 'warning');
+            return;
+        }
+
+        const message = `Set "${this.escapeHtml(name)}" as the ${locale} app name?`;
+        const details = 'Android: res/values-*/strings.xml<br>iOS and macOS: Runner/*.lproj/InfoPlist.strings<br>Web, Linux and Windows keep their current name';
+
+        this.showConfirmationDialog(message, details, async () => {
+            this.showLoading();
+            try {
+                const response = await fetch('/api/namer/set', {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify({ universal: name, locale: locale })
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Failed to set app name');
+                }
+
+                const data = await response.json();
+                showToast(`${locale} app name updated successfully!`, 'success');
+                (data.result.warnings || []).forEach(warning => showToast(warning, 'warning'));
+                (data.warnings || []).forEach(issue => showToast(`${issue.platform}: ${issue.message}`, 'warning'));
+
+                document.getElementById('localized-name').value = '';
+                await this.loadLocales();
+                await this.loadCurrentNames();
+            } catch (error) {
+                console.error('Error setting localized app name:', error);
+                showToast(`Failed to set app name: ${error.message}`, 'error');
+            } finally {
+                this.hideLoading();
+            }
+        });
+    }
+
+    removeLocalizedName(locale) {
+        this.showConfirmationDialog(`Remove the ${locale} app names?`, 'Users of this language will see the default app name', async () => {
+            try {
+                const formData = new FormData();
+                formData.append('locale', locale);
+
+                const response = await fetch('/api/namer/locales', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Failed to remove app names');
+                }
+
+                showToast(`${locale} app names removed`, 'success');
+                await this.loadLocales();
+            } catch (error) {
+                console.error('Error removing localized app names:', error);
+                showToast(`Failed to remove app names: ${error.message}`, 'error');
+            }
+        });
+    }
+
+    escapeHtml(text) {
+        const div = document.createElement('div');
+        div.textContent = text;
+        return div.innerHTML;
+    }
+
     renderPlatformForms() {
         const container = document.getElementById('platform-forms');
 
@@ -159,8 +317,13 @@ class NamerManager {
             return;
         }
 
-        const message = `Set "${universalName}" as the app name for all available platforms?`;
-        const details = availablePlatforms.map(p => `• ${p.name}`).join('<br>');
+        const report = await this.validateNames({ universal: universalName });
+        if (!report) {
+            return;
+        }
+
+        const message = `Set "${this.escapeHtml(universalName)}" as the app name for all available platforms?`;
+        const details = availablePlatforms.map(p => `• ${p.name}`).join('<br>') + this.formatIssues(report.issues);
 
         this.showConfirmationDialog(message, details, async () => {
             await this.executeSetNames({ universal: universalName });
@@ -206,8 +369,13 @@ class NamerManager {
         const platform = this.platforms.find(p => p.id === platformId);
         const platformName = platform ? platform.name : this.capitalizeFirst(platformId);
 
-        const message = `Set "${newName}" as the app name for ${platformName}?`;
-        const details = `Platform: ${platformName}<br>New Name: "${newName}"`;
+        const report = await this.validateNames({ platforms: { [platformId]: newName } });
+        if (!report) {
+            return;
+        }
+
+        const message = `Set "${this.escapeHtml(newName)}" as the app name for ${platformName}?`;
+        const details = `Platform: ${platformName}<br>New Name: "${this.escapeHtml(newName)}"` + this.formatIssues(report.issues);
 
         this.showConfirmationDialog(message, details, async () => {
             await this.executeSetNames({ platforms: { [platformId]: newName } });
@@ -216,6 +384,110 @@ class NamerManager {
         });
     }
 
+    // Checks names against the platform rules; errors are shown and return null, as they can't be saved
+    async validateNames(request) {
+        try {
+            const response = await fetch('/api/namer/validate', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify(request)
+            });
+
+            if (!response.ok) {
+                const errorText = await response.text();
+                throw new Error(errorText || 'Failed to validate app names');
+            }
+
+            const data = await response.json();
+            const errors = data.report.issues.filter(issue => issue.severity === 'error');
+            if (errors.length > 0) {
+                errors.forEach(issue => showToast(`${issue.platform}: ${issue.message}`, 'error'));
+                return null;
+            }
+            return data.report;
+        } catch (error) {
+            console.error('Error validating app names:', error);
+            showToast(`Failed to validate app names: ${error.message}`, 'error');
+            return null;
+        }
+    }
+
+    formatIssues(issues) {
+        if (issues.length === 0) {
+            return '';
+        }
+        return '<br><br><strong>Warnings:</strong><br>' + issues.map(issue =>
+            `• ${this.escapeHtml(issue.platform)}: ${this.escapeHtml(issue.message)}`
+        ).join('<br>');
+    }
+
+    // Loads manifest.json and index.html metadata; the card stays hidden without a web platform
+    async loadWebMetadata() {
+        const group = document.getElementById('web-metadata-group');
+        try {
+            const response = await fetch('/api/namer/web');
+            if (!response.ok) {
+                group.style.display = 'none';
+                return;
+            }
+
+            this.webMetadata = await response.json();
+            document.querySelectorAll('.web-meta-input').forEach(input => {
+                input.value = this.webMetadata[input.dataset.field] || '';
+            });
+            group.style.display = 'block';
+        } catch (error) {
+            console.error('Error loading web metadata:', error);
+            group.style.display = 'none';
+        }
+    }
+
+    // Saves only the fields that were changed
+    async saveWebMetadata() {
+        const update = {};
+        document.querySelectorAll('.web-meta-input').forEach(input => {
+            const value = input.value.trim();
+            if (value !== (this.webMetadata[input.dataset.field] || '')) {
+                update[input.dataset.field] = value;
+            }
+        });
+
+        if (Object.keys(update).length === 0) {
+            showToast('No web metadata changes to save', 'info');
+            return;
+        }
+
+        this.showLoading();
+        try {
+            const response = await fetch('/api/namer/web', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify(update)
+            });
+
+            if (!response.ok) {
+                const errorText = await response.text();
+                throw new Error(errorText || 'Failed to save web metadata');
+            }
+
+            const data = await response.json();
+            showToast('Web metadata updated successfully!', 'success');
+            (data.warnings || []).forEach(issue => showToast(`${issue.field}: ${issue.message}`, 'warning'));
+
+            await this.loadWebMetadata();
+            await this.loadCurrentNames();
+        } catch (error) {
+            console.error('Error saving web metadata:', error);
+            showToast(`Failed to save web metadata: ${error.message}`, 'error');
+        } finally {
+            this.hideLoading();
+        }
+    }
+
     async executeSetNames(request) {
         this.showLoading();
 
@@ -233,11 +505,13 @@ class NamerManager {
                 throw new Error(errorText || 'Failed to set app names');
             }
 
-            await response.json();
+            const data = await response.json();
             showToast('App names updated successfully!', 'success');
+            (data.warnings || []).forEach(issue => showToast(`${issue.platform}: ${issue.message}`, 'warning'));
 
             // Refresh the current names display
             await this.loadCurrentNames();
+            await this.loadWebMetadata();
 
             // Clear forms
             this.clearAllForms();
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/Jerinji2016/fdawg/pkg/flutter"
 )
@@ -38,6 +40,27 @@ const (
 	MiscAsset AssetType = "misc"
 )
 
+// AssetTypes lists every asset type, in the order their directories are listed
+var AssetTypes = []AssetType{
+	ImageAsset,
+	AnimationAsset,
+	AudioAsset,
+	VideoAsset,
+	JSONAsset,
+	SVGAsset,
+	MiscAsset,
+}
+
+// IsValidAssetType reports whether assetType is one of AssetTypes
+func IsValidAssetType(assetType AssetType) bool {
+	for _, known := range AssetTypes {
+		if assetType == known {
+			return true
+		}
+	}
+	return false
+}
+
 // GetAssetDir returns the path to the asset directory for a Flutter project
 func GetAssetDir(projectPath string) string {
 	return filepath.Join(projectPath, AssetDirName)
@@ -233,180 +256,33 @@ func ListAssets(projectPath string) (map[AssetType][]string, error) {
 			continue
 		}
 
-		files, err := os.ReadDir(assetTypeDir)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read asset directory: %v", err)
-		}
-
+		// Walk the type directory recursively so nested folders are included.
+		// Names are returned relative to the type directory using forward slashes,
+		// e.g. "onboarding/step1.png".
 		var assetFiles []string
-		for _, file := range files {
-			if file.IsDir() {
-				continue
+		err := filepath.WalkDir(assetTypeDir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
 			}
-			assetFiles = append(assetFiles, file.Name())
-		}
-
-		assets[assetType] = assetFiles
-	}
-
-	return assets, nil
-}
-
-// MigrationResult represents the result of asset migration
-type MigrationResult struct {
-	FilesProcessed   int
-	NoFilesToMigrate bool
-}
-
-// MigrateAssets migrates assets from a flat structure to organized folders
-func MigrateAssets(projectPath string) (*MigrationResult, error) {
-	assetDir := GetAssetDir(projectPath)
-	backupDir := GetAssetBackupDir(projectPath)
-
-	// Check if the asset directory exists
-	if _, err := os.Stat(assetDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("asset directory does not exist")
-	}
-
-	// Create backup directory
-	if err := os.MkdirAll(backupDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create backup directory: %v", err)
-	}
-
-	// Find all files in the asset directory (including in subdirectories)
-	var filesToMigrate []string
-	err := filepath.Walk(assetDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip the root directory
-		if path == assetDir {
-			return nil
-		}
-
-		// Skip directories that match our asset type directories
-		relPath, err := filepath.Rel(assetDir, path)
-		if err != nil {
-			return err
-		}
-
-		// If it's a directory, check if it's one of our asset type directories or translations directory
-		if info.IsDir() {
-			// Skip translations directory to avoid conflicts with localization
-			if relPath == "translations" {
-				return filepath.SkipDir
+			if d.IsDir() {
+				return nil
 			}
 
-			// Skip asset type directories
-			for _, assetType := range []AssetType{
-				ImageAsset,
-				AnimationAsset,
-				AudioAsset,
-				VideoAsset,
-				JSONAsset,
-				SVGAsset,
-				MiscAsset,
-			} {
-				if relPath == string(assetType) {
-					return filepath.SkipDir
-				}
+			relPath, err := filepath.Rel(assetTypeDir, path)
+			if err != nil {
+				return err
 			}
+			assetFiles = append(assetFiles, filepath.ToSlash(relPath))
 			return nil
-		}
-
-		// It's a file, add it to the list
-		filesToMigrate = append(filesToMigrate, path)
-		return nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to walk asset directory: %v", err)
-	}
-
-	if len(filesToMigrate) == 0 {
-		return &MigrationResult{
-			FilesProcessed:   0,
-			NoFilesToMigrate: true,
-		}, nil
-	}
-
-	// Move all files to the backup directory
-	for _, filePath := range filesToMigrate {
-		// Get the relative path from the asset directory
-		relPath, err := filepath.Rel(assetDir, filePath)
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to get relative path: %v", err)
-		}
-
-		// Create the backup path
-		destPath := filepath.Join(backupDir, filepath.Base(filePath))
-
-		// Copy the file to the backup directory
-		if err := copyFile(filePath, destPath); err != nil {
-			return nil, fmt.Errorf("failed to backup file %s: %v", relPath, err)
-		}
-	}
-
-	// Now add each file to the appropriate directory
-	for _, filePath := range filesToMigrate {
-		// Determine the asset type
-		assetType := DetermineAssetType(filePath)
-
-		// Ensure the asset type directory exists
-		if err := EnsureAssetTypeDirExists(projectPath, assetType); err != nil {
-			return nil, fmt.Errorf("failed to create asset type directory: %v", err)
-		}
-
-		// Get the file name
-		fileName := filepath.Base(filePath)
-
-		// Copy the file to the appropriate directory
-		destPath := filepath.Join(assetDir, string(assetType), fileName)
-		if err := copyFile(filePath, destPath); err != nil {
-			return nil, fmt.Errorf("failed to copy file %s to %s: %v", fileName, assetType, err)
-		}
-
-		// Remove the original file
-		if err := os.Remove(filePath); err != nil {
-			return nil, fmt.Errorf("failed to remove original file %s: %v", filePath, err)
-		}
-	}
-
-	// Remove empty directories
-	removeEmptyDirs(assetDir)
-
-	// Update the pubspec.yaml file for each asset type
-	assetTypes := []AssetType{
-		ImageAsset,
-		AnimationAsset,
-		AudioAsset,
-		VideoAsset,
-		JSONAsset,
-		SVGAsset,
-		MiscAsset,
-	}
-
-	for _, assetType := range assetTypes {
-		assetTypeDir := filepath.Join(assetDir, string(assetType))
-		if _, err := os.Stat(assetTypeDir); os.IsNotExist(err) {
-			continue
-		}
-
-		if err := updatePubspecWithAsset(projectPath, assetType); err != nil {
-			return nil, fmt.Errorf("failed to update pubspec.yaml for %s: %v", assetType, err)
+			return nil, fmt.Errorf("failed to read asset directory: %v", err)
 		}
-	}
 
-	// Generate the Dart asset file
-	if err := GenerateDartAssetFile(projectPath); err != nil {
-		return nil, fmt.Errorf("failed to generate Dart asset file: %v", err)
+		assets[assetType] = assetFiles
 	}
 
-	return &MigrationResult{
-		FilesProcessed:   len(filesToMigrate),
-		NoFilesToMigrate: false,
-	}, nil
+	return assets, nil
 }
 
 // removeEmptyDirs removes empty directories recursively
@@ -463,6 +339,21 @@ func removeEmptyDirs(dir string) error {
 
 // updatePubspecWithAsset is implemented in pubspec_updater.go
 
+// assetClasses lists the generated class of each asset type and the Asset field holding it
+var assetClasses = []struct {
+	assetType AssetType
+	className string
+	fieldName string
+}{
+	{ImageAsset, "Images", "images"},
+	{AnimationAsset, "Animations", "animations"},
+	{AudioAsset, "Audio", "audio"},
+	{VideoAsset, "Videos", "videos"},
+	{JSONAsset, "Json", "json"},
+	{SVGAsset, "Svgs", "svgs"},
+	{MiscAsset, "Misc", "misc"},
+}
+
 // GenerateDartAssetFile generates a Dart asset file with all assets
 func GenerateDartAssetFile(projectPath string) error {
 	// List all assets
@@ -471,6 +362,29 @@ func GenerateDartAssetFile(projectPath string) error {
 		return fmt.Errorf("failed to list assets: %v", err)
 	}
 
+	content, _ := renderDartAssetFile(assets)
+
+	// Write the file
+	dartFilePath := filepath.Join(projectPath, "lib", "config")
+
+	// Ensure the directory exists
+	if err := os.MkdirAll(dartFilePath, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	dartFilePath = filepath.Join(dartFilePath, "asset.dart")
+
+	if err := os.WriteFile(dartFilePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write Dart file: %v", err)
+	}
+
+	return nil
+}
+
+// renderDartAssetFile returns the content of the Dart asset file for the given
+// assets, and the accessor of each asset by path, e.g. Asset.images.logo for
+// assets/images/logo.png
+func renderDartAssetFile(assets map[AssetType][]string) (string, map[string]string) {
 	// Create the Dart file content
 	var content strings.Builder
 
@@ -510,34 +424,60 @@ class Asset {
 
 `)
 
-	// Add asset classes
-	addAssetClass(&content, "Images", ImageAsset, assets[ImageAsset])
-	addAssetClass(&content, "Animations", AnimationAsset, assets[AnimationAsset])
-	addAssetClass(&content, "Audio", AudioAsset, assets[AudioAsset])
-	addAssetClass(&content, "Videos", VideoAsset, assets[VideoAsset])
-	addAssetClass(&content, "Json", JSONAsset, assets[JSONAsset])
-	addAssetClass(&content, "Svgs", SVGAsset, assets[SVGAsset])
-	addAssetClass(&content, "Misc", MiscAsset, assets[MiscAsset])
+	// Add asset classes, tracking class names so nested folders don't collide
+	usedClassNames := make(map[string]bool)
+	accessors := make(map[string]string)
+	for _, class := range assetClasses {
+		addAssetClass(&content, class.className, string(class.assetType), "Asset."+class.fieldName, buildAssetFolder(assets[class.assetType]), usedClassNames, accessors)
+	}
 
-	// Write the file
-	dartFilePath := filepath.Join(projectPath, "lib", "config")
+	return content.String(), accessors
+}
 
-	// Ensure the directory exists
-	if err := os.MkdirAll(dartFilePath, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %v", err)
-	}
+// assetFolder represents a directory of assets when generating nested Dart classes
+type assetFolder struct {
+	name       string
+	files      []string
+	subfolders []*assetFolder
+}
 
-	dartFilePath = filepath.Join(dartFilePath, "asset.dart")
+// buildAssetFolder builds a folder tree from slash-separated asset names as returned by ListAssets
+func buildAssetFolder(assetFiles []string) *assetFolder {
+	root := &assetFolder{}
 
-	if err := os.WriteFile(dartFilePath, []byte(content.String()), 0644); err != nil {
-		return fmt.Errorf("failed to write Dart file: %v", err)
+	for _, assetFile := range assetFiles {
+		parts := strings.Split(assetFile, "/")
+		folder := root
+
+		// Walk (and create) the folders leading up to the file
+		for _, dirName := range parts[:len(parts)-1] {
+			var next *assetFolder
+			for _, sub := range folder.subfolders {
+				if sub.name == dirName {
+					next = sub
+					break
+				}
+			}
+			if next == nil {
+				next = &assetFolder{name: dirName}
+				folder.subfolders = append(folder.subfolders, next)
+			}
+			folder = next
+		}
+
+		folder.files = append(folder.files, parts[len(parts)-1])
 	}
 
-	return nil
+	return root
 }
 
-// addAssetClass adds an asset class to the content builder
-func addAssetClass(content *strings.Builder, className string, assetType AssetType, assetFiles []string) {
+// addAssetClass adds an asset class to the content builder. Assets and subfolders
+// are instance members, as the classes are reached through the instances held by
+// Asset, e.g. Asset.images.onboarding.step1. The accessor of each asset, starting
+// from the given accessor of the class, is recorded in accessors.
+func addAssetClass(content *strings.Builder, className, assetDirPath, accessor string, folder *assetFolder, usedClassNames map[string]bool, accessors map[string]string) {
+	usedClassNames[className] = true
+
 	content.WriteString(fmt.Sprintf(`/// %s assets
 class %s {
   // Private constructor to prevent instantiation
@@ -545,28 +485,84 @@ class %s {
 
 `, className, className, className))
 
-	// Add constants for each asset
-	for _, assetFile := range assetFiles {
+	// Member names must be unique within a class
+	usedNames := make(map[string]bool)
+
+	// Add fields for each subfolder
+	subClassNames := make([]string, len(folder.subfolders))
+	fieldNames := make([]string, len(folder.subfolders))
+	for i, sub := range folder.subfolders {
+		fieldName := createDartVariableName(sub.name, usedNames)
+		fieldNames[i] = fieldName
+		subClassNames[i] = createDartClassName(className, sub.name, usedClassNames)
+
+		content.WriteString(fmt.Sprintf(`  /// %s/%s assets
+  final %s %s = %s._();
+
+`, assetDirPath, sub.name, subClassNames[i], fieldName, subClassNames[i]))
+	}
+
+	// Add getters for each asset
+	for _, assetFile := range folder.files {
 		// Create a valid Dart variable name
-		varName := createDartVariableName(assetFile)
+		varName := createDartVariableName(assetFile, usedNames)
+		accessors["assets/"+assetDirPath+"/"+assetFile] = accessor + "." + varName
 
-		// Add the constant
+		// Add the getter
 		content.WriteString(fmt.Sprintf(`  /// %s asset
-  static const String %s = 'assets/%s/%s';
+  String get %s => 'assets/%s/%s';
 
-`, assetFile, varName, assetType, assetFile))
+`, assetFile, varName, assetDirPath, assetFile))
 	}
 
 	content.WriteString("}\n\n")
+
+	// Add the nested classes for each subfolder
+	for i, sub := range folder.subfolders {
+		addAssetClass(content, subClassNames[i], assetDirPath+"/"+sub.name, accessor+"."+fieldNames[i], sub, usedClassNames, accessors)
+	}
 }
 
-// createDartVariableName creates a valid Dart variable name from a file name
-func createDartVariableName(fileName string) string {
+// createDartVariableName creates a valid Dart variable name from a file name.
+// If the name is already taken, the file extension and then a numeric suffix
+// are appended until it is unique (e.g. logo, logoSvg, logoSvg2).
+func createDartVariableName(fileName string, usedNames map[string]bool) string {
 	// Remove the file extension
-	name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	ext := filepath.Ext(fileName)
+	name := strings.TrimSuffix(fileName, ext)
 
 	// Use the common helper function to format the name
-	return flutter.FormatDartVariableName(name)
+	varName := flutter.FormatDartVariableName(name)
+
+	if usedNames[varName] && ext != "" {
+		varName = flutter.FormatDartVariableName(name + ext)
+	}
+
+	candidate := varName
+	for i := 2; usedNames[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", varName, i)
+	}
+
+	usedNames[candidate] = true
+	return candidate
+}
+
+// createDartClassName creates a unique PascalCase Dart class name for a
+// subfolder by appending the folder name to its parent class name
+func createDartClassName(parentClassName, folderName string, usedClassNames map[string]bool) string {
+	suffix := strings.TrimPrefix(flutter.FormatDartVariableName(folderName), "_")
+	if first, size := utf8.DecodeRuneInString(suffix); size > 0 {
+		suffix = string(unicode.ToUpper(first)) + suffix[size:]
+	}
+	className := parentClassName + suffix
+
+	candidate := className
+	for i := 2; usedClassNames[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", className, i)
+	}
+
+	usedClassNames[candidate] = true
+	return candidate
 }
 
 // copyFile copies a file from src to dst
@@ -0,0 +1,417 @@
+package asset
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DefaultPerceptualThreshold is the default maximum number of differing perceptual
+// hash bits (out of 64) for two images to be considered near-duplicates
+const DefaultPerceptualThreshold = 5
+
+// resolutionVariantDirRegex matches Flutter's resolution-aware asset directories, e.g. 2.0x or 3x
+var resolutionVariantDirRegex = regexp.MustCompile(`^\d+(\.\d+)?x$`)
+
+// DuplicateGroup is a set of assets with identical or visually similar content
+type DuplicateGroup struct {
+	// Keep is the asset path suggested to keep, e.g. assets/images/logo.png
+	Keep string `json:"keep"`
+	// Duplicates are the other asset paths in the group
+	Duplicates []string `json:"duplicates"`
+	// Exact is true when every file in the group is byte-identical
+	Exact bool `json:"exact"`
+}
+
+// DedupeOptions configures FindDuplicates
+type DedupeOptions struct {
+	// Threshold is the maximum perceptual hash distance for near-duplicate images
+	Threshold int
+	// ExactOnly skips perceptual comparison of images
+	ExactOnly bool
+}
+
+// MergeResult represents the result of merging a duplicate group
+type MergeResult struct {
+	FilesRemoved      int
+	ReferencesUpdated int
+	UpdatedDartFiles  []string
+}
+
+// FindDuplicates finds byte-identical assets by content hash and, unless
+// ExactOnly is set, visually near-identical raster images by perceptual hash
+func FindDuplicates(projectPath string, options DedupeOptions) ([]DuplicateGroup, error) {
+	metadata, err := GetAssetMetadata(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []DuplicateGroup
+
+	// Group byte-identical files by content hash
+	pathsByHash := make(map[string][]string)
+	var hashes []string
+	for _, items := range metadata {
+		for _, item := range items {
+			if _, ok := pathsByHash[item.Hash]; !ok {
+				hashes = append(hashes, item.Hash)
+			}
+			pathsByHash[item.Hash] = append(pathsByHash[item.Hash], item.Path)
+		}
+	}
+
+	// Exact duplicates that won't be kept are left out of the perceptual comparison
+	exactDuplicates := make(map[string]bool)
+	for _, hash := range hashes {
+		paths := pathsByHash[hash]
+		if len(paths) < 2 {
+			continue
+		}
+
+		group := newDuplicateGroup(paths, true)
+		for _, path := range group.Duplicates {
+			exactDuplicates[path] = true
+		}
+		groups = append(groups, group)
+	}
+
+	if !options.ExactOnly {
+		nearGroups, err := findNearDuplicateImages(projectPath, metadata[ImageAsset], exactDuplicates, options.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, nearGroups...)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Keep < groups[j].Keep
+	})
+
+	return groups, nil
+}
+
+// findNearDuplicateImages groups raster images whose perceptual hashes are within the threshold
+func findNearDuplicateImages(projectPath string, images []AssetMetadata, skip map[string]bool, threshold int) ([]DuplicateGroup, error) {
+	if threshold <= 0 {
+		threshold = DefaultPerceptualThreshold
+	}
+
+	type hashedImage struct {
+		path string
+		hash uint64
+	}
+
+	var hashed []hashedImage
+	for _, item := range images {
+		// Resolution variants look like the base image on purpose
+		if skip[item.Path] || isResolutionVariant(item.Path) {
+			continue
+		}
+
+		hash, ok := perceptualHash(filepath.Join(projectPath, filepath.FromSlash(item.Path)))
+		if !ok {
+			continue
+		}
+		hashed = append(hashed, hashedImage{path: item.Path, hash: hash})
+	}
+
+	// Union images that are close to each other
+	parent := make([]int, len(hashed))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	hasNearPair := make(map[int]bool)
+	for i := 0; i < len(hashed); i++ {
+		for j := i + 1; j < len(hashed); j++ {
+			if bits.OnesCount64(hashed[i].hash^hashed[j].hash) <= threshold {
+				parent[find(i)] = find(j)
+				hasNearPair[i] = true
+				hasNearPair[j] = true
+			}
+		}
+	}
+
+	members := make(map[int][]string)
+	var roots []int
+	for i := range hashed {
+		if !hasNearPair[i] {
+			continue
+		}
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], hashed[i].path)
+	}
+
+	var groups []DuplicateGroup
+	for _, root := range roots {
+		if len(members[root]) < 2 {
+			continue
+		}
+		groups = append(groups, newDuplicateGroup(members[root], false))
+	}
+
+	return groups, nil
+}
+
+// isResolutionVariant reports whether an asset is in a resolution-aware directory such as images/2.0x/
+func isResolutionVariant(assetPath string) bool {
+	parts := strings.Split(assetPath, "/")
+	for _, part := range parts[:len(parts)-1] {
+		if resolutionVariantDirRegex.MatchString(part) {
+			return true
+		}
+	}
+	return false
+}
+
+// newDuplicateGroup creates a group, suggesting the least nested, shortest path to keep
+func newDuplicateGroup(paths []string, exact bool) DuplicateGroup {
+	sorted := append([]string(nil), paths...)
+	sort.Slice(sorted, func(i, j int) bool {
+		depthI, depthJ := strings.Count(sorted[i], "/"), strings.Count(sorted[j], "/")
+		if depthI != depthJ {
+			return depthI < depthJ
+		}
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) < len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	return DuplicateGroup{
+		Keep:       sorted[0],
+		Duplicates: sorted[1:],
+		Exact:      exact,
+	}
+}
+
+// perceptualHash computes a 64-bit difference hash (dHash) of an image. Images
+// that look alike produce hashes with a small Hamming distance, even after
+// resizing or recompression.
+func perceptualHash(filePath string) (uint64, bool) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return 0, false
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return 0, false
+	}
+
+	// Downscale to 9x8 grayscale by averaging each cell
+	const width, height = 9, 8
+	var gray [height][width]float64
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/width, x0+1)
+
+			var sum float64
+			var count int
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					r, g, b, a := img.At(px, py).RGBA()
+					// Blend transparent pixels onto white so icons with alpha compare sensibly
+					alpha := float64(a) / 0xffff
+					luma := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 0xffff
+					sum += luma + (1 - alpha)
+					count++
+				}
+			}
+			gray[y][x] = sum / float64(count)
+		}
+	}
+
+	// Each bit records whether brightness increases to the right
+	var hash uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			hash <<= 1
+			if gray[y][x] < gray[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash, true
+}
+
+// MergeDuplicates keeps one asset and removes its duplicates. Removed files are
+// backed up to the asset backup directory, string and generated accessor references
+// to them in lib/ are rewritten to the kept asset, folders left empty are removed
+// along with their pubspec.yaml entries, and the Dart asset file is regenerated.
+func MergeDuplicates(projectPath, keep string, duplicates []string) (*MergeResult, error) {
+	keepPath, err := resolveAssetPath(projectPath, keep)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(keepPath); err != nil {
+		return nil, fmt.Errorf("asset to keep does not exist: %s", keep)
+	}
+
+	// Accessors are named per folder, so removing a file can rename its neighbours
+	// (logo.svg is logoSvg next to logo.png, but logo on its own)
+	assets, err := ListAssets(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assets: %v", err)
+	}
+	_, oldAccessors := renderDartAssetFile(assets)
+
+	assetDir := GetAssetDir(projectPath)
+	backupDir := GetAssetBackupDir(projectPath)
+	result := &MergeResult{}
+	replacements := make(map[string]string)
+	var removedDirs []string
+
+	for _, duplicate := range duplicates {
+		if duplicate == keep {
+			continue
+		}
+
+		duplicatePath, err := resolveAssetPath(projectPath, duplicate)
+		if err != nil {
+			return nil, err
+		}
+
+		// Back up the file, keeping its path relative to the asset directory
+		relPath, err := filepath.Rel(GetAssetDir(projectPath), duplicatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get relative path: %v", err)
+		}
+		backupPath := filepath.Join(backupDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create backup directory: %v", err)
+		}
+		if err := copyFile(duplicatePath, backupPath); err != nil {
+			return nil, fmt.Errorf("failed to backup file %s: %v", duplicate, err)
+		}
+
+		if err := os.Remove(duplicatePath); err != nil {
+			return nil, fmt.Errorf("failed to remove duplicate %s: %v", duplicate, err)
+		}
+
+		replacements[duplicate] = keep
+		result.FilesRemoved++
+
+		dirs, err := removeEmptyParentDirs(filepath.Dir(duplicatePath), assetDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove empty folders: %v", err)
+		}
+		for _, dir := range dirs {
+			relDir, err := filepath.Rel(projectPath, dir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get relative path: %v", err)
+			}
+			removedDirs = append(removedDirs, filepath.ToSlash(relDir))
+		}
+	}
+
+	if err := RemoveMissingPubspecAssetDirs(projectPath, removedDirs); err != nil {
+		return nil, err
+	}
+
+	references, err := updateAssetReferences(projectPath, replacements, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, reference := range references {
+		if !slices.Contains(result.UpdatedDartFiles, reference.File) {
+			result.UpdatedDartFiles = append(result.UpdatedDartFiles, reference.File)
+		}
+		result.ReferencesUpdated += reference.Count
+	}
+
+	assets, err = ListAssets(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assets: %v", err)
+	}
+	_, newAccessors := renderDartAssetFile(assets)
+
+	accessorReplacements := make(map[string]string)
+	for assetPath, oldAccessor := range oldAccessors {
+		newAccessor, ok := newAccessors[assetPath]
+		if !ok {
+			newAccessor, ok = newAccessors[replacements[assetPath]]
+		}
+		if ok && newAccessor != oldAccessor {
+			accessorReplacements[oldAccessor] = newAccessor
+		}
+	}
+
+	references, err = updateAccessorReferences(projectPath, accessorReplacements)
+	if err != nil {
+		return nil, err
+	}
+	for _, reference := range references {
+		if !slices.Contains(result.UpdatedDartFiles, reference.File) {
+			result.UpdatedDartFiles = append(result.UpdatedDartFiles, reference.File)
+		}
+		result.ReferencesUpdated += reference.Count
+	}
+
+	if err := GenerateDartAssetFile(projectPath); err != nil {
+		return nil, fmt.Errorf("failed to generate Dart asset file: %v", err)
+	}
+
+	return result, nil
+}
+
+// removeEmptyParentDirs removes dir and its parents while they are empty, stopping
+// at asset type directories and at stopDir. It returns the removed directories.
+func removeEmptyParentDirs(dir, stopDir string) ([]string, error) {
+	var removed []string
+	for dir != stopDir && filepath.Dir(dir) != stopDir {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return removed, err
+		}
+		if len(entries) > 0 {
+			break
+		}
+		if err := os.Remove(dir); err != nil {
+			return removed, err
+		}
+		removed = append(removed, dir)
+		dir = filepath.Dir(dir)
+	}
+	return removed, nil
+}
+
+// resolveAssetPath converts an asset path such as assets/images/logo.png to a
+// file path, making sure it points inside the asset directory
+func resolveAssetPath(projectPath, assetPath string) (string, error) {
+	filePath := filepath.Join(projectPath, filepath.FromSlash(assetPath))
+
+	relPath, err := filepath.Rel(GetAssetDir(projectPath), filePath)
+	if err != nil || relPath == "." || strings.HasPrefix(relPath, "..") {
+		return "", fmt.Errorf("not an asset path: %s", assetPath)
+	}
+
+	return filePath, nil
+}
@@ -0,0 +1,361 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/gif"  // register GIF decoder for image.DecodeConfig
+	_ "image/jpeg" // register JPEG decoder for image.DecodeConfig
+	_ "image/png"  // register PNG decoder for image.DecodeConfig
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	svgTagRegex     = regexp.MustCompile(`(?s)<svg\b[^>]*>`)
+	svgWidthRegex   = regexp.MustCompile(`\swidth\s*=\s*["']([\d.]+)(px)?["']`)
+	svgHeightRegex  = regexp.MustCompile(`\sheight\s*=\s*["']([\d.]+)(px)?["']`)
+	svgViewBoxRegex = regexp.MustCompile(`\sviewBox\s*=\s*["']\s*[-\d.]+[\s,]+[-\d.]+[\s,]+([\d.]+)[\s,]+([\d.]+)\s*["']`)
+)
+
+// readImageSize returns the pixel dimensions of a raster image, or zeros if they can't be read
+func readImageSize(filePath string) (int, int) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".webp":
+		return readWebPSize(file)
+	case ".bmp":
+		return readBMPSize(file)
+	}
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0
+	}
+
+	return config.Width, config.Height
+}
+
+// readWebPSize reads the canvas size from a WebP header
+func readWebPSize(r io.Reader) (int, int) {
+	header := make([]byte, 30)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, 0
+	}
+
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WEBP" {
+		return 0, 0
+	}
+
+	switch string(header[12:16]) {
+	case "VP8 ":
+		// Lossy: 14-bit width and height after the frame tag and start code
+		width := int(binary.LittleEndian.Uint16(header[26:28]) & 0x3fff)
+		height := int(binary.LittleEndian.Uint16(header[28:30]) & 0x3fff)
+		return width, height
+	case "VP8L":
+		// Lossless: 14-bit width-1 and height-1 packed after the signature byte
+		bits := binary.LittleEndian.Uint32(header[21:25])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1
+	case "VP8X":
+		// Extended: 24-bit canvas width-1 and height-1
+		width := int(header[24]) | int(header[25])<<8 | int(header[26])<<16
+		height := int(header[27]) | int(header[28])<<8 | int(header[29])<<16
+		return width + 1, height + 1
+	}
+
+	return 0, 0
+}
+
+// readBMPSize reads the dimensions from a BMP header
+func readBMPSize(r io.Reader) (int, int) {
+	header := make([]byte, 26)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, 0
+	}
+
+	if string(header[0:2]) != "BM" {
+		return 0, 0
+	}
+
+	width := int(int32(binary.LittleEndian.Uint32(header[18:22])))
+	height := int(int32(binary.LittleEndian.Uint32(header[22:26])))
+	if height < 0 {
+		// Top-down bitmaps store a negative height
+		height = -height
+	}
+
+	return width, height
+}
+
+// readSVGSize returns the size declared on the root svg element, falling back to the viewBox
+func readSVGSize(filePath string) (int, int) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, 0
+	}
+
+	tag := svgTagRegex.Find(data)
+	if tag == nil {
+		return 0, 0
+	}
+
+	widthMatch := svgWidthRegex.FindSubmatch(tag)
+	heightMatch := svgHeightRegex.FindSubmatch(tag)
+	if widthMatch != nil && heightMatch != nil {
+		return parseSVGLength(widthMatch[1]), parseSVGLength(heightMatch[1])
+	}
+
+	if viewBoxMatch := svgViewBoxRegex.FindSubmatch(tag); viewBoxMatch != nil {
+		return parseSVGLength(viewBoxMatch[1]), parseSVGLength(viewBoxMatch[2])
+	}
+
+	return 0, 0
+}
+
+// parseSVGLength parses a numeric SVG length, rounding to whole pixels
+func parseSVGLength(value []byte) int {
+	f, err := strconv.ParseFloat(string(value), 64)
+	if err != nil {
+		return 0
+	}
+	return int(f + 0.5)
+}
+
+// readMediaDuration returns the duration in seconds of an audio or video file,
+// or zero if the format isn't supported or the header can't be parsed
+func readMediaDuration(filePath string) float64 {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".wav":
+		return readWAVDuration(file)
+	case ".mp4", ".m4a", ".m4v", ".mov":
+		return readMP4Duration(file, info.Size())
+	case ".flac":
+		return readFLACDuration(file)
+	case ".mp3":
+		return readMP3Duration(file, info.Size())
+	}
+
+	return 0
+}
+
+// readWAVDuration computes the duration from the fmt and data chunks of a RIFF/WAVE file
+func readWAVDuration(r io.ReadSeeker) float64 {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return 0
+	}
+
+	var byteRate uint32
+	chunkHeader := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, chunkHeader); err != nil {
+			return 0
+		}
+
+		chunkID := string(chunkHeader[0:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(chunkHeader[4:8]))
+
+		switch chunkID {
+		case "fmt ":
+			fmtData := make([]byte, 12)
+			if _, err := io.ReadFull(r, fmtData); err != nil {
+				return 0
+			}
+			byteRate = binary.LittleEndian.Uint32(fmtData[8:12])
+			chunkSize -= 12
+		case "data":
+			if byteRate == 0 {
+				return 0
+			}
+			return float64(chunkSize) / float64(byteRate)
+		}
+
+		// Chunks are padded to an even size
+		if _, err := r.Seek(chunkSize+chunkSize%2, io.SeekCurrent); err != nil {
+			return 0
+		}
+	}
+}
+
+// readMP4Duration reads the duration from the mvhd box inside the moov box of an ISO media file
+func readMP4Duration(r io.ReadSeeker, size int64) float64 {
+	moovOffset, moovSize := findMP4Box(r, 0, size, "moov")
+	if moovOffset < 0 {
+		return 0
+	}
+
+	mvhdOffset, _ := findMP4Box(r, moovOffset, moovOffset+moovSize, "mvhd")
+	if mvhdOffset < 0 {
+		return 0
+	}
+
+	if _, err := r.Seek(mvhdOffset, io.SeekStart); err != nil {
+		return 0
+	}
+
+	header := make([]byte, 32)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0
+	}
+
+	var timescale uint32
+	var duration uint64
+	if header[0] == 1 {
+		// Version 1 uses 64-bit creation/modification times and duration
+		timescale = binary.BigEndian.Uint32(header[20:24])
+		duration = binary.BigEndian.Uint64(header[24:32])
+	} else {
+		timescale = binary.BigEndian.Uint32(header[12:16])
+		duration = uint64(binary.BigEndian.Uint32(header[16:20]))
+	}
+
+	if timescale == 0 {
+		return 0
+	}
+
+	return float64(duration) / float64(timescale)
+}
+
+// findMP4Box scans the boxes between start and end for one of the given type,
+// returning the offset and size of its payload, or -1 if it isn't found
+func findMP4Box(r io.ReadSeeker, start, end int64, boxType string) (int64, int64) {
+	header := make([]byte, 16)
+	offset := start
+
+	for offset+8 <= end {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return -1, 0
+		}
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return -1, 0
+		}
+
+		boxSize := int64(binary.BigEndian.Uint32(header[0:4]))
+		headerSize := int64(8)
+
+		switch boxSize {
+		case 0:
+			// Box extends to the end of the file
+			boxSize = end - offset
+		case 1:
+			// 64-bit extended size follows the type
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return -1, 0
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+
+		if boxSize < headerSize {
+			return -1, 0
+		}
+
+		if string(header[4:8]) == boxType {
+			return offset + headerSize, boxSize - headerSize
+		}
+
+		offset += boxSize
+	}
+
+	return -1, 0
+}
+
+// readFLACDuration reads the sample rate and total samples from the FLAC STREAMINFO block
+func readFLACDuration(r io.Reader) float64 {
+	header := make([]byte, 26)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0
+	}
+	if string(header[0:4]) != "fLaC" || header[4]&0x7f != 0 {
+		return 0
+	}
+
+	// STREAMINFO: sample rate is 20 bits, total samples is 36 bits
+	streamInfo := header[8:]
+	sampleRate := uint64(streamInfo[10])<<12 | uint64(streamInfo[11])<<4 | uint64(streamInfo[12])>>4
+	totalSamples := uint64(streamInfo[13]&0x0f)<<32 | uint64(binary.BigEndian.Uint32(streamInfo[14:18]))
+
+	if sampleRate == 0 {
+		return 0
+	}
+
+	return float64(totalSamples) / float64(sampleRate)
+}
+
+// mp3Bitrates maps the bitrate index to kbps for MPEG-1 and MPEG-2/2.5 Layer III
+var mp3Bitrates = map[bool][16]int{
+	true:  {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	false: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+}
+
+// readMP3Duration estimates the duration of an MP3 from the bitrate of its first frame.
+// This is exact for constant bitrate files and an approximation for variable bitrate ones.
+func readMP3Duration(r io.ReadSeeker, size int64) float64 {
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0
+	}
+
+	// Skip an ID3v2 tag if present; its size is stored as a 28-bit syncsafe integer
+	var audioStart int64
+	if bytes.Equal(header[0:3], []byte("ID3")) {
+		audioStart = 10 + (int64(header[6]&0x7f)<<21 | int64(header[7]&0x7f)<<14 | int64(header[8]&0x7f)<<7 | int64(header[9]&0x7f))
+	}
+
+	if _, err := r.Seek(audioStart, io.SeekStart); err != nil {
+		return 0
+	}
+
+	// Find the first frame sync within a small window
+	buf := make([]byte, 4096)
+	n, _ := io.ReadFull(r, buf)
+	buf = buf[:n]
+
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] != 0xff || buf[i+1]&0xe0 != 0xe0 {
+			continue
+		}
+
+		version := (buf[i+1] >> 3) & 0x03
+		layer := (buf[i+1] >> 1) & 0x03
+		bitrateIndex := buf[i+2] >> 4
+		if version == 1 || layer != 1 {
+			// Reserved version or not Layer III
+			continue
+		}
+
+		kbps := mp3Bitrates[version == 3][bitrateIndex]
+		if kbps == 0 {
+			continue
+		}
+
+		audioBytes := size - audioStart - int64(i)
+		return float64(audioBytes*8) / float64(kbps*1000)
+	}
+
+	return 0
+}
@@ -0,0 +1,222 @@
+package asset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// StateDirName is the name of the directory holding fdawg project state
+	StateDirName = ".fdawg"
+	// MetadataCacheFileName is the name of the file caching asset metadata
+	MetadataCacheFileName = "asset_metadata.json"
+)
+
+// AssetMetadata contains details about a single asset file
+type AssetMetadata struct {
+	Name    string    `json:"name"`
+	Type    AssetType `json:"type"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Hash    string    `json:"hash"`
+
+	// Width and Height are set for images, SVGs with explicit sizes and Lottie animations
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+	// Duration is the length in seconds for audio, video and Lottie animations
+	Duration float64 `json:"duration,omitempty"`
+	// FrameRate is set for Lottie animations
+	FrameRate float64 `json:"frame_rate,omitempty"`
+
+	// Duplicates lists the paths of other assets with identical content
+	Duplicates []string `json:"duplicates,omitempty"`
+}
+
+// GetMetadataCachePath returns the path to the asset metadata cache file for a Flutter project
+func GetMetadataCachePath(projectPath string) string {
+	return filepath.Join(projectPath, StateDirName, MetadataCacheFileName)
+}
+
+// GetAssetMetadata returns metadata for all assets in the project, grouped by type
+// in the same order as ListAssets. Metadata is cached and only recomputed for files
+// whose size or modification time changed.
+func GetAssetMetadata(projectPath string) (map[AssetType][]AssetMetadata, error) {
+	assets, err := ListAssets(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := loadMetadataCache(projectPath)
+	updatedCache := make(map[string]AssetMetadata)
+	assetDir := GetAssetDir(projectPath)
+
+	result := make(map[AssetType][]AssetMetadata)
+	for assetType, assetFiles := range assets {
+		for _, assetFile := range assetFiles {
+			filePath := filepath.Join(assetDir, string(assetType), filepath.FromSlash(assetFile))
+			info, err := os.Stat(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read asset %s: %v", assetFile, err)
+			}
+
+			relPath := fmt.Sprintf("%s/%s/%s", AssetDirName, assetType, assetFile)
+
+			metadata, ok := cache[relPath]
+			if !ok || metadata.Size != info.Size() || !metadata.ModTime.Equal(info.ModTime()) {
+				metadata, err = computeAssetMetadata(filePath, info)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read metadata for %s: %v", assetFile, err)
+				}
+			}
+
+			metadata.Name = assetFile
+			metadata.Type = assetType
+			metadata.Path = relPath
+			metadata.Duplicates = nil
+
+			updatedCache[relPath] = metadata
+			result[assetType] = append(result[assetType], metadata)
+		}
+	}
+
+	markDuplicates(result)
+
+	// A stale or unwritable cache only costs recomputation, so don't fail the listing
+	_ = saveMetadataCache(projectPath, updatedCache)
+
+	return result, nil
+}
+
+// markDuplicates fills in the Duplicates field of assets sharing the same content hash
+func markDuplicates(result map[AssetType][]AssetMetadata) {
+	pathsByHash := make(map[string][]string)
+	for _, items := range result {
+		for _, item := range items {
+			pathsByHash[item.Hash] = append(pathsByHash[item.Hash], item.Path)
+		}
+	}
+
+	for _, items := range result {
+		for i := range items {
+			paths := pathsByHash[items[i].Hash]
+			if len(paths) < 2 {
+				continue
+			}
+
+			for _, path := range paths {
+				if path != items[i].Path {
+					items[i].Duplicates = append(items[i].Duplicates, path)
+				}
+			}
+			sort.Strings(items[i].Duplicates)
+		}
+	}
+}
+
+// computeAssetMetadata reads an asset file and extracts its metadata
+func computeAssetMetadata(filePath string, info os.FileInfo) (AssetMetadata, error) {
+	metadata := AssetMetadata{
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+
+	hash, err := hashFile(filePath)
+	if err != nil {
+		return metadata, err
+	}
+	metadata.Hash = hash
+
+	// Media headers are best-effort: unknown or malformed files simply have no details
+	ext := strings.ToLower(filepath.Ext(filePath))
+	switch {
+	case ext == ".svg":
+		metadata.Width, metadata.Height = readSVGSize(filePath)
+	case ext == ".json":
+		readLottieMetadata(filePath, &metadata)
+	case DetermineAssetType(filePath) == ImageAsset:
+		metadata.Width, metadata.Height = readImageSize(filePath)
+	default:
+		metadata.Duration = readMediaDuration(filePath)
+	}
+
+	return metadata, nil
+}
+
+// hashFile returns the hex-encoded SHA-256 hash of a file
+func hashFile(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// readLottieMetadata fills in frame rate, size and duration from a Lottie JSON file
+func readLottieMetadata(filePath string, metadata *AssetMetadata) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+
+	var lottie struct {
+		Version   string          `json:"v"`
+		FrameRate float64         `json:"fr"`
+		InPoint   float64         `json:"ip"`
+		OutPoint  float64         `json:"op"`
+		Width     float64         `json:"w"`
+		Height    float64         `json:"h"`
+		Layers    json.RawMessage `json:"layers"`
+	}
+	if err := json.Unmarshal(data, &lottie); err != nil {
+		return
+	}
+
+	// Plain JSON data files won't have the Lottie version and layers
+	if lottie.Version == "" || lottie.Layers == nil || lottie.FrameRate <= 0 {
+		return
+	}
+
+	metadata.FrameRate = lottie.FrameRate
+	metadata.Width = int(lottie.Width)
+	metadata.Height = int(lottie.Height)
+	if lottie.OutPoint > lottie.InPoint {
+		metadata.Duration = (lottie.OutPoint - lottie.InPoint) / lottie.FrameRate
+	}
+}
+
+// loadMetadataCache loads the cached metadata, returning an empty cache if it is missing or invalid
+func loadMetadataCache(projectPath string) map[string]AssetMetadata {
+	cache := make(map[string]AssetMetadata)
+
+	data, err := os.ReadFile(GetMetadataCachePath(projectPath))
+	if err != nil {
+		return cache
+	}
+
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(map[string]AssetMetadata)
+	}
+
+	return cache
+}
+
+// saveMetadataCache writes the metadata cache
+func saveMetadataCache(projectPath string, cache map[string]AssetMetadata) error {
+	return writeStateFile(GetMetadataCachePath(projectPath), cache)
+}
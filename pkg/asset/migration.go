@@ -0,0 +1,598 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MigrationPlanFileName is the name of the file holding a pending migration plan
+	MigrationPlanFileName = "asset_migration_plan.json"
+	// MigrationUndoFileName is the name of the file recording the last applied migration
+	MigrationUndoFileName = "asset_migration_undo.json"
+)
+
+// MigrationResult represents the result of asset migration
+type MigrationResult struct {
+	FilesProcessed    int
+	NoFilesToMigrate  bool
+	ReferencesUpdated int
+}
+
+// MigrationMove describes moving a single asset into its type folder
+type MigrationMove struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	Type AssetType `json:"type"`
+}
+
+// MigrationPlan describes the changes a migration will make. Paths are relative
+// to the project and use forward slashes, e.g. assets/icons/home.png.
+type MigrationPlan struct {
+	CreatedAt        time.Time        `json:"created_at"`
+	Moves            []MigrationMove  `json:"moves"`
+	PubspecAdditions []string         `json:"pubspec_additions"`
+	PubspecRemovals  []string         `json:"pubspec_removals"`
+	References       []AssetReference `json:"references"`
+}
+
+// MigrationUndoLog records what an applied migration changed so it can be reverted
+type MigrationUndoLog struct {
+	AppliedAt   time.Time       `json:"applied_at"`
+	Moves       []MigrationMove `json:"moves"`
+	CreatedDirs []string        `json:"created_dirs"`
+	Pubspec     string          `json:"pubspec"`
+	// DartFiles holds the original content of every Dart file whose references were rewritten
+	DartFiles map[string]string `json:"dart_files"`
+	// AppliedHashes holds the hash of pubspec.yaml and each of DartFiles once the
+	// migration finished, so undo can refuse to overwrite later edits
+	AppliedHashes map[string]string `json:"applied_hashes,omitempty"`
+}
+
+// GetMigrationPlanPath returns the path to the pending migration plan file
+func GetMigrationPlanPath(projectPath string) string {
+	return filepath.Join(projectPath, StateDirName, MigrationPlanFileName)
+}
+
+// GetMigrationUndoPath returns the path to the migration undo log
+func GetMigrationUndoPath(projectPath string) string {
+	return filepath.Join(projectPath, StateDirName, MigrationUndoFileName)
+}
+
+// MigrateAssets migrates assets from a flat structure to organized folders.
+// It creates a plan and applies it immediately, so the result can still be undone.
+func MigrateAssets(projectPath string) (*MigrationResult, error) {
+	plan, err := CreateMigrationPlan(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plan.Moves) == 0 {
+		return &MigrationResult{
+			FilesProcessed:   0,
+			NoFilesToMigrate: true,
+		}, nil
+	}
+
+	return ApplyMigrationPlan(projectPath, plan)
+}
+
+// CreateMigrationPlan works out where each asset outside the type folders
+// should move, and which pubspec.yaml entries and lib/ references will change.
+// Files keep their subfolder, so assets/icons/home.png moves to
+// assets/images/icons/home.png.
+func CreateMigrationPlan(projectPath string) (*MigrationPlan, error) {
+	assetDir := GetAssetDir(projectPath)
+
+	// Check if the asset directory exists
+	if _, err := os.Stat(assetDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("asset directory does not exist")
+	}
+
+	plan := &MigrationPlan{
+		CreatedAt: time.Now(),
+	}
+
+	// Find all files outside the asset type and translations directories
+	var allFiles []string
+	err := filepath.Walk(assetDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip the root directory
+		if path == assetDir {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(assetDir, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if info.IsDir() {
+			return nil
+		}
+
+		allFiles = append(allFiles, AssetDirName+"/"+relPath)
+
+		// Skip translations to avoid conflicts with localization, and already organized files
+		topDir := strings.Split(relPath, "/")[0]
+		if topDir == "translations" || (topDir != relPath && isAssetTypeDir(topDir)) {
+			return nil
+		}
+
+		assetType := DetermineAssetType(path)
+		plan.Moves = append(plan.Moves, MigrationMove{
+			From: AssetDirName + "/" + relPath,
+			To:   fmt.Sprintf("%s/%s/%s", AssetDirName, assetType, relPath),
+			Type: assetType,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk asset directory: %v", err)
+	}
+
+	if len(plan.Moves) == 0 {
+		return plan, nil
+	}
+
+	// Avoid overwriting files that already exist at the destination
+	taken := make(map[string]bool)
+	for _, file := range allFiles {
+		taken[file] = true
+	}
+	for i := range plan.Moves {
+		plan.Moves[i].To = uniqueAssetPath(plan.Moves[i].To, taken)
+		taken[plan.Moves[i].To] = true
+	}
+
+	if err := planPubspecChanges(projectPath, plan, allFiles); err != nil {
+		return nil, err
+	}
+
+	replacements := make(map[string]string)
+	for _, move := range plan.Moves {
+		replacements[move.From] = move.To
+	}
+	plan.References, err = updateAssetReferences(projectPath, replacements, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// isAssetTypeDir reports whether a directory name is one of the asset type directories
+func isAssetTypeDir(name string) bool {
+	for _, assetType := range []AssetType{
+		ImageAsset,
+		AnimationAsset,
+		AudioAsset,
+		VideoAsset,
+		JSONAsset,
+		SVGAsset,
+		MiscAsset,
+	} {
+		if name == string(assetType) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueAssetPath appends a numeric suffix to the file name until the path is not taken
+func uniqueAssetPath(assetPath string, taken map[string]bool) string {
+	if !taken[assetPath] {
+		return assetPath
+	}
+
+	ext := filepath.Ext(assetPath)
+	base := strings.TrimSuffix(assetPath, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d%s", base, i, ext)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// planPubspecChanges fills in the pubspec.yaml entries to add for the new folders
+// and the existing entries that will point at nothing once files have moved
+func planPubspecChanges(projectPath string, plan *MigrationPlan, allFiles []string) error {
+	pubspecData, err := os.ReadFile(filepath.Join(projectPath, "pubspec.yaml"))
+	if err != nil {
+		return fmt.Errorf("failed to read pubspec.yaml: %v", err)
+	}
+
+	var pubspec struct {
+		Flutter struct {
+			Assets []string `yaml:"assets"`
+		} `yaml:"flutter"`
+	}
+	if err := yaml.Unmarshal(pubspecData, &pubspec); err != nil {
+		return fmt.Errorf("failed to parse pubspec.yaml: %v", err)
+	}
+
+	existing := make(map[string]bool)
+	for _, entry := range pubspec.Flutter.Assets {
+		existing[entry] = true
+	}
+
+	// Work out which files exist before and after the migration
+	moved := make(map[string]bool)
+	for _, move := range plan.Moves {
+		moved[move.From] = true
+	}
+	var before, after []string
+	before = append(before, allFiles...)
+	for _, file := range allFiles {
+		if !moved[file] {
+			after = append(after, file)
+		}
+	}
+	for _, move := range plan.Moves {
+		after = append(after, move.To)
+	}
+
+	// Every destination folder needs its own entry
+	additions := make(map[string]bool)
+	for _, move := range plan.Moves {
+		typeDir := fmt.Sprintf("%s/%s/", AssetDirName, move.Type)
+		dir := filepath.ToSlash(filepath.Dir(move.To)) + "/"
+		for ; strings.HasPrefix(dir, typeDir); dir = filepath.ToSlash(filepath.Dir(strings.TrimSuffix(dir, "/"))) + "/" {
+			if !existing[dir] {
+				additions[dir] = true
+			}
+		}
+	}
+	for dir := range additions {
+		plan.PubspecAdditions = append(plan.PubspecAdditions, dir)
+	}
+	sort.Strings(plan.PubspecAdditions)
+
+	// Remove entries under assets/ that exist now but won't afterwards
+	for _, entry := range pubspec.Flutter.Assets {
+		if !strings.HasPrefix(entry, AssetDirName+"/") || entry == AssetDirName+"/" {
+			continue
+		}
+		if assetEntryExists(entry, before) && !assetEntryExists(entry, after) {
+			plan.PubspecRemovals = append(plan.PubspecRemovals, entry)
+		}
+	}
+
+	return nil
+}
+
+// assetEntryExists reports whether a pubspec asset entry matches a file or a non-empty directory
+func assetEntryExists(entry string, files []string) bool {
+	if strings.HasSuffix(entry, "/") {
+		// Asset type directories are kept even when empty
+		if isAssetTypeDir(strings.TrimSuffix(strings.TrimPrefix(entry, AssetDirName+"/"), "/")) {
+			return true
+		}
+		for _, file := range files {
+			if strings.HasPrefix(file, entry) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, file := range files {
+		if file == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveMigrationPlan writes a migration plan so it can be reviewed and applied later
+func SaveMigrationPlan(projectPath string, plan *MigrationPlan) error {
+	return writeStateFile(GetMigrationPlanPath(projectPath), plan)
+}
+
+// LoadMigrationPlan reads the pending migration plan
+func LoadMigrationPlan(projectPath string) (*MigrationPlan, error) {
+	var plan MigrationPlan
+	if err := readStateFile(GetMigrationPlanPath(projectPath), &plan); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no migration plan found, create one with 'fdawg asset migrate --plan'")
+		}
+		return nil, fmt.Errorf("failed to read migration plan: %v", err)
+	}
+	return &plan, nil
+}
+
+// ApplyMigrationPlan moves the files in the plan, updates pubspec.yaml and lib/
+// references and regenerates the Dart asset file. Moved files are also copied
+// to the asset backup directory, and an undo log is recorded so the migration
+// can be reverted with UndoMigration.
+func ApplyMigrationPlan(projectPath string, plan *MigrationPlan) (*MigrationResult, error) {
+	// Make sure the plan still matches the files on disk
+	for _, move := range plan.Moves {
+		if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(move.From))); err != nil {
+			return nil, fmt.Errorf("plan is out of date: %s no longer exists", move.From)
+		}
+		if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(move.To))); err == nil {
+			return nil, fmt.Errorf("plan is out of date: %s already exists", move.To)
+		}
+	}
+
+	// Record the original pubspec.yaml and Dart files before changing anything
+	pubspecPath := filepath.Join(projectPath, "pubspec.yaml")
+	pubspecData, err := os.ReadFile(pubspecPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pubspec.yaml: %v", err)
+	}
+
+	undoLog := &MigrationUndoLog{
+		AppliedAt: time.Now(),
+		Moves:     plan.Moves,
+		Pubspec:   string(pubspecData),
+		DartFiles: make(map[string]string),
+	}
+
+	// lib/ may have changed since the plan was written, so look up the references again
+	replacements := make(map[string]string)
+	for _, move := range plan.Moves {
+		replacements[move.From] = move.To
+	}
+	pendingReferences, err := updateAssetReferences(projectPath, replacements, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, reference := range pendingReferences {
+		if _, ok := undoLog.DartFiles[reference.File]; ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(reference.File)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", reference.File, err)
+		}
+		undoLog.DartFiles[reference.File] = string(data)
+	}
+
+	// Back up every file, keeping its path relative to the asset directory
+	assetDir := GetAssetDir(projectPath)
+	backupDir := GetAssetBackupDir(projectPath)
+	for _, move := range plan.Moves {
+		fromPath := filepath.Join(projectPath, filepath.FromSlash(move.From))
+		relPath, err := filepath.Rel(assetDir, fromPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get relative path: %v", err)
+		}
+
+		backupPath := filepath.Join(backupDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create backup directory: %v", err)
+		}
+		if err := copyFile(fromPath, backupPath); err != nil {
+			return nil, fmt.Errorf("failed to backup file %s: %v", move.From, err)
+		}
+	}
+
+	// Write the undo log before moving so a failed migration can still be reverted
+	if err := writeStateFile(GetMigrationUndoPath(projectPath), undoLog); err != nil {
+		return nil, fmt.Errorf("failed to write undo log: %v", err)
+	}
+
+	for _, move := range plan.Moves {
+		fromPath := filepath.Join(projectPath, filepath.FromSlash(move.From))
+		toPath := filepath.Join(projectPath, filepath.FromSlash(move.To))
+
+		createdDirs, err := mkdirAllTracked(filepath.Dir(toPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create directory for %s: %v", move.To, err)
+		}
+		for _, dir := range createdDirs {
+			relDir, _ := filepath.Rel(projectPath, dir)
+			undoLog.CreatedDirs = append(undoLog.CreatedDirs, filepath.ToSlash(relDir))
+		}
+
+		if err := os.Rename(fromPath, toPath); err != nil {
+			return nil, fmt.Errorf("failed to move %s to %s: %v", move.From, move.To, err)
+		}
+
+		// Keep the undo log current as files move
+		if err := writeStateFile(GetMigrationUndoPath(projectPath), undoLog); err != nil {
+			return nil, fmt.Errorf("failed to write undo log: %v", err)
+		}
+	}
+
+	// Remove empty directories
+	removeEmptyDirs(assetDir)
+
+	// Update pubspec.yaml
+	for _, entry := range plan.PubspecAdditions {
+		if err := updatePubspecWithAssetPath(projectPath, entry); err != nil {
+			return nil, fmt.Errorf("failed to update pubspec.yaml: %v", err)
+		}
+	}
+	if err := removePubspecAssetEntries(projectPath, plan.PubspecRemovals); err != nil {
+		return nil, fmt.Errorf("failed to update pubspec.yaml: %v", err)
+	}
+
+	// Rewrite references in lib/
+	references, err := updateAssetReferences(projectPath, replacements, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the Dart asset file
+	if err := GenerateDartAssetFile(projectPath); err != nil {
+		return nil, fmt.Errorf("failed to generate Dart asset file: %v", err)
+	}
+
+	// Record what the migration left, so undo can tell if the files were edited since
+	undoLog.AppliedHashes = make(map[string]string)
+	for _, file := range undoLog.restoredFiles() {
+		hash, err := hashFile(filepath.Join(projectPath, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %v", file, err)
+		}
+		undoLog.AppliedHashes[file] = hash
+	}
+	if err := writeStateFile(GetMigrationUndoPath(projectPath), undoLog); err != nil {
+		return nil, fmt.Errorf("failed to write undo log: %v", err)
+	}
+
+	// The plan has been used up
+	os.Remove(GetMigrationPlanPath(projectPath))
+
+	referencesUpdated := 0
+	for _, reference := range references {
+		referencesUpdated += reference.Count
+	}
+
+	return &MigrationResult{
+		FilesProcessed:    len(plan.Moves),
+		NoFilesToMigrate:  false,
+		ReferencesUpdated: referencesUpdated,
+	}, nil
+}
+
+// restoredFiles returns pubspec.yaml and the Dart files undo restores, relative to the project
+func (l *MigrationUndoLog) restoredFiles() []string {
+	files := make([]string, 0, len(l.DartFiles))
+	for file := range l.DartFiles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return append([]string{"pubspec.yaml"}, files...)
+}
+
+// UndoMigration restores the asset layout, pubspec.yaml and Dart files from before the last applied migration
+func UndoMigration(projectPath string) (*MigrationResult, error) {
+	var undoLog MigrationUndoLog
+	if err := readStateFile(GetMigrationUndoPath(projectPath), &undoLog); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no applied migration to undo")
+		}
+		return nil, fmt.Errorf("failed to read undo log: %v", err)
+	}
+
+	// pubspec.yaml and the Dart files are restored whole, so refuse if they were edited
+	// since the migration finished rather than lose those edits
+	for _, file := range undoLog.restoredFiles() {
+		expected, ok := undoLog.AppliedHashes[file]
+		if !ok {
+			continue
+		}
+		hash, err := hashFile(filepath.Join(projectPath, filepath.FromSlash(file)))
+		if err != nil || hash != expected {
+			return nil, fmt.Errorf("%s has changed since the migration was applied; undo would overwrite those edits, so revert them first or undo the migration by hand", file)
+		}
+	}
+
+	// Move files back, skipping any that never moved because the migration was interrupted
+	restored := 0
+	for i := len(undoLog.Moves) - 1; i >= 0; i-- {
+		move := undoLog.Moves[i]
+		fromPath := filepath.Join(projectPath, filepath.FromSlash(move.From))
+		toPath := filepath.Join(projectPath, filepath.FromSlash(move.To))
+
+		if _, err := os.Stat(toPath); os.IsNotExist(err) {
+			continue
+		}
+		if _, err := os.Stat(fromPath); err == nil {
+			return nil, fmt.Errorf("cannot restore %s: file already exists", move.From)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(fromPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory for %s: %v", move.From, err)
+		}
+		if err := os.Rename(toPath, fromPath); err != nil {
+			return nil, fmt.Errorf("failed to move %s back to %s: %v", move.To, move.From, err)
+		}
+		restored++
+	}
+
+	// Remove directories the migration created, deepest first, if they are empty again
+	createdDirs := append([]string(nil), undoLog.CreatedDirs...)
+	sort.Slice(createdDirs, func(i, j int) bool {
+		return len(createdDirs[i]) > len(createdDirs[j])
+	})
+	for _, dir := range createdDirs {
+		dirPath := filepath.Join(projectPath, filepath.FromSlash(dir))
+		if entries, err := os.ReadDir(dirPath); err == nil && len(entries) == 0 {
+			os.Remove(dirPath)
+		}
+	}
+
+	// Restore pubspec.yaml and the Dart files exactly as they were
+	if err := os.WriteFile(filepath.Join(projectPath, "pubspec.yaml"), []byte(undoLog.Pubspec), 0644); err != nil {
+		return nil, fmt.Errorf("failed to restore pubspec.yaml: %v", err)
+	}
+	for file, content := range undoLog.DartFiles {
+		if err := os.WriteFile(filepath.Join(projectPath, filepath.FromSlash(file)), []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %v", file, err)
+		}
+	}
+
+	if err := GenerateDartAssetFile(projectPath); err != nil {
+		return nil, fmt.Errorf("failed to generate Dart asset file: %v", err)
+	}
+
+	if err := os.Remove(GetMigrationUndoPath(projectPath)); err != nil {
+		return nil, fmt.Errorf("failed to remove undo log: %v", err)
+	}
+
+	return &MigrationResult{
+		FilesProcessed:   restored,
+		NoFilesToMigrate: restored == 0,
+	}, nil
+}
+
+// mkdirAllTracked creates a directory and its parents, returning the directories that were created
+func mkdirAllTracked(dir string) ([]string, error) {
+	var missing []string
+	for current := dir; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(current); err == nil {
+			break
+		}
+		missing = append(missing, current)
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
+// writeStateFile writes a value as indented JSON to a file under the state directory
+func writeStateFile(path string, value interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// readStateFile reads a JSON file written by writeStateFile
+func readStateFile(path string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, value)
+}
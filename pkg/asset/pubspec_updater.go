@@ -7,8 +7,46 @@ import (
 	"strings"
 )
 
-// updatePubspecWithAsset updates the pubspec.yaml file with the asset entry
+// updatePubspecWithAsset updates the pubspec.yaml file with the asset entries
+// for an asset type. Flutter does not include subdirectories of a listed asset
+// directory, so an entry is written for the type directory and for every
+// directory nested below it.
 func updatePubspecWithAsset(projectPath string, assetType AssetType) error {
+	assetTypeDir := filepath.Join(GetAssetDir(projectPath), string(assetType))
+
+	assetPaths := []string{fmt.Sprintf("assets/%s/", assetType)}
+	if _, err := os.Stat(assetTypeDir); err == nil {
+		err := filepath.WalkDir(assetTypeDir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() || path == assetTypeDir {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(assetTypeDir, path)
+			if err != nil {
+				return err
+			}
+			assetPaths = append(assetPaths, fmt.Sprintf("assets/%s/%s/", assetType, filepath.ToSlash(relPath)))
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to walk asset directory: %v", err)
+		}
+	}
+
+	for _, assetPath := range assetPaths {
+		if err := updatePubspecWithAssetPath(projectPath, assetPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updatePubspecWithAssetPath adds a single asset path entry to the pubspec.yaml file if it is missing
+func updatePubspecWithAssetPath(projectPath, assetPath string) error {
 	// Read the pubspec.yaml file
 	pubspecPath := filepath.Join(projectPath, "pubspec.yaml")
 	pubspecData, err := os.ReadFile(pubspecPath)
@@ -16,9 +54,6 @@ func updatePubspecWithAsset(projectPath string, assetType AssetType) error {
 		return fmt.Errorf("failed to read pubspec.yaml: %v", err)
 	}
 
-	// Create the asset path
-	assetPath := fmt.Sprintf("assets/%s/", assetType)
-
 	// Parse the file line by line to find the correct flutter section
 	pubspecContent := string(pubspecData)
 	lines := strings.Split(pubspecContent, "\n")
@@ -236,3 +271,92 @@ func addAssetEntry(pubspecPath, pubspecContent string, assetsLineIndex int, asse
 	updatedContent := strings.Join(updatedLines, "\n")
 	return os.WriteFile(pubspecPath, []byte(updatedContent), 0644)
 }
+
+// removePubspecAssetEntries removes the given entries from the flutter assets section of the pubspec.yaml file
+func removePubspecAssetEntries(projectPath string, assetPaths []string) error {
+	if len(assetPaths) == 0 {
+		return nil
+	}
+
+	pubspecPath := filepath.Join(projectPath, "pubspec.yaml")
+	pubspecData, err := os.ReadFile(pubspecPath)
+	if err != nil {
+		return fmt.Errorf("failed to read pubspec.yaml: %v", err)
+	}
+
+	toRemove := make(map[string]bool)
+	for _, assetPath := range assetPaths {
+		toRemove[assetPath] = true
+	}
+
+	lines := strings.Split(string(pubspecData), "\n")
+	updatedLines := make([]string, 0, len(lines))
+
+	inFlutterSection := false
+	inAssetsSection := false
+	for _, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		isTopLevel := len(line) > 0 && !strings.HasPrefix(line, " ") && !strings.HasPrefix(trimmedLine, "#")
+
+		if isTopLevel {
+			inFlutterSection = trimmedLine == "flutter:"
+			inAssetsSection = false
+		} else if inFlutterSection && strings.HasSuffix(trimmedLine, ":") && !strings.HasPrefix(trimmedLine, "-") {
+			inAssetsSection = trimmedLine == "assets:"
+		}
+
+		if inAssetsSection && strings.HasPrefix(trimmedLine, "- ") {
+			entry := strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmedLine, "- ")), `"'`)
+			if toRemove[entry] {
+				continue
+			}
+		}
+
+		updatedLines = append(updatedLines, line)
+	}
+
+	return os.WriteFile(pubspecPath, []byte(strings.Join(updatedLines, "\n")), 0644)
+}
+
+// pubspecAssetEntries returns the entries of the flutter assets section of the pubspec.yaml file
+func pubspecAssetEntries(projectPath string) ([]string, error) {
+	pubspecData, err := os.ReadFile(filepath.Join(projectPath, "pubspec.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pubspec.yaml: %v", err)
+	}
+
+	var entries []string
+	inFlutterSection := false
+	inAssetsSection := false
+	for _, line := range strings.Split(string(pubspecData), "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		isTopLevel := len(line) > 0 && !strings.HasPrefix(line, " ") && !strings.HasPrefix(trimmedLine, "#")
+
+		if isTopLevel {
+			inFlutterSection = trimmedLine == "flutter:"
+			inAssetsSection = false
+		} else if inFlutterSection && strings.HasSuffix(trimmedLine, ":") && !strings.HasPrefix(trimmedLine, "-") {
+			inAssetsSection = trimmedLine == "assets:"
+		}
+
+		if inAssetsSection && strings.HasPrefix(trimmedLine, "- ") {
+			entries = append(entries, strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmedLine, "- ")), `"'`))
+		}
+	}
+
+	return entries, nil
+}
+
+// RemoveMissingPubspecAssetDirs removes the pubspec.yaml entries of the given asset directories
+// that no longer exist. Directories are relative to the project, e.g. assets/images/extra.
+func RemoveMissingPubspecAssetDirs(projectPath string, dirs []string) error {
+	var missing []string
+	for _, dir := range dirs {
+		dir = strings.TrimSuffix(dir, "/")
+		if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(dir))); os.IsNotExist(err) {
+			missing = append(missing, dir+"/")
+		}
+	}
+
+	return removePubspecAssetEntries(projectPath, missing)
+}
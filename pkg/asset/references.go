@@ -0,0 +1,191 @@
+package asset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AssetReference describes quoted references to an asset path in a Dart file
+type AssetReference struct {
+	// File is the Dart file path relative to the project
+	File string `json:"file"`
+	// From is the referenced asset path
+	From string `json:"from"`
+	// To is the asset path the reference is rewritten to
+	To string `json:"to"`
+	// Count is the number of occurrences in the file
+	Count int `json:"count"`
+}
+
+// updateAssetReferences finds quoted asset paths in the Dart files under lib/ and,
+// when write is set, replaces them according to replacements (old path to new path).
+// The generated asset file is skipped since it is regenerated from the asset directory.
+func updateAssetReferences(projectPath string, replacements map[string]string, write bool) ([]AssetReference, error) {
+	if len(replacements) == 0 {
+		return nil, nil
+	}
+
+	libDir := filepath.Join(projectPath, "lib")
+	generatedFile := filepath.Join(libDir, "config", "asset.dart")
+
+	if _, err := os.Stat(libDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	// Apply replacements in a stable order
+	oldPaths := make([]string, 0, len(replacements))
+	for oldPath := range replacements {
+		oldPaths = append(oldPaths, oldPath)
+	}
+	sort.Strings(oldPaths)
+
+	var references []AssetReference
+
+	err := filepath.Walk(libDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".dart" || path == generatedFile {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", path, err)
+		}
+
+		relPath, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		content := string(data)
+		changed := false
+		for _, oldPath := range oldPaths {
+			newPath := replacements[oldPath]
+			count := 0
+			for _, quote := range []string{"'", `"`} {
+				quoted := quote + oldPath + quote
+				if n := strings.Count(content, quoted); n > 0 {
+					content = strings.ReplaceAll(content, quoted, quote+newPath+quote)
+					count += n
+				}
+			}
+
+			if count > 0 {
+				references = append(references, AssetReference{
+					File:  relPath,
+					From:  oldPath,
+					To:    newPath,
+					Count: count,
+				})
+				changed = true
+			}
+		}
+
+		if !changed || !write {
+			return nil
+		}
+
+		if err := os.WriteFile(path, []byte(content), info.Mode()); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update references: %v", err)
+	}
+
+	return references, nil
+}
+
+// updateAccessorReferences replaces generated asset accessors such as
+// Asset.images.logoCopy in the Dart files under lib/ according to replacements
+// (old accessor to new accessor). All accessors are matched in a single pass, so
+// swapped names aren't rewritten twice.
+func updateAccessorReferences(projectPath string, replacements map[string]string) ([]AssetReference, error) {
+	if len(replacements) == 0 {
+		return nil, nil
+	}
+
+	libDir := filepath.Join(projectPath, "lib")
+	generatedFile := filepath.Join(libDir, "config", "asset.dart")
+
+	if _, err := os.Stat(libDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	// Longer accessors first, so a nested accessor wins over its parent
+	oldAccessors := make([]string, 0, len(replacements))
+	for oldAccessor := range replacements {
+		oldAccessors = append(oldAccessors, oldAccessor)
+	}
+	sort.Slice(oldAccessors, func(i, j int) bool {
+		if len(oldAccessors[i]) != len(oldAccessors[j]) {
+			return len(oldAccessors[i]) > len(oldAccessors[j])
+		}
+		return oldAccessors[i] < oldAccessors[j]
+	})
+	patterns := make([]string, len(oldAccessors))
+	for i, oldAccessor := range oldAccessors {
+		patterns[i] = regexp.QuoteMeta(oldAccessor)
+	}
+	accessorRegex := regexp.MustCompile(`\b(?:` + strings.Join(patterns, "|") + `)\b`)
+
+	var references []AssetReference
+
+	err := filepath.Walk(libDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".dart" || path == generatedFile {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", path, err)
+		}
+
+		relPath, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		counts := make(map[string]int)
+		content := accessorRegex.ReplaceAllStringFunc(string(data), func(match string) string {
+			counts[match]++
+			return replacements[match]
+		})
+		if len(counts) == 0 {
+			return nil
+		}
+
+		for _, oldAccessor := range oldAccessors {
+			if counts[oldAccessor] > 0 {
+				references = append(references, AssetReference{
+					File:  relPath,
+					From:  oldAccessor,
+					To:    replacements[oldAccessor],
+					Count: counts[oldAccessor],
+				})
+			}
+		}
+
+		if err := os.WriteFile(path, []byte(content), info.Mode()); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update references: %v", err)
+	}
+
+	return references, nil
+}
@@ -0,0 +1,228 @@
+package asset
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultWatchInterval is the default polling interval used by WatchAssets
+	DefaultWatchInterval = time.Second
+	// DefaultWatchDebounce is the default quiet period before WatchAssets regenerates
+	DefaultWatchDebounce = 500 * time.Millisecond
+)
+
+// WatchOptions configures WatchAssets
+type WatchOptions struct {
+	// Interval is how often the asset directory is polled for changes
+	Interval time.Duration
+	// Debounce is how long the asset directory must stay unchanged before regenerating
+	Debounce time.Duration
+	// OnChange is called after every regeneration
+	OnChange func(result *WatchResult)
+}
+
+// WatchResult describes a regeneration triggered by WatchAssets
+type WatchResult struct {
+	// Changed lists the asset paths (relative to the asset directory) that were added, modified or removed
+	Changed []string
+	// Err is set if scanning the asset directory, or regenerating the Dart asset file or
+	// pubspec entries, failed. Watching goes on either way.
+	Err error
+}
+
+// fileState is the part of a file's metadata used to detect changes
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// WatchAssets polls the asset directory until ctx is cancelled. Whenever files
+// change, it waits for the debounce period and then refreshes the pubspec.yaml
+// asset entries and regenerates the Dart asset file.
+func WatchAssets(ctx context.Context, projectPath string, opts WatchOptions) error {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+	if opts.Debounce < 0 {
+		opts.Debounce = 0
+	}
+
+	previous, err := snapshotAssetDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to scan asset directory: %v", err)
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	pending := make(map[string]bool)
+	var lastChange time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// A failed scan, e.g. of a directory removed while it was walked, is reported and
+		// retried on the next tick rather than stopping the watcher
+		current, err := snapshotAssetDir(projectPath)
+		if err != nil {
+			if opts.OnChange != nil {
+				opts.OnChange(&WatchResult{Err: fmt.Errorf("failed to scan asset directory: %v", err)})
+			}
+			continue
+		}
+
+		if changed := diffSnapshots(previous, current); len(changed) > 0 {
+			for _, path := range changed {
+				pending[path] = true
+			}
+			lastChange = time.Now()
+			previous = current
+		}
+
+		if len(pending) == 0 || time.Since(lastChange) < opts.Debounce {
+			continue
+		}
+
+		result := &WatchResult{}
+		for path := range pending {
+			result.Changed = append(result.Changed, path)
+		}
+		sort.Strings(result.Changed)
+		pending = make(map[string]bool)
+
+		result.Err = RefreshAssets(projectPath)
+
+		if opts.OnChange != nil {
+			opts.OnChange(result)
+		}
+	}
+}
+
+// RefreshAssets adds any missing pubspec.yaml asset entries, removes those of deleted
+// asset directories and regenerates the Dart asset file
+func RefreshAssets(projectPath string) error {
+	if err := SyncPubspecAssets(projectPath); err != nil {
+		return err
+	}
+
+	if err := GenerateDartAssetFile(projectPath); err != nil {
+		return fmt.Errorf("failed to generate Dart asset file: %v", err)
+	}
+
+	return nil
+}
+
+// SyncPubspecAssets adds pubspec.yaml entries for every existing asset type directory and its
+// subdirectories, and removes the entries of asset directories that no longer exist
+func SyncPubspecAssets(projectPath string) error {
+	assetDir := GetAssetDir(projectPath)
+
+	for _, assetType := range []AssetType{
+		ImageAsset,
+		AnimationAsset,
+		AudioAsset,
+		VideoAsset,
+		JSONAsset,
+		SVGAsset,
+		MiscAsset,
+	} {
+		if _, err := os.Stat(filepath.Join(assetDir, string(assetType))); os.IsNotExist(err) {
+			continue
+		}
+
+		if err := updatePubspecWithAsset(projectPath, assetType); err != nil {
+			return fmt.Errorf("failed to update pubspec.yaml for %s: %v", assetType, err)
+		}
+	}
+
+	// flutter build fails on asset entries whose directory is gone
+	entries, err := pubspecAssetEntries(projectPath)
+	if err != nil {
+		return err
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry, AssetDirName+"/") && strings.HasSuffix(entry, "/") {
+			dirs = append(dirs, entry)
+		}
+	}
+	if err := RemoveMissingPubspecAssetDirs(projectPath, dirs); err != nil {
+		return fmt.Errorf("failed to update pubspec.yaml: %v", err)
+	}
+
+	return nil
+}
+
+// snapshotAssetDir records the size and modification time of every file in the asset directory.
+// The translations directory is skipped since it is managed by the localization commands.
+func snapshotAssetDir(projectPath string) (map[string]fileState, error) {
+	assetDir := GetAssetDir(projectPath)
+	snapshot := make(map[string]fileState)
+
+	if _, err := os.Stat(assetDir); os.IsNotExist(err) {
+		return snapshot, nil
+	}
+
+	err := filepath.Walk(assetDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Files can disappear between listing and stat while they are being edited
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		relPath, err := filepath.Rel(assetDir, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if relPath == "translations" {
+				return filepath.SkipDir
+			}
+			// Track directories too so empty folders still trigger a pubspec refresh
+			if path != assetDir {
+				snapshot[filepath.ToSlash(relPath)+"/"] = fileState{}
+			}
+			return nil
+		}
+
+		snapshot[filepath.ToSlash(relPath)] = fileState{
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+		return nil
+	})
+
+	return snapshot, err
+}
+
+// diffSnapshots returns the paths that differ between two snapshots
+func diffSnapshots(previous, current map[string]fileState) []string {
+	var changed []string
+
+	for path, state := range current {
+		if prev, ok := previous[path]; !ok || prev.size != state.size || !prev.modTime.Equal(state.modTime) {
+			changed = append(changed, path)
+		}
+	}
+
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	return changed
+}
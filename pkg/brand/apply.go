@@ -0,0 +1,478 @@
+package brand
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jerinji2016/fdawg/pkg/asset"
+	"github.com/Jerinji2016/fdawg/pkg/environment"
+	"github.com/Jerinji2016/fdawg/pkg/identity"
+)
+
+// Change kinds, besides the identity fields for names, bundle IDs and Android sources
+const (
+	KindIcon        = "icon"
+	KindSplash      = "splash"
+	KindEnvironment = "environment"
+	KindAsset       = "asset"
+)
+
+// Change is a single difference between the working tree and a brand
+type Change struct {
+	Kind string `json:"kind"`
+	// Target is the platform, file or setting that changes
+	Target string `json:"target"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// Plan describes what applying a brand changes
+type Plan struct {
+	Brand string `json:"brand"`
+	// Previous is the brand that is active now, if any
+	Previous string   `json:"previous,omitempty"`
+	Changes  []Change `json:"changes"`
+	Warnings []string `json:"warnings"`
+
+	brand     *Brand
+	identity  *identity.Identity
+	assets    []assetChange
+	nextState *State
+}
+
+// assetChange is an asset file written or removed when switching brands
+type assetChange struct {
+	// target is the asset's path relative to the project, with forward slashes
+	target string
+	// source is the file to copy to target, or empty to remove target
+	source string
+	// saveOriginal keeps the project's own version before it is overridden
+	saveOriginal bool
+	// dropOriginal removes the kept original once it has been put back
+	dropOriginal bool
+}
+
+// PlanBrand works out what applying a brand would change, without writing anything
+func PlanBrand(projectPath, name string) (*Plan, error) {
+	brand, err := LoadBrand(projectPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := LoadState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Brand:    name,
+		Changes:  []Change{},
+		Warnings: []string{},
+		brand:    brand,
+		identity: brand.Identity(),
+		nextState: &State{
+			Brand:       name,
+			Environment: brand.Environment,
+			Overridden:  []string{},
+			Added:       []string{},
+		},
+	}
+	if state != nil {
+		plan.Previous = state.Brand
+	}
+
+	// Names and bundle IDs
+	if plan.identity != nil {
+		identityPlan, err := identity.PlanIdentity(projectPath, plan.identity)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range identityPlan.Changes {
+			plan.Changes = append(plan.Changes, Change{Kind: change.Field, Target: change.Platform, From: change.From, To: change.To})
+		}
+		plan.Warnings = append(plan.Warnings, identityPlan.Warnings...)
+	}
+
+	// Launcher icon and splash screen
+	if brand.Icon != "" {
+		current, err := readYAMLSection(filepath.Join(projectPath, LauncherIconsFileName), "flutter_launcher_icons")
+		if err != nil {
+			return nil, err
+		}
+		if want := brand.brandPath(brand.Icon); current["image_path"] != want {
+			plan.Changes = append(plan.Changes, Change{Kind: KindIcon, Target: "image_path", From: current["image_path"], To: want})
+		}
+	}
+
+	splash := brand.splashValues()
+	if len(splash) > 0 {
+		current, err := readYAMLSection(filepath.Join(projectPath, NativeSplashFileName), "flutter_native_splash")
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(splash) {
+			if current[key] != splash[key] {
+				plan.Changes = append(plan.Changes, Change{Kind: KindSplash, Target: key, From: current[key], To: splash[key]})
+			}
+		}
+	}
+
+	// Environment used for builds
+	if brand.Environment != "" {
+		if _, err := environment.GetEnvFile(projectPath, brand.Environment); err != nil {
+			return nil, fmt.Errorf("brand %s: %v", name, err)
+		}
+	}
+	previousEnvironment := ""
+	if state != nil {
+		previousEnvironment = state.Environment
+	}
+	if brand.Environment != previousEnvironment {
+		plan.Changes = append(plan.Changes, Change{Kind: KindEnvironment, Target: "environment", From: previousEnvironment, To: brand.Environment})
+	}
+
+	// Asset overrides
+	if err := plan.planAssets(projectPath, state); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// ApplyBrand switches the working tree to a brand. Assets, the icon and splash
+// settings are written first and the names and bundle IDs last; if any step
+// fails, everything written so far is put back.
+func ApplyBrand(projectPath, name string) (*Plan, error) {
+	plan, err := PlanBrand(projectPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := snapshotFiles(plan.touchedFiles(projectPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up files: %v", err)
+	}
+
+	if err := plan.apply(projectPath); err != nil {
+		restoreSnapshots(snapshots)
+		return nil, fmt.Errorf("%v; all changes were rolled back", err)
+	}
+
+	return plan, nil
+}
+
+// apply writes the planned changes, stopping at the first error
+func (p *Plan) apply(projectPath string) error {
+	if len(p.assets) > 0 {
+		if err := p.applyAssets(projectPath); err != nil {
+			return fmt.Errorf("failed to apply asset overrides: %v", err)
+		}
+		if err := asset.RemoveMissingPubspecAssetDirs(projectPath, p.removedAssetDirs()); err != nil {
+			return fmt.Errorf("failed to update pubspec.yaml: %v", err)
+		}
+		if err := asset.RefreshAssets(projectPath); err != nil {
+			return err
+		}
+	}
+
+	if p.brand.Icon != "" {
+		launcherPath := filepath.Join(projectPath, LauncherIconsFileName)
+		if _, err := os.Stat(launcherPath); os.IsNotExist(err) {
+			// flutter_launcher_icons needs at least one platform enabled
+			if err := os.WriteFile(launcherPath, []byte("flutter_launcher_icons:\n  android: true\n  ios: true\n"), 0644); err != nil {
+				return fmt.Errorf("failed to create %s: %v", LauncherIconsFileName, err)
+			}
+		}
+		if err := writeYAMLSection(launcherPath, "flutter_launcher_icons", map[string]string{"image_path": p.brand.brandPath(p.brand.Icon)}); err != nil {
+			return fmt.Errorf("failed to update %s: %v", LauncherIconsFileName, err)
+		}
+	}
+
+	if splash := p.brand.splashValues(); len(splash) > 0 {
+		if err := writeYAMLSection(filepath.Join(projectPath, NativeSplashFileName), "flutter_native_splash", splash); err != nil {
+			return fmt.Errorf("failed to update %s: %v", NativeSplashFileName, err)
+		}
+	}
+
+	p.nextState.AppliedAt = time.Now()
+	if err := saveState(projectPath, p.nextState); err != nil {
+		return fmt.Errorf("failed to save brand state: %v", err)
+	}
+
+	// Names and bundle IDs roll themselves back if they fail
+	if p.identity != nil {
+		if _, err := identity.ApplyIdentity(projectPath, p.identity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planAssets works out which assets change: the previous brand's overrides are
+// put back, then this brand's files are copied over assets/
+func (p *Plan) planAssets(projectPath string, state *State) error {
+	originalsDir := GetOriginalsDir(projectPath)
+	wanted := make(map[string]assetChange)
+
+	previouslyOverridden := make(map[string]bool)
+	previouslyAdded := make(map[string]bool)
+	if state != nil {
+		for _, target := range state.Overridden {
+			previouslyOverridden[target] = true
+			wanted[target] = assetChange{target: target, source: filepath.Join(originalsDir, filepath.FromSlash(target)), dropOriginal: true}
+		}
+		for _, target := range state.Added {
+			previouslyAdded[target] = true
+			wanted[target] = assetChange{target: target}
+		}
+	}
+
+	assetsDir := p.brand.AssetsDir(projectPath)
+	err := filepath.Walk(assetsDir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(assetsDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.ToSlash(filepath.Join(asset.AssetDirName, relPath))
+		change := assetChange{target: target, source: path}
+
+		switch {
+		case previouslyOverridden[target]:
+			p.nextState.Overridden = append(p.nextState.Overridden, target)
+		case previouslyAdded[target]:
+			p.nextState.Added = append(p.nextState.Added, target)
+		default:
+			if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(target))); err == nil {
+				change.saveOriginal = true
+				p.nextState.Overridden = append(p.nextState.Overridden, target)
+			} else {
+				p.nextState.Added = append(p.nextState.Added, target)
+			}
+		}
+
+		wanted[target] = change
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read asset overrides: %v", err)
+	}
+
+	targets := make([]string, 0, len(wanted))
+	for target := range wanted {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	for _, target := range targets {
+		change := wanted[target]
+		current, currentErr := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(target)))
+
+		var to string
+		if change.source == "" {
+			if os.IsNotExist(currentErr) {
+				continue
+			}
+			to = "removed"
+		} else {
+			content, err := os.ReadFile(change.source)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %v", change.source, err)
+			}
+			if currentErr == nil && bytes.Equal(current, content) && !change.dropOriginal {
+				// Already in place, but keep the original if it is about to be needed
+				if change.saveOriginal {
+					p.assets = append(p.assets, change)
+				}
+				continue
+			}
+			to = "original"
+			if !change.dropOriginal {
+				to = p.brand.brandPath(filepath.ToSlash(filepath.Join(p.brand.assetsRelDir(), strings.TrimPrefix(target, asset.AssetDirName+"/"))))
+			}
+		}
+
+		p.assets = append(p.assets, change)
+		p.Changes = append(p.Changes, Change{Kind: KindAsset, Target: target, To: to})
+	}
+
+	return nil
+}
+
+// applyAssets saves originals, then writes or removes each changed asset
+func (p *Plan) applyAssets(projectPath string) error {
+	originalsDir := GetOriginalsDir(projectPath)
+
+	for _, change := range p.assets {
+		if !change.saveOriginal {
+			continue
+		}
+		if err := copyFile(filepath.Join(projectPath, filepath.FromSlash(change.target)), filepath.Join(originalsDir, filepath.FromSlash(change.target))); err != nil {
+			return fmt.Errorf("failed to keep the original %s: %v", change.target, err)
+		}
+	}
+
+	for _, change := range p.assets {
+		targetPath := filepath.Join(projectPath, filepath.FromSlash(change.target))
+
+		if change.source == "" {
+			if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %v", change.target, err)
+			}
+			removeEmptyParents(asset.GetAssetDir(projectPath), filepath.Dir(targetPath))
+			continue
+		}
+
+		if err := copyFile(change.source, targetPath); err != nil {
+			return fmt.Errorf("failed to write %s: %v", change.target, err)
+		}
+		if change.dropOriginal {
+			os.Remove(change.source)
+			removeEmptyParents(originalsDir, filepath.Dir(change.source))
+		}
+	}
+
+	return nil
+}
+
+// removedAssetDirs returns the directories of the assets the plan removes, and their parents below assets/
+func (p *Plan) removedAssetDirs() []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, change := range p.assets {
+		if change.source != "" {
+			continue
+		}
+		for dir := path.Dir(change.target); dir != asset.AssetDirName && dir != "." && !seen[dir]; dir = path.Dir(dir) {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// touchedFiles returns every file applying the plan may write, as absolute paths
+func (p *Plan) touchedFiles(projectPath string) []string {
+	files := []string{
+		filepath.Join(projectPath, LauncherIconsFileName),
+		filepath.Join(projectPath, NativeSplashFileName),
+		filepath.Join(projectPath, "pubspec.yaml"),
+		filepath.Join(projectPath, "lib", "config", "asset.dart"),
+		GetStatePath(projectPath),
+	}
+
+	originalsDir := GetOriginalsDir(projectPath)
+	for _, change := range p.assets {
+		files = append(files,
+			filepath.Join(projectPath, filepath.FromSlash(change.target)),
+			filepath.Join(originalsDir, filepath.FromSlash(change.target)))
+	}
+
+	return files
+}
+
+// splashValues returns the flutter_native_splash settings the brand sets
+func (b *Brand) splashValues() map[string]string {
+	values := make(map[string]string)
+	if b.Splash.Color != "" {
+		values["color"] = b.Splash.Color
+	}
+	if b.Splash.ColorDark != "" {
+		values["color_dark"] = b.Splash.ColorDark
+	}
+	if b.Splash.Image != "" {
+		values["image"] = b.brandPath(b.Splash.Image)
+	}
+	return values
+}
+
+// assetsRelDir returns the brand's asset override directory relative to the brand directory
+func (b *Brand) assetsRelDir() string {
+	if b.Assets == "" {
+		return DefaultAssetsDirName
+	}
+	return b.Assets
+}
+
+// fileSnapshot is the content of a file before a brand was applied
+type fileSnapshot struct {
+	path    string
+	data    []byte
+	existed bool
+}
+
+// snapshotFiles records the content of files so they can be restored
+func snapshotFiles(paths []string) ([]fileSnapshot, error) {
+	var snapshots []fileSnapshot
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			snapshots = append(snapshots, fileSnapshot{path: path})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, fileSnapshot{path: path, data: data, existed: true})
+	}
+	return snapshots, nil
+}
+
+// restoreSnapshots writes back recorded files and removes the ones that didn't exist
+func restoreSnapshots(snapshots []fileSnapshot) {
+	for _, snapshot := range snapshots {
+		if !snapshot.existed {
+			os.Remove(snapshot.path)
+			continue
+		}
+		os.MkdirAll(filepath.Dir(snapshot.path), 0755)
+		os.WriteFile(snapshot.path, snapshot.data, 0644)
+	}
+}
+
+// copyFile copies a file, creating the destination's directory
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
+
+// removeEmptyParents removes dir and its parents while they are empty, stopping at root
+func removeEmptyParents(root, dir string) {
+	for current := dir; current != root && strings.HasPrefix(current, root); current = filepath.Dir(current) {
+		if entries, err := os.ReadDir(current); err != nil || len(entries) > 0 || os.Remove(current) != nil {
+			return
+		}
+	}
+}
+
+// sortedKeys returns a map's keys in order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
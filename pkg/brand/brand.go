@@ -0,0 +1,273 @@
+package brand
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/Jerinji2016/fdawg/pkg/identity"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// BrandsDirName is the directory holding one subdirectory per brand
+	BrandsDirName = "brands"
+
+	// BrandFileName is the profile file in each brand's directory
+	BrandFileName = "brand.yaml"
+
+	// DefaultAssetsDirName is the directory in a brand whose files override the project's assets
+	DefaultAssetsDirName = "assets"
+
+	// StateDirName is the name of the directory holding fdawg project state
+	StateDirName = ".fdawg"
+
+	// StateFileName records the active brand in the state directory
+	StateFileName = "brand.json"
+
+	// OriginalsDirName holds the project's own versions of assets a brand overrides
+	OriginalsDirName = "brand_originals"
+)
+
+// Brand is a white-label profile declared in brands/<name>/brand.yaml. Paths are
+// relative to the brand's directory, and anything left empty is not changed.
+type Brand struct {
+	// Name is the brand's directory name
+	Name string `yaml:"-" json:"name"`
+
+	AppName   string            `yaml:"app_name,omitempty" json:"app_name,omitempty"`
+	AppNames  map[string]string `yaml:"app_names,omitempty" json:"app_names,omitempty"`
+	BundleID  string            `yaml:"bundle_id,omitempty" json:"bundle_id,omitempty"`
+	BundleIDs map[string]string `yaml:"bundle_ids,omitempty" json:"bundle_ids,omitempty"`
+	// MoveAndroidPackage moves the Android sources along with the Android bundle ID
+	MoveAndroidPackage bool `yaml:"move_android_package,omitempty" json:"move_android_package,omitempty"`
+
+	// Icon is the source image for flutter_launcher_icons
+	Icon   string       `yaml:"icon,omitempty" json:"icon,omitempty"`
+	Splash SplashConfig `yaml:"splash,omitempty" json:"splash,omitempty"`
+
+	// Environment is the name of an environment in .environment used for the brand's builds
+	Environment string `yaml:"environment,omitempty" json:"environment,omitempty"`
+
+	// Assets is a directory mirroring assets/, whose files replace or add to the project's
+	Assets string `yaml:"assets,omitempty" json:"assets,omitempty"`
+}
+
+// SplashConfig holds the flutter_native_splash settings of a brand
+type SplashConfig struct {
+	Color     string `yaml:"color,omitempty" json:"color,omitempty"`
+	ColorDark string `yaml:"color_dark,omitempty" json:"color_dark,omitempty"`
+	Image     string `yaml:"image,omitempty" json:"image,omitempty"`
+}
+
+// BrandInfo summarizes a brand for listing
+type BrandInfo struct {
+	Name     string `json:"name"`
+	AppName  string `json:"app_name,omitempty"`
+	BundleID string `json:"bundle_id,omitempty"`
+	Active   bool   `json:"active"`
+	Error    string `json:"error,omitempty"`
+}
+
+// State records the active brand and the assets it changed, so the next brand can put them back
+type State struct {
+	Brand       string    `json:"brand"`
+	AppliedAt   time.Time `json:"applied_at"`
+	Environment string    `json:"environment,omitempty"`
+	// Overridden are assets that existed before and were replaced; their originals are in OriginalsDirName
+	Overridden []string `json:"overridden"`
+	// Added are assets the brand added that the project doesn't have
+	Added []string `json:"added"`
+}
+
+// brandNameRegex matches brand directory names
+var brandNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// hexColorRegex matches #RGB and #RRGGBB colors
+var hexColorRegex = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// GetBrandsDir returns the path to the brands directory for a Flutter project
+func GetBrandsDir(projectPath string) string {
+	return filepath.Join(projectPath, BrandsDirName)
+}
+
+// GetBrandDir returns the path to a brand's directory
+func GetBrandDir(projectPath, name string) string {
+	return filepath.Join(GetBrandsDir(projectPath), name)
+}
+
+// GetStatePath returns the path to the active brand state file
+func GetStatePath(projectPath string) string {
+	return filepath.Join(projectPath, StateDirName, StateFileName)
+}
+
+// GetOriginalsDir returns the directory the project's own versions of overridden assets are kept in
+func GetOriginalsDir(projectPath string) string {
+	return filepath.Join(projectPath, StateDirName, OriginalsDirName)
+}
+
+// LoadBrand reads and checks brands/<name>/brand.yaml. Unknown keys are rejected so typos don't go unnoticed.
+func LoadBrand(projectPath, name string) (*Brand, error) {
+	if !brandNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("invalid brand name %q", name)
+	}
+
+	path := filepath.Join(GetBrandDir(projectPath, name), BrandFileName)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("brand %s not found: %s doesn't exist", name, filepath.ToSlash(filepath.Join(BrandsDirName, name, BrandFileName)))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read brand %s: %v", name, err)
+	}
+
+	brand := &Brand{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(brand); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse brand %s: %v", name, err)
+	}
+	brand.Name = name
+
+	if err := brand.validate(projectPath); err != nil {
+		return nil, fmt.Errorf("brand %s: %v", name, err)
+	}
+
+	return brand, nil
+}
+
+// ListBrands returns every brand in the brands directory, marking the active one
+func ListBrands(projectPath string) ([]BrandInfo, error) {
+	entries, err := os.ReadDir(GetBrandsDir(projectPath))
+	if os.IsNotExist(err) {
+		return []BrandInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read brands directory: %v", err)
+	}
+
+	state, err := LoadState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	brands := []BrandInfo{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(GetBrandsDir(projectPath), entry.Name(), BrandFileName)); err != nil {
+			continue
+		}
+
+		info := BrandInfo{Name: entry.Name(), Active: state != nil && state.Brand == entry.Name()}
+		if brand, err := LoadBrand(projectPath, entry.Name()); err != nil {
+			info.Error = err.Error()
+		} else {
+			info.AppName = brand.AppName
+			info.BundleID = brand.BundleID
+		}
+		brands = append(brands, info)
+	}
+
+	sort.Slice(brands, func(i, j int) bool {
+		return brands[i].Name < brands[j].Name
+	})
+
+	return brands, nil
+}
+
+// LoadState reads the active brand state, or returns nil if no brand has been applied
+func LoadState(projectPath string) (*State, error) {
+	data, err := os.ReadFile(GetStatePath(projectPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read brand state: %v", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse brand state: %v", err)
+	}
+	return &state, nil
+}
+
+// saveState writes the active brand state
+func saveState(projectPath string, state *State) error {
+	path := GetStatePath(projectPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// Identity returns the names and bundle IDs the brand sets, or nil if it sets none
+func (b *Brand) Identity() *identity.Identity {
+	if b.AppName == "" && len(b.AppNames) == 0 && b.BundleID == "" && len(b.BundleIDs) == 0 {
+		return nil
+	}
+
+	return &identity.Identity{
+		Name:               b.AppName,
+		Names:              b.AppNames,
+		BundleID:           b.BundleID,
+		BundleIDs:          b.BundleIDs,
+		MoveAndroidPackage: b.MoveAndroidPackage,
+	}
+}
+
+// AssetsDir returns the path to the brand's asset overrides
+func (b *Brand) AssetsDir(projectPath string) string {
+	dir := b.Assets
+	if dir == "" {
+		dir = DefaultAssetsDirName
+	}
+	return filepath.Join(GetBrandDir(projectPath, b.Name), filepath.FromSlash(dir))
+}
+
+// validate checks the brand's colors and that the files it refers to exist
+func (b *Brand) validate(projectPath string) error {
+	brandDir := GetBrandDir(projectPath, b.Name)
+
+	for field, path := range map[string]string{"icon": b.Icon, "splash.image": b.Splash.Image} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(brandDir, filepath.FromSlash(path))); err != nil {
+			return fmt.Errorf("%s: %s doesn't exist in %s", field, path, filepath.ToSlash(filepath.Join(BrandsDirName, b.Name)))
+		}
+	}
+
+	for field, color := range map[string]string{"splash.color": b.Splash.Color, "splash.color_dark": b.Splash.ColorDark} {
+		if color != "" && !hexColorRegex.MatchString(color) {
+			return fmt.Errorf("%s: %q is not a hex color like #FFFFFF", field, color)
+		}
+	}
+
+	if b.Assets != "" {
+		if info, err := os.Stat(b.AssetsDir(projectPath)); err != nil || !info.IsDir() {
+			return fmt.Errorf("assets: directory %s doesn't exist in %s", b.Assets, filepath.ToSlash(filepath.Join(BrandsDirName, b.Name)))
+		}
+	}
+
+	return nil
+}
+
+// brandPath returns a path in the brand's directory relative to the project, with forward slashes
+func (b *Brand) brandPath(path string) string {
+	return filepath.ToSlash(filepath.Join(BrandsDirName, b.Name, filepath.FromSlash(path)))
+}
@@ -0,0 +1,132 @@
+package brand
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// LauncherIconsFileName is the flutter_launcher_icons configuration file
+	LauncherIconsFileName = "flutter_launcher_icons.yaml"
+
+	// NativeSplashFileName is the flutter_native_splash configuration file
+	NativeSplashFileName = "flutter_native_splash.yaml"
+)
+
+// readYAMLSection returns the scalar values under a top-level key of a YAML file.
+// A missing file or key gives an empty map.
+func readYAMLSection(path, section string) (map[string]string, error) {
+	values := make(map[string]string)
+
+	doc, err := readYAMLDocument(path)
+	if err != nil || doc == nil {
+		return values, err
+	}
+
+	sectionNode := mappingValue(doc.Content[0], section)
+	if sectionNode == nil || sectionNode.Kind != yaml.MappingNode {
+		return values, nil
+	}
+
+	for i := 0; i+1 < len(sectionNode.Content); i += 2 {
+		if value := sectionNode.Content[i+1]; value.Kind == yaml.ScalarNode {
+			values[sectionNode.Content[i].Value] = value.Value
+		}
+	}
+	return values, nil
+}
+
+// writeYAMLSection sets scalar values under a top-level key of a YAML file, creating
+// the file or key if needed. Other keys and comments are kept.
+func writeYAMLSection(path, section string, values map[string]string) error {
+	doc, err := readYAMLDocument(path)
+	if err != nil {
+		return err
+	}
+	if doc == nil {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	root := doc.Content[0]
+	sectionNode := mappingValue(root, section)
+	if sectionNode == nil {
+		sectionNode = &yaml.Node{Kind: yaml.MappingNode}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: section}, sectionNode)
+	}
+	if sectionNode.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s in %s is not a mapping", section, path)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		valueNode := mappingValue(sectionNode, key)
+		if valueNode == nil {
+			valueNode = &yaml.Node{Kind: yaml.ScalarNode}
+			sectionNode.Content = append(sectionNode.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, valueNode)
+		}
+		valueNode.Kind = yaml.ScalarNode
+		valueNode.Tag = "!!str"
+		valueNode.Value = values[key]
+		valueNode.Content = nil
+		// A leading # would start a comment
+		if strings.HasPrefix(values[key], "#") {
+			valueNode.Style = yaml.DoubleQuotedStyle
+		}
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+// readYAMLDocument parses a YAML file whose top level is a mapping, or returns nil if the file is missing or empty
+func readYAMLDocument(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a YAML mapping", path)
+	}
+
+	return &doc, nil
+}
+
+// mappingValue returns the value node of a key in a mapping node
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
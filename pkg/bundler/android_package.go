@@ -0,0 +1,282 @@
+package bundler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AndroidPackageMove is a source file that moves to the directory of the new package
+type AndroidPackageMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// AndroidPackagePlan describes moving the Android Kotlin and Java sources from one
+// package to another. Paths are relative to the project and use forward slashes.
+type AndroidPackagePlan struct {
+	OldPackage string               `json:"old_package"`
+	NewPackage string               `json:"new_package"`
+	Moves      []AndroidPackageMove `json:"moves"`
+	// Manifests are the AndroidManifest.xml files that refer to the old package by name
+	Manifests []string `json:"manifests"`
+}
+
+// androidSourceLanguages are the source directories of an Android source set
+var androidSourceLanguages = []string{"kotlin", "java"}
+
+// androidPackageDeclRegex matches a package declaration, capturing the package name
+var androidPackageDeclRegex = regexp.MustCompile(`(?m)^\s*package\s+([A-Za-z_][\w.]*)`)
+
+// PlanAndroidPackageMove works out how the Android sources move when the package
+// changes to newPackage. The current package is the namespace in build.gradle, or
+// the package of MainActivity if no sources are in the namespace's directory. It
+// returns nil if there is nothing to move.
+func PlanAndroidPackageMove(projectPath, newPackage string) (*AndroidPackagePlan, error) {
+	oldPackage := androidSourcePackage(projectPath)
+	if oldPackage == "" || oldPackage == newPackage {
+		return nil, nil
+	}
+
+	plan := &AndroidPackagePlan{OldPackage: oldPackage, NewPackage: newPackage}
+
+	for _, root := range androidSourceRoots(projectPath) {
+		oldDir := filepath.Join(projectPath, root, packageDir(oldPackage))
+		newDir := filepath.Join(projectPath, root, packageDir(newPackage))
+
+		err := filepath.Walk(oldDir, func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(oldDir, path)
+			if err != nil {
+				return err
+			}
+			from, _ := filepath.Rel(projectPath, path)
+			to, _ := filepath.Rel(projectPath, filepath.Join(newDir, relPath))
+			plan.Moves = append(plan.Moves, AndroidPackageMove{From: filepath.ToSlash(from), To: filepath.ToSlash(to)})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", root, err)
+		}
+	}
+
+	// Don't overwrite sources that are already in the new package
+	moving := make(map[string]bool)
+	for _, move := range plan.Moves {
+		moving[move.From] = true
+	}
+	for _, move := range plan.Moves {
+		if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(move.To))); err == nil && !moving[move.To] {
+			return nil, fmt.Errorf("%s already exists", move.To)
+		}
+	}
+
+	manifests, _ := filepath.Glob(filepath.Join(projectPath, "android", "app", "src", "*", "AndroidManifest.xml"))
+	sort.Strings(manifests)
+	for _, manifest := range manifests {
+		content, err := os.ReadFile(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %v", err)
+		}
+		if manifestPackageRegex(oldPackage).Match(content) {
+			relPath, _ := filepath.Rel(projectPath, manifest)
+			plan.Manifests = append(plan.Manifests, filepath.ToSlash(relPath))
+		}
+	}
+
+	if len(plan.Moves) == 0 && len(plan.Manifests) == 0 {
+		return nil, nil
+	}
+
+	return plan, nil
+}
+
+// moveAndroidPackage moves the sources in a plan, updating their package declarations
+// and imports of the old package, and rewrites the manifests' references
+func moveAndroidPackage(projectPath string, plan *AndroidPackagePlan) error {
+	declRe := regexp.MustCompile(`(?m)^(\s*package\s+)` + regexp.QuoteMeta(plan.OldPackage) + `\b`)
+	importRe := regexp.MustCompile(`(?m)^(\s*import\s+(?:static\s+)?)` + regexp.QuoteMeta(plan.OldPackage) + `\b`)
+	replacement := "${1}" + literalReplacement(plan.NewPackage)
+
+	// Read everything first, since the new directory may be inside the old one
+	contents := make([][]byte, len(plan.Moves))
+	for i, move := range plan.Moves {
+		content, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(move.From)))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", move.From, err)
+		}
+		if ext := filepath.Ext(move.From); ext == ".kt" || ext == ".java" {
+			content = declRe.ReplaceAll(content, []byte(replacement))
+			content = importRe.ReplaceAll(content, []byte(replacement))
+		}
+		contents[i] = content
+	}
+
+	for _, move := range plan.Moves {
+		if err := os.Remove(filepath.Join(projectPath, filepath.FromSlash(move.From))); err != nil {
+			return fmt.Errorf("failed to move %s: %v", move.From, err)
+		}
+	}
+
+	for i, move := range plan.Moves {
+		toPath := filepath.Join(projectPath, filepath.FromSlash(move.To))
+		if err := os.MkdirAll(filepath.Dir(toPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %v", move.To, err)
+		}
+		if err := os.WriteFile(toPath, contents[i], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", move.To, err)
+		}
+	}
+
+	removeEmptySourceDirs(projectPath, plan.Moves)
+
+	manifestRe := manifestPackageRegex(plan.OldPackage)
+	for _, manifest := range plan.Manifests {
+		manifestPath := filepath.Join(projectPath, filepath.FromSlash(manifest))
+		content, err := os.ReadFile(manifestPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", manifest, err)
+		}
+		content = manifestRe.ReplaceAll(content, []byte(`="`+literalReplacement(plan.NewPackage)+"$1"))
+		if err := os.WriteFile(manifestPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", manifest, err)
+		}
+	}
+
+	return nil
+}
+
+// undoAndroidPackageMove removes the moved sources so the backups can be restored in their place
+func undoAndroidPackageMove(projectPath string, plan *AndroidPackagePlan) {
+	moving := make(map[string]bool)
+	for _, move := range plan.Moves {
+		moving[move.From] = true
+	}
+	for _, move := range plan.Moves {
+		if !moving[move.To] {
+			os.Remove(filepath.Join(projectPath, filepath.FromSlash(move.To)))
+		}
+	}
+
+	// The new package's directories go too, once they are empty
+	reversed := make([]AndroidPackageMove, len(plan.Moves))
+	for i, move := range plan.Moves {
+		reversed[i] = AndroidPackageMove{From: move.To, To: move.From}
+	}
+	removeEmptySourceDirs(projectPath, reversed)
+}
+
+// androidSourcePackage returns the package the Android sources are in
+func androidSourcePackage(projectPath string) string {
+	info := getAndroidBundleID(projectPath)
+	for _, candidate := range []string{info.Namespace, info.BundleID} {
+		if candidate == "" {
+			continue
+		}
+		for _, root := range androidSourceRoots(projectPath) {
+			if _, err := os.Stat(filepath.Join(projectPath, root, packageDir(candidate))); err == nil {
+				return candidate
+			}
+		}
+	}
+
+	// Fall back to the package MainActivity declares
+	for _, language := range androidSourceLanguages {
+		root := filepath.Join(projectPath, "android", "app", "src", "main", language)
+		var found string
+		filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || found != "" {
+				return filepath.SkipDir
+			}
+			name := info.Name()
+			if name != "MainActivity.kt" && name != "MainActivity.java" {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			if matches := androidPackageDeclRegex.FindSubmatch(content); matches != nil {
+				found = string(matches[1])
+			}
+			return nil
+		})
+		if found != "" {
+			return found
+		}
+	}
+
+	return ""
+}
+
+// androidSourceRoots returns the kotlin and java directories of every Android source set
+func androidSourceRoots(projectPath string) []string {
+	var roots []string
+	sourceSets, _ := os.ReadDir(filepath.Join(projectPath, "android", "app", "src"))
+	for _, sourceSet := range sourceSets {
+		if !sourceSet.IsDir() {
+			continue
+		}
+		for _, language := range androidSourceLanguages {
+			root := filepath.Join("android", "app", "src", sourceSet.Name(), language)
+			if info, err := os.Stat(filepath.Join(projectPath, root)); err == nil && info.IsDir() {
+				roots = append(roots, root)
+			}
+		}
+	}
+	return roots
+}
+
+// packageDir converts a package name to its directory, e.g. com/example/app
+func packageDir(packageName string) string {
+	return filepath.Join(strings.Split(packageName, ".")...)
+}
+
+// manifestPackageRegex matches manifest attribute values that are the package or a class in it
+func manifestPackageRegex(packageName string) *regexp.Regexp {
+	return regexp.MustCompile(`="` + regexp.QuoteMeta(packageName) + `([."])`)
+}
+
+// removeEmptySourceDirs removes the directories moved files left empty, up to the source root
+func removeEmptySourceDirs(projectPath string, moves []AndroidPackageMove) {
+	roots := androidSourceRoots(projectPath)
+
+	dirs := make(map[string]bool)
+	for _, move := range moves {
+		dirs[filepath.Dir(filepath.Join(projectPath, filepath.FromSlash(move.From)))] = true
+	}
+
+	for dir := range dirs {
+		for current := dir; ; current = filepath.Dir(current) {
+			isRoot := false
+			for _, root := range roots {
+				if current == filepath.Join(projectPath, root) {
+					isRoot = true
+				}
+			}
+			if isRoot || !strings.HasPrefix(current, projectPath) {
+				break
+			}
+			if entries, err := os.ReadDir(current); err != nil || len(entries) > 0 || os.Remove(current) != nil {
+				break
+			}
+		}
+	}
+}
+
+// literalReplacement escapes $ so a value can be used in a regexp replacement template
+func literalReplacement(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
@@ -38,6 +38,9 @@ type BundleIDRequest struct {
 	ProjectPath string              `json:"project_path"`
 	Universal   string              `json:"universal,omitempty"`
 	Platforms   map[Platform]string `json:"platforms,omitempty"`
+	// MoveAndroidPackage moves the Android sources to the directory of the new bundle ID
+	// and updates their package declarations and the manifest's class references
+	MoveAndroidPackage bool `json:"move_android_package,omitempty"`
 }
 
 // GetBundleIDs retrieves bundle IDs for specified platforms
@@ -89,9 +92,19 @@ func SetBundleIDs(request *BundleIDRequest) error {
 		return fmt.Errorf("either universal bundle ID or platform-specific bundle IDs must be provided")
 	}
 
+	// Plan the Android source move while the old namespace is still in build.gradle
+	var androidMove *AndroidPackagePlan
+	if bundleID, ok := platformsToUpdate[PlatformAndroid]; ok && request.MoveAndroidPackage {
+		var err error
+		androidMove, err = PlanAndroidPackageMove(request.ProjectPath, bundleID)
+		if err != nil {
+			return fmt.Errorf("failed to plan Android package move: %v", err)
+		}
+	}
+
 	// Create backups before making changes
 	backupDir := filepath.Join(request.ProjectPath, ".fdawg-backups", "bundler")
-	if err := createBackups(request.ProjectPath, platformsToUpdate, backupDir); err != nil {
+	if err := createBackups(request.ProjectPath, platformsToUpdate, backupDir, androidMove); err != nil {
 		return fmt.Errorf("failed to create backups: %v", err)
 	}
 
@@ -103,9 +116,15 @@ func SetBundleIDs(request *BundleIDRequest) error {
 		}
 	}
 
+	if androidMove != nil && len(errors) == 0 {
+		if err := moveAndroidPackage(request.ProjectPath, androidMove); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", PlatformAndroid, err))
+		}
+	}
+
 	if len(errors) > 0 {
 		// If there were errors, attempt to restore from backups
-		restoreFromBackups(request.ProjectPath, platformsToUpdate, backupDir)
+		restoreFromBackups(request.ProjectPath, platformsToUpdate, backupDir, androidMove)
 		return fmt.Errorf("failed to set bundle IDs: %v", errors)
 	}
 
@@ -223,7 +242,7 @@ func setBundleIDForPlatform(projectPath string, platform Platform, bundleID stri
 }
 
 // createBackups creates backups of platform-specific files before making changes
-func createBackups(projectPath string, platformsToUpdate map[Platform]string, backupDir string) error {
+func createBackups(projectPath string, platformsToUpdate map[Platform]string, backupDir string, androidMove *AndroidPackagePlan) error {
 	// Create backup directory
 	if err := os.MkdirAll(backupDir, 0755); err != nil {
 		return err
@@ -231,7 +250,7 @@ func createBackups(projectPath string, platformsToUpdate map[Platform]string, ba
 
 	// Create backups for each platform
 	for platform := range platformsToUpdate {
-		if err := createPlatformBackup(projectPath, platform, backupDir); err != nil {
+		if err := createPlatformBackup(projectPath, platform, backupDir, androidMove); err != nil {
 			return fmt.Errorf("failed to backup %s: %v", platform, err)
 		}
 	}
@@ -240,8 +259,8 @@ func createBackups(projectPath string, platformsToUpdate map[Platform]string, ba
 }
 
 // restoreFromBackups restores files from backups in case of errors
-func restoreFromBackups(projectPath string, platformsToUpdate map[Platform]string, backupDir string) {
+func restoreFromBackups(projectPath string, platformsToUpdate map[Platform]string, backupDir string, androidMove *AndroidPackagePlan) {
 	for platform := range platformsToUpdate {
-		restorePlatformBackup(projectPath, platform, backupDir)
+		restorePlatformBackup(projectPath, platform, backupDir, androidMove)
 	}
 }
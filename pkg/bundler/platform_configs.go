@@ -439,32 +439,52 @@ func updateWebManifest(manifestPath, bundleID string) error {
 
 // Backup and restore functions
 
-func createPlatformBackup(projectPath string, platform Platform, backupDir string) error {
-	var filesToBackup []string
+// BackupFiles returns the files, relative to the project, that setting bundle IDs
+// on the given platforms may change, including the Android sources a move touches
+func BackupFiles(projectPath string, platforms []Platform, androidMove *AndroidPackagePlan) []string {
+	var files []string
+	for _, platform := range platforms {
+		files = append(files, platformBackupFiles(projectPath, platform, androidMove)...)
+	}
+	return files
+}
 
+// platformBackupFiles returns the files that hold a platform's bundle ID
+func platformBackupFiles(projectPath string, platform Platform, androidMove *AndroidPackagePlan) []string {
 	switch platform {
 	case PlatformAndroid:
 		// Check which format exists and backup accordingly
 		ktsPath := "android/app/build.gradle.kts"
 		groovyPath := "android/app/build.gradle"
+		files := []string{groovyPath}
 		if _, err := os.Stat(filepath.Join(projectPath, ktsPath)); err == nil {
-			filesToBackup = []string{ktsPath}
-		} else {
-			filesToBackup = []string{groovyPath}
+			files = []string{ktsPath}
 		}
+
+		// Moving the package also changes the sources and manifests
+		if androidMove != nil {
+			files = append(files, androidMove.Manifests...)
+			for _, move := range androidMove.Moves {
+				files = append(files, move.From)
+			}
+		}
+		return files
 	case PlatformIOS:
-		filesToBackup = []string{"ios/Runner.xcodeproj/project.pbxproj"}
+		return []string{"ios/Runner.xcodeproj/project.pbxproj"}
 	case PlatformMacOS:
-		filesToBackup = []string{"macos/Runner/Configs/AppInfo.xcconfig"}
+		return []string{"macos/Runner/Configs/AppInfo.xcconfig"}
 	case PlatformLinux:
-		filesToBackup = []string{"linux/CMakeLists.txt"}
+		return []string{"linux/CMakeLists.txt"}
 	case PlatformWindows:
-		filesToBackup = []string{"windows/CMakeLists.txt"}
+		return []string{"windows/CMakeLists.txt"}
 	case PlatformWeb:
-		filesToBackup = []string{"web/manifest.json"}
+		return []string{"web/manifest.json"}
 	}
+	return nil
+}
 
-	for _, file := range filesToBackup {
+func createPlatformBackup(projectPath string, platform Platform, backupDir string, androidMove *AndroidPackagePlan) error {
+	for _, file := range platformBackupFiles(projectPath, platform, androidMove) {
 		srcPath := filepath.Join(projectPath, file)
 		dstPath := filepath.Join(backupDir, file)
 
@@ -480,34 +500,16 @@ func createPlatformBackup(projectPath string, platform Platform, backupDir strin
 	return nil
 }
 
-func restorePlatformBackup(projectPath string, platform Platform, backupDir string) {
-	var filesToRestore []string
-
-	switch platform {
-	case PlatformAndroid:
-		// Check which format exists and restore accordingly
-		ktsPath := "android/app/build.gradle.kts"
-		groovyPath := "android/app/build.gradle"
-		if _, err := os.Stat(filepath.Join(projectPath, ktsPath)); err == nil {
-			filesToRestore = []string{ktsPath}
-		} else {
-			filesToRestore = []string{groovyPath}
-		}
-	case PlatformIOS:
-		filesToRestore = []string{"ios/Runner.xcodeproj/project.pbxproj"}
-	case PlatformMacOS:
-		filesToRestore = []string{"macos/Runner/Configs/AppInfo.xcconfig"}
-	case PlatformLinux:
-		filesToRestore = []string{"linux/CMakeLists.txt"}
-	case PlatformWindows:
-		filesToRestore = []string{"windows/CMakeLists.txt"}
-	case PlatformWeb:
-		filesToRestore = []string{"web/manifest.json"}
+func restorePlatformBackup(projectPath string, platform Platform, backupDir string, androidMove *AndroidPackagePlan) {
+	// Clear the moved sources out of the new package first
+	if platform == PlatformAndroid && androidMove != nil {
+		undoAndroidPackageMove(projectPath, androidMove)
 	}
 
-	for _, file := range filesToRestore {
+	for _, file := range platformBackupFiles(projectPath, platform, androidMove) {
 		srcPath := filepath.Join(backupDir, file)
 		dstPath := filepath.Join(projectPath, file)
+		os.MkdirAll(filepath.Dir(dstPath), 0755)
 		copyFile(srcPath, dstPath) // Ignore errors during restore
 	}
 }
@@ -0,0 +1,64 @@
+package bundler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateBundleID checks a bundle ID against the reverse domain notation the
+// platforms share: dot-separated segments of letters, digits, hyphens and underscores
+func ValidateBundleID(bundleID string) error {
+	if bundleID == "" {
+		return fmt.Errorf("bundle ID cannot be empty")
+	}
+
+	// Check for reverse domain notation (at least one dot)
+	if !strings.Contains(bundleID, ".") {
+		return fmt.Errorf("bundle ID should follow reverse domain notation (e.g., com.company.app)")
+	}
+
+	// Check for valid characters (alphanumeric, dots, hyphens, underscores)
+	for _, char := range bundleID {
+		if !((char >= 'a' && char <= 'z') ||
+			(char >= 'A' && char <= 'Z') ||
+			(char >= '0' && char <= '9') ||
+			char == '.' || char == '-' || char == '_') {
+			return fmt.Errorf("bundle ID contains invalid character: %c. Only alphanumeric characters, dots, hyphens, and underscores are allowed", char)
+		}
+	}
+
+	// Check that it doesn't start or end with a dot
+	if strings.HasPrefix(bundleID, ".") || strings.HasSuffix(bundleID, ".") {
+		return fmt.Errorf("bundle ID cannot start or end with a dot")
+	}
+
+	// Check for consecutive dots
+	if strings.Contains(bundleID, "..") {
+		return fmt.Errorf("bundle ID cannot contain consecutive dots")
+	}
+
+	// Check minimum length
+	if len(bundleID) < 3 {
+		return fmt.Errorf("bundle ID is too short (minimum 3 characters)")
+	}
+
+	// Check maximum length (reasonable limit)
+	if len(bundleID) > 255 {
+		return fmt.Errorf("bundle ID is too long (maximum 255 characters)")
+	}
+
+	// Split by dots and validate each segment
+	segments := strings.Split(bundleID, ".")
+	for i, segment := range segments {
+		if segment == "" {
+			return fmt.Errorf("bundle ID segment %d is empty", i+1)
+		}
+
+		// Each segment should not start with a number (Java package naming convention)
+		if len(segment) > 0 && segment[0] >= '0' && segment[0] <= '9' {
+			return fmt.Errorf("bundle ID segment '%s' cannot start with a number", segment)
+		}
+	}
+
+	return nil
+}
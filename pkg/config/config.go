@@ -16,6 +16,32 @@ type FdawgConfig struct {
 type TranslationConfig struct {
 	GoogleTranslateAPIKey string `json:"google_translate_api_key"`
 	Enabled               bool   `json:"enabled"`
+
+	// Provider is the machine translation provider: google (default), deepl,
+	// libretranslate or fixture
+	Provider             string `json:"provider,omitempty"`
+	DeepLAPIKey          string `json:"deepl_api_key,omitempty"`
+	LibreTranslateURL    string `json:"libretranslate_url,omitempty"`
+	LibreTranslateAPIKey string `json:"libretranslate_api_key,omitempty"`
+	// FixturePath is a JSON file of canned translations, used for tests
+	FixturePath string `json:"fixture_path,omitempty"`
+	// RequestsPerSecond limits calls to the provider during bulk translation
+	RequestsPerSecond float64 `json:"requests_per_second,omitempty"`
+}
+
+// IsConfigured checks if the selected provider has the settings it needs.
+// LibreTranslate falls back to a local instance, so it needs no settings.
+func (c *TranslationConfig) IsConfigured() bool {
+	switch c.Provider {
+	case "deepl":
+		return c.DeepLAPIKey != ""
+	case "libretranslate":
+		return true
+	case "fixture":
+		return c.FixturePath != ""
+	default:
+		return c.GoogleTranslateAPIKey != ""
+	}
 }
 
 // GetConfigPath returns the path to the .fdawg-config file
@@ -49,8 +75,8 @@ func LoadConfig(projectPath string) (*FdawgConfig, error) {
 		return nil, fmt.Errorf("failed to parse config file: %v", err)
 	}
 
-	// Update enabled status based on API key
-	config.Translation.Enabled = config.Translation.GoogleTranslateAPIKey != ""
+	// Update enabled status based on the provider settings
+	config.Translation.Enabled = config.Translation.IsConfigured()
 
 	return &config, nil
 }
@@ -59,8 +85,8 @@ func LoadConfig(projectPath string) (*FdawgConfig, error) {
 func SaveConfig(projectPath string, config *FdawgConfig) error {
 	configPath := GetConfigPath(projectPath)
 
-	// Update enabled status based on API key
-	config.Translation.Enabled = config.Translation.GoogleTranslateAPIKey != ""
+	// Update enabled status based on the provider settings
+	config.Translation.Enabled = config.Translation.IsConfigured()
 
 	// Convert to JSON with proper formatting
 	data, err := json.MarshalIndent(config, "", "  ")
@@ -84,7 +110,7 @@ func UpdateTranslationAPIKey(projectPath, apiKey string) error {
 	}
 
 	config.Translation.GoogleTranslateAPIKey = apiKey
-	config.Translation.Enabled = apiKey != ""
+	config.Translation.Enabled = config.Translation.IsConfigured()
 
 	return SaveConfig(projectPath, config)
 }
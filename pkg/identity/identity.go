@@ -0,0 +1,641 @@
+package identity
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/bundler"
+	"github.com/Jerinji2016/fdawg/pkg/namer"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileName is the identity file read when no path is given
+const DefaultFileName = "identity.yaml"
+
+// Change fields
+const (
+	FieldName     = "name"
+	FieldBundleID = "bundle_id"
+	FieldPackage  = "package"
+	FieldImports  = "imports"
+	// FieldAndroidSource is an Android source file moving to the new package's directory
+	FieldAndroidSource = "android_source"
+)
+
+// Identity is the set of values that make up an app's identity: its display names,
+// bundle IDs and Dart package name. Per-platform values override the universal ones,
+// and anything left empty is not changed.
+type Identity struct {
+	Name      string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Names     map[string]string `yaml:"names,omitempty" json:"names,omitempty"`
+	BundleID  string            `yaml:"bundle_id,omitempty" json:"bundle_id,omitempty"`
+	BundleIDs map[string]string `yaml:"bundle_ids,omitempty" json:"bundle_ids,omitempty"`
+	Package   string            `yaml:"package,omitempty" json:"package,omitempty"`
+	// MoveAndroidPackage moves the Android sources to the directory of the new Android bundle ID
+	MoveAndroidPackage bool `yaml:"move_android_package,omitempty" json:"move_android_package,omitempty"`
+}
+
+// Change is a single value an identity changes
+type Change struct {
+	Field    string `json:"field"`
+	Platform string `json:"platform,omitempty"`
+	// File is set for import changes, relative to the project
+	File  string `json:"file,omitempty"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int    `json:"count,omitempty"`
+}
+
+// Plan describes what applying an identity changes, and the files it touches
+type Plan struct {
+	Changes  []Change `json:"changes"`
+	Warnings []string `json:"warnings"`
+	Files    []string `json:"files"`
+
+	names       map[namer.Platform]string
+	bundleIDs   map[bundler.Platform]string
+	androidMove *bundler.AndroidPackagePlan
+	oldPackage  string
+	newPackage  string
+	dartFiles   []string
+}
+
+// packageNameRegex matches a valid pub package name
+var packageNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// pubspecNameRegex matches the top-level name in pubspec.yaml, keeping any quote
+var pubspecNameRegex = regexp.MustCompile(`(?m)^(name:[ \t]*["']?)([A-Za-z0-9_]+)`)
+
+// dartReservedWords can't be used as package names, since they can't be imported
+var dartReservedWords = map[string]bool{
+	"assert": true, "break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "else": true, "enum": true, "extends": true,
+	"false": true, "final": true, "finally": true, "for": true, "if": true, "in": true,
+	"is": true, "new": true, "null": true, "rethrow": true, "return": true, "super": true,
+	"switch": true, "this": true, "throw": true, "true": true, "try": true, "var": true,
+	"void": true, "while": true, "with": true,
+}
+
+// LoadIdentity reads an identity file. Unknown keys are rejected so typos don't go unnoticed.
+func LoadIdentity(path string) (*Identity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identity file: %v", err)
+	}
+
+	var identity Identity
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&identity); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("identity file %s is empty", path)
+		}
+		return nil, fmt.Errorf("failed to parse identity file: %v", err)
+	}
+
+	return &identity, nil
+}
+
+// GetIdentity reads the project's current identity. Names and bundle IDs that are
+// the same on every platform are returned as universal values.
+func GetIdentity(projectPath string) (*Identity, error) {
+	identity := &Identity{}
+
+	names, err := currentNames(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	identity.Name, identity.Names = collapse(names)
+
+	bundleIDs, err := currentBundleIDs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	identity.BundleID, identity.BundleIDs = collapse(bundleIDs)
+
+	identity.Package, err = readPackageName(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// ValidatePackageName checks a pub package name: lowercase letters, digits and
+// underscores, starting with a letter, and not a Dart reserved word
+func ValidatePackageName(name string) error {
+	if !packageNameRegex.MatchString(name) {
+		return fmt.Errorf("package name %q must be lowercase letters, digits and underscores, starting with a letter", name)
+	}
+	if dartReservedWords[name] {
+		return fmt.Errorf("package name %q is a Dart reserved word", name)
+	}
+	return nil
+}
+
+// PlanIdentity works out what applying an identity would change without writing anything.
+// Invalid names, bundle IDs or package names are returned as an error.
+func PlanIdentity(projectPath string, identity *Identity) (*Plan, error) {
+	if identity.Name == "" && len(identity.Names) == 0 && identity.BundleID == "" &&
+		len(identity.BundleIDs) == 0 && identity.Package == "" {
+		return nil, fmt.Errorf("identity doesn't set a name, bundle ID or package")
+	}
+
+	plan := &Plan{
+		Changes:   []Change{},
+		Warnings:  []string{},
+		Files:     []string{},
+		names:     make(map[namer.Platform]string),
+		bundleIDs: make(map[bundler.Platform]string),
+	}
+
+	currentNameValues, err := currentNames(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	wantedNames, err := resolveValues("names", identity.Name, identity.Names, currentNameValues)
+	if err != nil {
+		return nil, err
+	}
+
+	currentBundleIDValues, err := currentBundleIDs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	wantedBundleIDs, err := resolveValues("bundle_ids", identity.BundleID, identity.BundleIDs, currentBundleIDValues)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the bundle IDs before anything else, since they decide the Linux and Windows binary names
+	for _, platform := range sortedKeys(wantedBundleIDs) {
+		if err := bundler.ValidateBundleID(wantedBundleIDs[platform]); err != nil {
+			return nil, fmt.Errorf("invalid bundle ID for %s: %v", platform, err)
+		}
+	}
+
+	for _, platform := range sortedKeys(wantedNames) {
+		name := wantedNames[platform]
+
+		// Linux and Windows keep both in BINARY_NAME, and the bundle ID wins
+		if bundleID, ok := wantedBundleIDs[platform]; ok && isBinaryNamePlatform(platform) {
+			if name != bundleID {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: BINARY_NAME holds both the app name and the bundle ID; using the bundle ID %s", platform, bundleID))
+			}
+			continue
+		}
+
+		if name == currentNameValues[platform] {
+			continue
+		}
+		plan.names[namer.Platform(platform)] = name
+		plan.Changes = append(plan.Changes, Change{Field: FieldName, Platform: platform, From: currentNameValues[platform], To: name})
+	}
+
+	if len(plan.names) > 0 {
+		report, err := namer.ValidateAppNames(&namer.SetAppNameRequest{ProjectPath: projectPath, Platforms: plan.names})
+		if err != nil {
+			return nil, err
+		}
+		if err := report.Err(); err != nil {
+			return nil, err
+		}
+		for _, issue := range report.Issues {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: %s", issue.Platform, issue.Message))
+		}
+	}
+
+	for _, platform := range sortedKeys(wantedBundleIDs) {
+		bundleID := wantedBundleIDs[platform]
+		if bundleID == currentBundleIDValues[platform] {
+			continue
+		}
+		plan.bundleIDs[bundler.Platform(platform)] = bundleID
+		plan.Changes = append(plan.Changes, Change{Field: FieldBundleID, Platform: platform, From: currentBundleIDValues[platform], To: bundleID})
+	}
+
+	if bundleID, ok := plan.bundleIDs[bundler.PlatformAndroid]; ok && identity.MoveAndroidPackage {
+		plan.androidMove, err = bundler.PlanAndroidPackageMove(projectPath, bundleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan Android package move: %v", err)
+		}
+		if plan.androidMove != nil {
+			for _, move := range plan.androidMove.Moves {
+				plan.Changes = append(plan.Changes, Change{Field: FieldAndroidSource, Platform: string(bundler.PlatformAndroid), From: move.From, To: move.To})
+			}
+		}
+	}
+
+	if identity.Package != "" {
+		if err := ValidatePackageName(identity.Package); err != nil {
+			return nil, err
+		}
+
+		currentPackage, err := readPackageName(projectPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if identity.Package != currentPackage {
+			plan.oldPackage = currentPackage
+			plan.newPackage = identity.Package
+			plan.Changes = append(plan.Changes, Change{Field: FieldPackage, File: "pubspec.yaml", From: currentPackage, To: identity.Package})
+
+			imports, err := findPackageImports(projectPath, currentPackage, identity.Package)
+			if err != nil {
+				return nil, err
+			}
+			for _, change := range imports {
+				plan.dartFiles = append(plan.dartFiles, change.File)
+			}
+			plan.Changes = append(plan.Changes, imports...)
+		}
+	}
+
+	plan.Files = plan.collectFiles(projectPath)
+	return plan, nil
+}
+
+// ApplyIdentity applies an identity as one transaction. Every file it may change
+// is backed up to .fdawg-backups/identity first, and if any step fails all of them
+// are restored, so the project never ends up with half an identity.
+func ApplyIdentity(projectPath string, identity *Identity) (*Plan, error) {
+	plan, err := PlanIdentity(projectPath, identity)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Changes) == 0 {
+		return plan, nil
+	}
+
+	backupDir := GetBackupDir(projectPath)
+	created, err := backupFiles(projectPath, plan.Files, backupDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backups: %v", err)
+	}
+
+	if err := plan.apply(projectPath); err != nil {
+		if restoreErr := restoreFiles(projectPath, plan.Files, created, backupDir); restoreErr != nil {
+			return nil, fmt.Errorf("%v; rolling back also failed, the original files are in %s: %v", err, backupDir, restoreErr)
+		}
+		return nil, fmt.Errorf("%v; all changes were rolled back", err)
+	}
+
+	return plan, nil
+}
+
+// GetBackupDir returns the directory identity backups are written to
+func GetBackupDir(projectPath string) string {
+	return filepath.Join(projectPath, ".fdawg-backups", "identity")
+}
+
+// apply writes the planned changes, stopping at the first error
+func (p *Plan) apply(projectPath string) error {
+	if len(p.names) > 0 {
+		if err := namer.SetAppNames(&namer.SetAppNameRequest{ProjectPath: projectPath, Platforms: p.names}); err != nil {
+			return err
+		}
+	}
+
+	if len(p.bundleIDs) > 0 {
+		request := &bundler.BundleIDRequest{
+			ProjectPath:        projectPath,
+			Platforms:          p.bundleIDs,
+			MoveAndroidPackage: p.androidMove != nil,
+		}
+		if err := bundler.SetBundleIDs(request); err != nil {
+			return err
+		}
+	}
+
+	if p.newPackage != "" {
+		if err := updatePubspecName(projectPath, p.newPackage); err != nil {
+			return fmt.Errorf("failed to update pubspec.yaml: %v", err)
+		}
+		for _, file := range p.dartFiles {
+			if err := rewritePackageImports(filepath.Join(projectPath, filepath.FromSlash(file)), p.oldPackage, p.newPackage); err != nil {
+				return fmt.Errorf("failed to update imports in %s: %v", file, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectFiles returns every file the plan may change, relative to the project
+func (p *Plan) collectFiles(projectPath string) []string {
+	var namePlatforms []namer.Platform
+	for platform := range p.names {
+		namePlatforms = append(namePlatforms, platform)
+	}
+	var bundlePlatforms []bundler.Platform
+	for platform := range p.bundleIDs {
+		bundlePlatforms = append(bundlePlatforms, platform)
+	}
+
+	files := append(namer.BackupFiles(projectPath, namePlatforms), bundler.BackupFiles(projectPath, bundlePlatforms, p.androidMove)...)
+	if p.androidMove != nil {
+		// The moved sources don't exist yet, so a rollback removes them
+		for _, move := range p.androidMove.Moves {
+			files = append(files, move.To)
+		}
+	}
+	if p.newPackage != "" {
+		files = append(files, "pubspec.yaml")
+		files = append(files, p.dartFiles...)
+	}
+
+	seen := make(map[string]bool)
+	unique := []string{}
+	for _, file := range files {
+		if !seen[file] {
+			seen[file] = true
+			unique = append(unique, file)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// currentNames returns the app name of every available platform
+func currentNames(projectPath string) (map[string]string, error) {
+	result, err := namer.GetAppNames(projectPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	for _, info := range result.AppNames {
+		if info.Available && info.Error == "" {
+			names[string(info.Platform)] = info.DisplayName
+		}
+	}
+	return names, nil
+}
+
+// currentBundleIDs returns the bundle ID of every available platform
+func currentBundleIDs(projectPath string) (map[string]string, error) {
+	result, err := bundler.GetBundleIDs(projectPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bundleIDs := make(map[string]string)
+	for _, info := range result.BundleIDs {
+		if info.Available && info.Error == "" {
+			bundleIDs[string(info.Platform)] = info.BundleID
+		}
+	}
+	return bundleIDs, nil
+}
+
+// resolveValues applies a universal value to every platform in current, then the
+// per-platform overrides, which must name platforms the project has
+func resolveValues(key, universal string, overrides map[string]string, current map[string]string) (map[string]string, error) {
+	values := make(map[string]string)
+	if universal != "" {
+		for platform := range current {
+			values[platform] = universal
+		}
+	}
+
+	for platform, value := range overrides {
+		platform = strings.ToLower(platform)
+		if !isKnownPlatform(platform) {
+			return nil, fmt.Errorf("%s: unknown platform %q. Valid platforms are: android, ios, macos, linux, windows, web", key, platform)
+		}
+		if _, ok := current[platform]; !ok {
+			return nil, fmt.Errorf("%s: the project has no %s platform", key, platform)
+		}
+		if value == "" {
+			return nil, fmt.Errorf("%s: empty value for %s", key, platform)
+		}
+		values[platform] = value
+	}
+
+	return values, nil
+}
+
+// collapse returns a single value if every platform has the same one, or the per-platform values
+func collapse(values map[string]string) (string, map[string]string) {
+	var universal string
+	for _, value := range values {
+		if universal == "" {
+			universal = value
+		} else if value != universal {
+			return "", values
+		}
+	}
+	return universal, nil
+}
+
+// sortedKeys returns platform keys in the order namer lists platforms
+func sortedKeys(values map[string]string) []string {
+	var keys []string
+	for _, platform := range namer.AllPlatforms() {
+		if _, ok := values[string(platform)]; ok {
+			keys = append(keys, string(platform))
+		}
+	}
+	return keys
+}
+
+// isKnownPlatform reports whether a platform name is supported
+func isKnownPlatform(platform string) bool {
+	for _, known := range namer.AllPlatforms() {
+		if string(known) == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinaryNamePlatform reports whether a platform stores both its app name and bundle ID in BINARY_NAME
+func isBinaryNamePlatform(platform string) bool {
+	return platform == string(namer.PlatformLinux) || platform == string(namer.PlatformWindows)
+}
+
+// backupFiles copies files to the backup directory, replacing any earlier backup.
+// It returns the files that don't exist yet, which a rollback has to remove.
+func backupFiles(projectPath string, files []string, backupDir string) (map[string]bool, error) {
+	if err := os.RemoveAll(backupDir); err != nil {
+		return nil, err
+	}
+
+	missing := make(map[string]bool)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(file)))
+		if os.IsNotExist(err) {
+			missing[file] = true
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		backupPath := filepath.Join(backupDir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(backupPath, data, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return missing, nil
+}
+
+// restoreFiles puts back every backed up file and removes the ones that didn't exist before
+func restoreFiles(projectPath string, files []string, missing map[string]bool, backupDir string) error {
+	var failed []string
+	for _, file := range files {
+		path := filepath.Join(projectPath, filepath.FromSlash(file))
+		if missing[file] {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				failed = append(failed, file)
+			}
+			removeEmptyParents(projectPath, filepath.Dir(path))
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(backupDir, filepath.FromSlash(file)))
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0755)
+		}
+		if err == nil {
+			err = os.WriteFile(path, data, 0644)
+		}
+		if err != nil {
+			failed = append(failed, file)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not restore %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// removeEmptyParents removes dir and its parents while they are empty, stopping at the project
+func removeEmptyParents(projectPath, dir string) {
+	for current := dir; current != projectPath && strings.HasPrefix(current, projectPath); current = filepath.Dir(current) {
+		if entries, err := os.ReadDir(current); err != nil || len(entries) > 0 || os.Remove(current) != nil {
+			return
+		}
+	}
+}
+
+// readPackageName returns the name in pubspec.yaml
+func readPackageName(projectPath string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "pubspec.yaml"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read pubspec.yaml: %v", err)
+	}
+
+	var pubspec struct {
+		Name string `yaml:"name"`
+	}
+	if err := yaml.Unmarshal(data, &pubspec); err != nil {
+		return "", fmt.Errorf("failed to parse pubspec.yaml: %v", err)
+	}
+	if pubspec.Name == "" {
+		return "", fmt.Errorf("pubspec.yaml has no name")
+	}
+
+	return pubspec.Name, nil
+}
+
+// updatePubspecName replaces the name in pubspec.yaml, leaving the rest of the file as it is
+func updatePubspecName(projectPath, name string) error {
+	pubspecPath := filepath.Join(projectPath, "pubspec.yaml")
+	data, err := os.ReadFile(pubspecPath)
+	if err != nil {
+		return err
+	}
+
+	loc := pubspecNameRegex.FindSubmatchIndex(data)
+	if loc == nil {
+		return fmt.Errorf("no top-level name found")
+	}
+
+	updated := append([]byte{}, data[:loc[4]]...)
+	updated = append(updated, name...)
+	updated = append(updated, data[loc[5]:]...)
+
+	return os.WriteFile(pubspecPath, updated, 0644)
+}
+
+// packageImportRegex matches the start of a package: URI for a package
+func packageImportRegex(packageName string) *regexp.Regexp {
+	return regexp.MustCompile(`(['"])package:` + regexp.QuoteMeta(packageName) + `/`)
+}
+
+// findPackageImports finds the Dart files that refer to package:<old>/. Hidden
+// directories, build output and nested packages with their own pubspec.yaml are skipped.
+func findPackageImports(projectPath, oldPackage, newPackage string) ([]Change, error) {
+	re := packageImportRegex(oldPackage)
+	changes := []Change{}
+
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path == projectPath {
+				return nil
+			}
+			name := info.Name()
+			if strings.HasPrefix(name, ".") || name == "build" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "pubspec.yaml")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) != ".dart" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if count := len(re.FindAllIndex(data, -1)); count > 0 {
+			relPath, _ := filepath.Rel(projectPath, path)
+			changes = append(changes, Change{
+				Field: FieldImports,
+				File:  filepath.ToSlash(relPath),
+				From:  "package:" + oldPackage + "/",
+				To:    "package:" + newPackage + "/",
+				Count: count,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan Dart files: %v", err)
+	}
+
+	return changes, nil
+}
+
+// rewritePackageImports replaces package:<old>/ with package:<new>/ in a Dart file
+func rewritePackageImports(path, oldPackage, newPackage string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	updated := packageImportRegex(oldPackage).ReplaceAll(data, []byte("${1}package:"+newPackage+"/"))
+	return os.WriteFile(path, updated, 0644)
+}
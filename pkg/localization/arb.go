@@ -0,0 +1,586 @@
+package localization
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// L10nConfigFile is the gen-l10n configuration file in the project root
+	L10nConfigFile = "l10n.yaml"
+
+	// DefaultARBDir is the default directory for ARB files
+	DefaultARBDir = "lib/l10n"
+
+	// DefaultARBTemplateFile is the default template ARB file name
+	DefaultARBTemplateFile = "app_en.arb"
+)
+
+var (
+	// arbKeyRegex matches keys gen-l10n can turn into Dart getters
+	arbKeyRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+	// arbPlaceholderRegex matches simple {name} placeholders and the argument of ICU {name, plural, ...} messages
+	arbPlaceholderRegex = regexp.MustCompile(`\{\s*([a-zA-Z][a-zA-Z0-9_]*)\s*[,}]`)
+
+	// arbLocaleRegex matches a locale as written in ARB file names, e.g. pt_BR or zh_Hant_TW
+	arbLocaleRegex = regexp.MustCompile(`^` + arbLocalePattern + `$`)
+
+	// arbFileNameRegex matches <prefix>_<locale>.arb file names
+	arbFileNameRegex = regexp.MustCompile(`^.+_(` + arbLocalePattern + `)\.arb$`)
+)
+
+// arbLocalePattern is a language code with optional script and region subtags
+const arbLocalePattern = `[a-z]{2,3}(?:_[A-Z][a-z]{3})?(?:_(?:[A-Z]{2}|[0-9]{3}))?`
+
+// l10nConfig holds the parts of l10n.yaml fdawg needs
+type l10nConfig struct {
+	ARBDir                 string `yaml:"arb-dir"`
+	TemplateARBFile        string `yaml:"template-arb-file"`
+	OutputLocalizationFile string `yaml:"output-localization-file"`
+}
+
+// arbBackend stores flat ARB files for Flutter's gen-l10n tool
+type arbBackend struct {
+	projectPath string
+	config      l10nConfig
+}
+
+// newARBBackend creates the ARB backend for a project, reading l10n.yaml if present
+func newARBBackend(projectPath string) Backend {
+	config := l10nConfig{
+		ARBDir:                 DefaultARBDir,
+		TemplateARBFile:        DefaultARBTemplateFile,
+		OutputLocalizationFile: "app_localizations.dart",
+	}
+
+	if data, err := os.ReadFile(filepath.Join(projectPath, L10nConfigFile)); err == nil {
+		var fileConfig l10nConfig
+		if err := yaml.Unmarshal(data, &fileConfig); err == nil {
+			if fileConfig.ARBDir != "" {
+				config.ARBDir = fileConfig.ARBDir
+			}
+			if fileConfig.TemplateARBFile != "" {
+				config.TemplateARBFile = fileConfig.TemplateARBFile
+			}
+			if fileConfig.OutputLocalizationFile != "" {
+				config.OutputLocalizationFile = fileConfig.OutputLocalizationFile
+			}
+		}
+	}
+
+	return &arbBackend{projectPath: projectPath, config: config}
+}
+
+// Name returns the backend identifier
+func (b *arbBackend) Name() string {
+	return BackendARB
+}
+
+// Detect reports whether the project has an l10n.yaml or ARB files
+func (b *arbBackend) Detect() bool {
+	if _, err := os.Stat(filepath.Join(b.projectPath, L10nConfigFile)); err == nil {
+		return true
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(b.TranslationsDir(), "*.arb"))
+	return len(matches) > 0
+}
+
+// Init creates the template ARB file, l10n.yaml and the gen-l10n pubspec settings
+func (b *arbBackend) Init() error {
+	if err := utils.EnsureDirExists(b.TranslationsDir()); err != nil {
+		return fmt.Errorf("failed to create ARB directory: %v", err)
+	}
+
+	templatePath := filepath.Join(b.TranslationsDir(), b.config.TemplateARBFile)
+	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
+		if b.DefaultLanguage() == "" {
+			return fmt.Errorf("template ARB file %s has no locale in its name; name it <prefix>_<locale>.arb, e.g. app_en.arb", b.config.TemplateARBFile)
+		}
+
+		defaultTranslations := map[string]interface{}{
+			"@@locale": b.DefaultLanguage(),
+			"appTitle": "Flutter App",
+			"@appTitle": map[string]interface{}{
+				"description": "The title of the application",
+			},
+			"welcome": "Welcome to Flutter!",
+		}
+
+		if err := writeARBFile(templatePath, defaultTranslations); err != nil {
+			return fmt.Errorf("failed to create template ARB file: %v", err)
+		}
+	}
+
+	configPath := filepath.Join(b.projectPath, L10nConfigFile)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		content := fmt.Sprintf("arb-dir: %s\ntemplate-arb-file: %s\noutput-localization-file: %s\n",
+			b.config.ARBDir, b.config.TemplateARBFile, b.config.OutputLocalizationFile)
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", L10nConfigFile, err)
+		}
+	}
+
+	if err := updatePubspecForGenL10n(b.projectPath); err != nil {
+		return fmt.Errorf("failed to update pubspec.yaml: %v", err)
+	}
+
+	if err := updateIOSInfoPlistForLocalization(b.projectPath, b.DefaultLanguage()); err != nil {
+		utils.Info("Warning: Failed to update iOS Info.plist: %v", err)
+		// Don't fail the initialization if iOS config fails
+	}
+
+	return nil
+}
+
+// Status checks the ARB directory, files and gen-l10n pubspec settings
+func (b *arbBackend) Status() InitializationStatus {
+	status := InitializationStatus{Backend: b.Name()}
+
+	if _, err := os.Stat(b.TranslationsDir()); err == nil {
+		status.HasTranslationsDir = true
+	}
+
+	if status.HasTranslationsDir {
+		translationFiles, err := listTranslationFiles(b)
+		if err == nil && len(translationFiles) > 0 {
+			status.HasTranslationFiles = true
+		}
+	}
+
+	if pubspecData, err := os.ReadFile(filepath.Join(b.projectPath, "pubspec.yaml")); err == nil {
+		var pubspec struct {
+			Dependencies map[string]interface{} `yaml:"dependencies"`
+			Flutter      struct {
+				Generate bool `yaml:"generate"`
+			} `yaml:"flutter"`
+		}
+		if err := yaml.Unmarshal(pubspecData, &pubspec); err == nil {
+			_, hasFlutterLocalizations := pubspec.Dependencies["flutter_localizations"]
+			status.HasGenL10n = hasFlutterLocalizations && pubspec.Flutter.Generate
+		}
+	}
+
+	status.HasIOSConfig = hasIOSLocalizationConfig(b.projectPath)
+
+	status.IsInitialized = status.HasTranslationsDir &&
+		status.HasTranslationFiles &&
+		status.HasGenL10n
+
+	return status
+}
+
+// TranslationsDir returns the ARB directory from l10n.yaml
+func (b *arbBackend) TranslationsDir() string {
+	return filepath.Join(b.projectPath, filepath.FromSlash(b.config.ARBDir))
+}
+
+// DefaultLanguage returns the locale of the template ARB file
+func (b *arbBackend) DefaultLanguage() string {
+	templatePath := filepath.Join(b.TranslationsDir(), b.config.TemplateARBFile)
+	if locale := readARBLocale(templatePath); locale != "" {
+		return locale
+	}
+	return arbLocaleFromFileName(b.config.TemplateARBFile)
+}
+
+// FilePath returns the ARB file for a language: the existing file whose locale it
+// is, or else a file named after the template, e.g. app_es.arb
+func (b *arbBackend) FilePath(language string) string {
+	if entries, err := os.ReadDir(b.TranslationsDir()); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if locale, ok := b.LanguageFromFile(entry.Name()); ok && locale == language {
+				return filepath.Join(b.TranslationsDir(), entry.Name())
+			}
+		}
+	}
+	return filepath.Join(b.TranslationsDir(), b.filePrefix()+language+".arb")
+}
+
+// LanguageFromFile returns the locale of an ARB file: its @@locale when set, as
+// gen-l10n uses that over the file name, or else the locale in the file name
+func (b *arbBackend) LanguageFromFile(fileName string) (string, bool) {
+	if !strings.HasSuffix(fileName, ".arb") {
+		return "", false
+	}
+
+	if locale := readARBLocale(filepath.Join(b.TranslationsDir(), fileName)); locale != "" {
+		return locale, true
+	}
+
+	prefix := b.filePrefix()
+	if strings.HasPrefix(fileName, prefix) {
+		if locale := strings.TrimSuffix(strings.TrimPrefix(fileName, prefix), ".arb"); arbLocaleRegex.MatchString(locale) {
+			return locale, true
+		}
+	}
+
+	locale := arbLocaleFromFileName(fileName)
+	return locale, locale != ""
+}
+
+// ValidateKey checks that a key is a valid Dart identifier, since ARB keys are flat
+func (b *arbBackend) ValidateKey(key string) error {
+	if !arbKeyRegex.MatchString(key) {
+		return fmt.Errorf("invalid ARB key: %s (ARB keys must be Dart identifiers without dots, e.g., 'welcomeMessage')", key)
+	}
+	return nil
+}
+
+// UpdateKeyMetadata declares the placeholders used by the default language value
+// in the template ARB file, as gen-l10n needs them to generate typed methods
+func (b *arbBackend) UpdateKeyMetadata(key string, values map[string]string) error {
+	names := extractARBPlaceholders(values[b.DefaultLanguage()])
+	if len(names) == 0 {
+		return nil
+	}
+
+	templatePath := filepath.Join(b.TranslationsDir(), b.config.TemplateARBFile)
+	entries, err := readARBEntries(templatePath)
+	if err != nil {
+		return err
+	}
+
+	metadata, _ := entries["@"+key].(map[string]interface{})
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	placeholders, _ := metadata["placeholders"].(map[string]interface{})
+	if placeholders == nil {
+		placeholders = make(map[string]interface{})
+	}
+
+	// gen-l10n needs a numeric type for the argument of plural messages
+	pluralArgument := ""
+	if message := ParseMessage(values[b.DefaultLanguage()]); message.Kind == MessagePlural {
+		pluralArgument = message.Argument
+	}
+
+	changed := false
+	for _, name := range names {
+		if _, ok := placeholders[name]; !ok {
+			if name == pluralArgument {
+				placeholders[name] = map[string]interface{}{"type": "int"}
+			} else {
+				placeholders[name] = map[string]interface{}{}
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	metadata["placeholders"] = placeholders
+	entries["@"+key] = metadata
+
+	return writeARBEntries(templatePath, entries)
+}
+
+// FormatMessage stores plural and gender messages as ICU strings
+func (b *arbBackend) FormatMessage(message Message) interface{} {
+	return FormatICUMessage(message)
+}
+
+// SetupHint returns the reminder to fetch dependencies and generate localizations
+func (b *arbBackend) SetupHint() string {
+	return "Run 'flutter pub get' and 'flutter gen-l10n' to generate AppLocalizations"
+}
+
+// filePrefix returns the part of the template file name before its locale, e.g. app_.
+// A template without a locale in its name, such as app.arb, gives app_.
+func (b *arbBackend) filePrefix() string {
+	name := strings.TrimSuffix(b.config.TemplateARBFile, ".arb")
+	locale := arbLocaleFromFileName(b.config.TemplateARBFile)
+	if locale == "" {
+		return name + "_"
+	}
+	return strings.TrimSuffix(name, locale)
+}
+
+// arbLocaleFromFileName returns the locale of an ARB file named <prefix>_<locale>.arb,
+// e.g. app_pt_BR.arb gives pt_BR. Names without a prefix, such as app.arb, give an
+// empty string.
+func arbLocaleFromFileName(fileName string) string {
+	match := arbFileNameRegex.FindStringSubmatch(fileName)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// extractARBPlaceholders returns the placeholder names used in an ARB message
+func extractARBPlaceholders(value string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	// Look inside plural and select forms rather than mistaking a form body for a placeholder
+	if message, ok := parseICUMessage(value); ok {
+		seen[message.Argument] = true
+		names = append(names, message.Argument)
+
+		value = ""
+		for _, form := range SortForms(message.Forms) {
+			value += message.Forms[form]
+		}
+	}
+
+	for _, match := range arbPlaceholderRegex.FindAllStringSubmatch(value, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// readARBLocale returns the @@locale of an ARB file, or an empty string
+func readARBLocale(path string) string {
+	entries, err := readARBEntries(path)
+	if err != nil {
+		return ""
+	}
+	locale, _ := entries["@@locale"].(string)
+	return locale
+}
+
+// readARBEntries reads every entry of an ARB file, including metadata
+func readARBEntries(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	var entries map[string]interface{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse ARB: %v", err)
+	}
+	if entries == nil {
+		entries = make(map[string]interface{})
+	}
+
+	return entries, nil
+}
+
+// readARBFile reads the message values of an ARB file, leaving out metadata
+func readARBFile(path string) (map[string]interface{}, error) {
+	entries, err := readARBEntries(path)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make(map[string]interface{})
+	for key, value := range entries {
+		if !strings.HasPrefix(key, "@") {
+			translations[key] = value
+		}
+	}
+
+	return translations, nil
+}
+
+// readARBMetadata returns the @key metadata objects of an ARB file, keyed by message key
+func readARBMetadata(path string) (map[string]map[string]interface{}, error) {
+	entries, err := readARBEntries(path)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]map[string]interface{})
+	for key, value := range entries {
+		if !strings.HasPrefix(key, "@") || strings.HasPrefix(key, "@@") {
+			continue
+		}
+		if object, ok := value.(map[string]interface{}); ok {
+			metadata[strings.TrimPrefix(key, "@")] = object
+		}
+	}
+
+	return metadata, nil
+}
+
+// writeARBFile writes message values to an ARB file. Existing metadata is kept
+// for messages that remain, and @@locale is filled in from the file name for new files.
+func writeARBFile(path string, data map[string]interface{}) error {
+	entries := make(map[string]interface{})
+
+	if existing, err := readARBEntries(path); err == nil {
+		for key, value := range existing {
+			if strings.HasPrefix(key, "@@") {
+				entries[key] = value
+			} else if strings.HasPrefix(key, "@") {
+				if _, ok := data[strings.TrimPrefix(key, "@")]; ok {
+					entries[key] = value
+				}
+			}
+		}
+	}
+
+	for key, value := range data {
+		entries[key] = value
+	}
+
+	if _, ok := entries["@@locale"]; !ok {
+		if locale := arbLocaleFromFileName(filepath.Base(path)); locale != "" {
+			entries["@@locale"] = locale
+		}
+	}
+
+	return writeARBEntries(path, entries)
+}
+
+// writeARBEntries writes ARB entries with global @@ entries first and each
+// message followed by its @metadata, as gen-l10n and translators expect. Entries
+// keep their order in the existing file, and new ones are appended in sorted order.
+func writeARBEntries(path string, entries map[string]interface{}) error {
+	if err := utils.EnsureDirExists(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	// A file that can't be read yet has no order to keep
+	var existingKeys []string
+	if order, err := readKeyOrder(path); err == nil {
+		existingKeys = order.keys
+	}
+
+	var globals, messages, newGlobals, newMessages []string
+	placed := make(map[string]bool)
+	for _, key := range existingKeys {
+		if _, ok := entries[key]; !ok || placed[key] {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(key, "@@"):
+			globals = append(globals, key)
+		case strings.HasPrefix(key, "@"):
+			continue
+		default:
+			messages = append(messages, key)
+		}
+		placed[key] = true
+	}
+	for key := range entries {
+		switch {
+		case placed[key]:
+		case strings.HasPrefix(key, "@@"):
+			newGlobals = append(newGlobals, key)
+		case strings.HasPrefix(key, "@"):
+			// Written after its message; orphaned metadata is dropped
+		default:
+			newMessages = append(newMessages, key)
+		}
+	}
+	sort.Strings(newGlobals)
+	sort.Strings(newMessages)
+	globals = append(globals, newGlobals...)
+	messages = append(messages, newMessages...)
+
+	var ordered []string
+	ordered = append(ordered, globals...)
+	for _, key := range messages {
+		ordered = append(ordered, key)
+		if _, ok := entries["@"+key]; ok {
+			ordered = append(ordered, "@"+key)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, key := range ordered {
+		keyJSON, err := encodeJSONValue(key, "")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		valueJSON, err := encodeJSONValue(entries[key], "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  ")
+		buf.Write(keyJSON)
+		buf.WriteString(": ")
+		buf.Write(valueJSON)
+	}
+	buf.WriteString("\n}\n")
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+
+	return nil
+}
+
+// encodeJSONValue encodes a value as indented JSON without HTML escaping
+func encodeJSONValue(value interface{}, prefix string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent(prefix, "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// updatePubspecForGenL10n adds the flutter_localizations and intl dependencies
+// and enables code generation in the flutter section of pubspec.yaml
+func updatePubspecForGenL10n(projectPath string) error {
+	pubspecPath := filepath.Join(projectPath, "pubspec.yaml")
+
+	pubspecData, err := os.ReadFile(pubspecPath)
+	if err != nil {
+		return fmt.Errorf("failed to read pubspec.yaml: %v", err)
+	}
+
+	lines := strings.Split(string(pubspecData), "\n")
+
+	// insertAfter adds lines after the first top-level line matching the section header
+	insertAfter := func(section string, newLines ...string) bool {
+		for i, line := range lines {
+			if strings.TrimRight(line, " \t\r") == section {
+				lines = append(lines[:i+1], append(newLines, lines[i+1:]...)...)
+				return true
+			}
+		}
+		return false
+	}
+
+	content := string(pubspecData)
+	if strings.Contains(content, "flutter_localizations:") {
+		utils.Info("flutter_localizations dependency already exists in pubspec.yaml")
+	} else if !insertAfter("dependencies:", "  flutter_localizations:", "    sdk: flutter") {
+		return fmt.Errorf("could not find dependencies section in pubspec.yaml")
+	}
+
+	if !regexp.MustCompile(`(?m)^\s+intl:`).MatchString(content) {
+		insertAfter("dependencies:", "  intl: any")
+	}
+
+	if regexp.MustCompile(`(?m)^\s+generate:\s*true`).MatchString(content) {
+		utils.Info("gen-l10n code generation already enabled in pubspec.yaml")
+	} else if !insertAfter("flutter:", "  generate: true") {
+		lines = append(lines, "flutter:", "  generate: true")
+	}
+
+	if err := os.WriteFile(pubspecPath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to write pubspec.yaml: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,345 @@
+package localization
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// KeyUsage is a translation key used in a Dart file
+type KeyUsage struct {
+	Key string `json:"key"`
+	// File is the Dart file path relative to the project
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// AuditReport compares the keys used in Dart code with the keys in the default language
+type AuditReport struct {
+	DefaultLanguage string `json:"defaultLanguage"`
+	FilesScanned    int    `json:"filesScanned"`
+	Keys            int    `json:"keys"`
+	UsedKeys        int    `json:"usedKeys"`
+	// UnusedKeys exist in the translations but are never used in code
+	UnusedKeys []string `json:"unusedKeys"`
+	// MissingKeys are used in code but missing from the default language
+	MissingKeys []KeyUsage `json:"missingKeys"`
+	// DynamicUsages build keys at runtime, e.g. 'errors.$code'.tr(). Keys starting
+	// with their static prefix are treated as used.
+	DynamicUsages []KeyUsage `json:"dynamicUsages"`
+	// UnresolvedUsages translate a key held in a variable or returned by a call,
+	// e.g. titleKey.tr(). Key holds the source line.
+	UnresolvedUsages []KeyUsage `json:"unresolvedUsages"`
+}
+
+// dartStringLiteral matches a single or double quoted Dart string on one line
+const dartStringLiteral = `(?:'([^'\n]*)'|"([^"\n]*)")`
+
+var (
+	// 'key'.tr(), "key".plural(n) and 'key'.trArgs(...)
+	trExtensionRegex = regexp.MustCompile(dartStringLiteral + `\s*\.\s*(?:tr|plural|trArgs)\s*\(`)
+
+	// tr('key'), plural('key', n) and context.tr('key')
+	trFunctionRegex = regexp.MustCompile(`\b(?:tr|plural)\s*\(\s*` + dartStringLiteral)
+
+	// Text('key').tr()
+	textWidgetRegex = regexp.MustCompile(`\bText\s*\(\s*` + dartStringLiteral + `\s*\)\s*\.\s*tr\s*\(`)
+
+	// LocaleKeys.app_title or LocaleKeys.app.title
+	localeKeysRegex = regexp.MustCompile(`\bLocaleKeys((?:\s*\.\s*[a-zA-Z_][a-zA-Z0-9_]*)+)`)
+
+	// static const app_title = 'app.title'; in a generated LocaleKeys class, where
+	// the key may hold escapes such as \' or \$
+	localeKeysConstRegex = regexp.MustCompile(`static\s+const\s+(?:String\s+)?([a-zA-Z_][a-zA-Z0-9_]*)\s*=\s*(?:'((?:[^'\\\n]|\\.)*)'|"((?:[^"\\\n]|\\.)*)")`)
+
+	// Any tr, plural or trArgs call, whatever its receiver or arguments
+	trCallRegex = regexp.MustCompile(`\b(?:tr|plural|trArgs)\s*\(`)
+
+	// The receiver of a call, e.g. widget.titleKey or context
+	receiverChainRegex = regexp.MustCompile(`[a-zA-Z_$][a-zA-Z0-9_$]*(?:\s*\.\s*[a-zA-Z_$][a-zA-Z0-9_$]*)*$`)
+
+	// Text('key') ending a receiver
+	textWidgetReceiverRegex = regexp.MustCompile(`\bText\s*\(\s*` + dartStringLiteral + `\s*\)$`)
+
+	// The last word before a call, to tell calls from declarations such as String tr(
+	trailingWordRegex = regexp.MustCompile(`[a-zA-Z0-9_$]+$`)
+)
+
+// callKeywords can precede a call without making it a declaration
+var callKeywords = []string{"return", "await", "yield", "throw", "else", "case", "in"}
+
+// AuditTranslationKeys scans lib/**/*.dart for easy_localization usages and compares
+// them with the keys in the default language file
+func AuditTranslationKeys(projectPath string) (*AuditReport, error) {
+	backend := DetectBackend(projectPath)
+	if backend.Name() != BackendEasyLocalization {
+		return nil, fmt.Errorf("audit supports easy_localization projects; gen-l10n reports missing keys as Dart compile errors")
+	}
+
+	defaultPath := backend.FilePath(backend.DefaultLanguage())
+	defaultData, err := readTranslationFile(defaultPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read default translations: %v. Please run 'fdawg lang init' first to initialize localization", err)
+	}
+
+	keys := MessageKeys(defaultData)
+	known := make(map[string]bool)
+	for _, key := range keys {
+		known[key] = true
+	}
+
+	report := &AuditReport{
+		DefaultLanguage:  backend.DefaultLanguage(),
+		Keys:             len(keys),
+		UnusedKeys:       []string{},
+		MissingKeys:      []KeyUsage{},
+		DynamicUsages:    []KeyUsage{},
+		UnresolvedUsages: []KeyUsage{},
+	}
+
+	// Generated LocaleKeys classes map constant names to keys
+	localeKeys := make(map[string]string)
+	err = scanDartFiles(projectPath, func(relPath, content string) error {
+		if strings.Contains(content, "class LocaleKeys") {
+			for _, match := range localeKeysConstRegex.FindAllStringSubmatch(content, -1) {
+				if key, ok := unescapeDartString(match[2] + match[3]); ok {
+					localeKeys[match[1]] = key
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []KeyUsage
+	err = scanDartFiles(projectPath, func(relPath, content string) error {
+		// The generated LocaleKeys helpers call .tr() for every key with placeholders
+		if relPath == LocaleKeysFile {
+			return nil
+		}
+		report.FilesScanned++
+		usages = append(usages, findKeyUsages(relPath, content, localeKeys, known)...)
+		report.UnresolvedUsages = append(report.UnresolvedUsages, findUnresolvedUsages(relPath, content)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	var dynamicPrefixes []string
+	for _, usage := range usages {
+		// Interpolated keys can't be resolved, so treat keys sharing their static prefix as used
+		if index := strings.Index(usage.Key, "$"); index >= 0 {
+			report.DynamicUsages = append(report.DynamicUsages, usage)
+			dynamicPrefixes = append(dynamicPrefixes, usage.Key[:index])
+			continue
+		}
+
+		if known[usage.Key] {
+			used[usage.Key] = true
+		} else {
+			report.MissingKeys = append(report.MissingKeys, usage)
+		}
+	}
+
+	for _, key := range keys {
+		if used[key] {
+			continue
+		}
+		dynamic := false
+		for _, prefix := range dynamicPrefixes {
+			if prefix != "" && strings.HasPrefix(key, prefix) {
+				dynamic = true
+				break
+			}
+		}
+		if dynamic {
+			used[key] = true
+			continue
+		}
+		report.UnusedKeys = append(report.UnusedKeys, key)
+	}
+	report.UsedKeys = len(used)
+
+	return report, nil
+}
+
+// findKeyUsages returns the translation keys used in a Dart file
+func findKeyUsages(relPath, content string, localeKeys map[string]string, known map[string]bool) []KeyUsage {
+	var usages []KeyUsage
+
+	add := func(key string, offset int) {
+		usages = append(usages, KeyUsage{
+			Key:  key,
+			File: relPath,
+			Line: strings.Count(content[:offset], "\n") + 1,
+		})
+	}
+
+	seen := make(map[int]bool)
+	for _, regex := range []*regexp.Regexp{trExtensionRegex, trFunctionRegex, textWidgetRegex} {
+		for _, match := range regex.FindAllStringSubmatchIndex(content, -1) {
+			// The literal is the first non-empty quote group
+			start, end := match[2], match[3]
+			if start < 0 {
+				start, end = match[4], match[5]
+			}
+			if start < 0 || seen[start] {
+				continue
+			}
+			seen[start] = true
+			add(content[start:end], start)
+		}
+	}
+
+	for _, match := range localeKeysRegex.FindAllStringSubmatchIndex(content, -1) {
+		reference := strings.Join(strings.Fields(content[match[2]:match[3]]), "")
+		reference = strings.TrimPrefix(reference, ".")
+		add(resolveLocaleKey(reference, localeKeys, known), match[0])
+	}
+
+	return usages
+}
+
+// findUnresolvedUsages returns the tr, plural and trArgs calls in a Dart file whose
+// key isn't a string literal or a LocaleKeys reference, so it can't be audited
+func findUnresolvedUsages(relPath, content string) []KeyUsage {
+	var usages []KeyUsage
+
+	for _, match := range trCallRegex.FindAllStringIndex(content, -1) {
+		before := strings.TrimRight(content[:match[0]], " \t\r\n")
+		arguments := strings.TrimLeft(content[match[1]:], " \t\r\n")
+		literalArgument := strings.HasPrefix(arguments, "'") || strings.HasPrefix(arguments, `"`) || strings.HasPrefix(arguments, ")")
+
+		if strings.HasSuffix(before, ".") {
+			// Extension call on a receiver, or context.tr(...)
+			receiver := strings.TrimRight(strings.TrimSuffix(before, "."), " \t\r\n")
+			if strings.HasSuffix(receiver, "'") || strings.HasSuffix(receiver, `"`) || textWidgetReceiverRegex.MatchString(receiver) {
+				continue
+			}
+			if chain := receiverChainRegex.FindString(receiver); chain != "" {
+				segments := strings.Split(strings.Join(strings.Fields(chain), ""), ".")
+				if segments[0] == "LocaleKeys" {
+					continue
+				}
+				if segments[len(segments)-1] == "context" && literalArgument {
+					continue
+				}
+			}
+		} else {
+			// Function call tr(...) or plural(...), unless it's a declaration
+			if word := trailingWordRegex.FindString(before); word != "" && !containsString(callKeywords, word) {
+				continue
+			}
+			if literalArgument {
+				continue
+			}
+		}
+
+		lineStart := strings.LastIndex(content[:match[0]], "\n") + 1
+		lineEnd := strings.Index(content[match[0]:], "\n")
+		if lineEnd < 0 {
+			lineEnd = len(content)
+		} else {
+			lineEnd += match[0]
+		}
+		usages = append(usages, KeyUsage{
+			Key:  strings.TrimSpace(content[lineStart:lineEnd]),
+			File: relPath,
+			Line: strings.Count(content[:match[0]], "\n") + 1,
+		})
+	}
+
+	return usages
+}
+
+// resolveLocaleKey maps a LocaleKeys reference to a translation key. The reference
+// may be followed by method calls such as .tr, so the longest prefix that resolves
+// wins. Generated constants are looked up first, then keys with dots written as underscores.
+// Typed helpers such as LocaleKeys.app.greetingTr(...) resolve to the key they translate.
+func resolveLocaleKey(reference string, localeKeys map[string]string, known map[string]bool) string {
+	segments := strings.Split(reference, ".")
+	for n := len(segments); n > 0; n-- {
+		candidate := strings.Join(segments[:n], ".")
+		for _, name := range []string{candidate, strings.TrimSuffix(candidate, localeKeysHelperSuffix)} {
+			if key, ok := localeKeys[name]; ok {
+				return key
+			}
+			if known[name] {
+				return name
+			}
+			for key := range known {
+				if strings.ReplaceAll(key, ".", "_") == name {
+					return key
+				}
+			}
+		}
+	}
+	return "LocaleKeys." + segments[0]
+}
+
+// scanDartFiles calls fn with the path relative to the project and the contents
+// of every Dart file under lib/
+func scanDartFiles(projectPath string, fn func(relPath, content string) error) error {
+	libDir := filepath.Join(projectPath, "lib")
+	if _, err := os.Stat(libDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	err := filepath.Walk(libDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".dart" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", path, err)
+		}
+
+		relPath, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return err
+		}
+
+		return fn(filepath.ToSlash(relPath), string(data))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan Dart files: %v", err)
+	}
+
+	return nil
+}
+
+// PruneUnusedKeys deletes the unused keys of an audit report from every language,
+// returning the keys that were deleted. Keys built at runtime or held in variables
+// may use any of them, so unless force is set nothing is deleted when the report
+// has dynamic or unresolved usages.
+func PruneUnusedKeys(projectPath string, report *AuditReport, force bool) ([]string, error) {
+	if !force {
+		if count := len(report.DynamicUsages) + len(report.UnresolvedUsages); count > 0 {
+			return nil, fmt.Errorf("%d call(s) translate keys built at runtime or held in variables, so unused keys can't be told apart reliably; check them and prune with force", count)
+		}
+	}
+
+	sorted := append([]string{}, report.UnusedKeys...)
+	sort.Strings(sorted)
+
+	var deleted []string
+	for _, key := range sorted {
+		if err := DeleteTranslationKey(projectPath, key); err != nil {
+			return deleted, fmt.Errorf("failed to delete %s: %v", key, err)
+		}
+		deleted = append(deleted, key)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,285 @@
+package localization
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+)
+
+const (
+	// BackendEasyLocalization stores translations as nested JSON for the easy_localization package
+	BackendEasyLocalization = "easy_localization"
+
+	// BackendARB stores translations as ARB files for Flutter's gen-l10n tool
+	BackendARB = "arb"
+)
+
+// Backend abstracts how a localization system lays out and stores its
+// translation files. A backend is bound to a single project.
+type Backend interface {
+	// Name returns the backend identifier, e.g. easy_localization or arb
+	Name() string
+
+	// Detect reports whether the project is already set up for this backend
+	Detect() bool
+
+	// Init sets up the translation files and dependencies in the project
+	Init() error
+
+	// Status reports how far localization has been set up
+	Status() InitializationStatus
+
+	// TranslationsDir returns the directory holding the translation files
+	TranslationsDir() string
+
+	// DefaultLanguage returns the language that other languages are derived from
+	DefaultLanguage() string
+
+	// FilePath returns the path of the translation file for a language
+	FilePath(language string) string
+
+	// LanguageFromFile returns the language of a translation file name,
+	// or false if the file isn't a translation file for this backend
+	LanguageFromFile(fileName string) (string, bool)
+
+	// ValidateKey checks that a translation key can be stored by this backend
+	ValidateKey(key string) error
+
+	// UpdateKeyMetadata refreshes any metadata the backend keeps for a key,
+	// such as ARB placeholder declarations
+	UpdateKeyMetadata(key string, values map[string]string) error
+
+	// FormatMessage converts a message to the value stored in a translation file
+	FormatMessage(message Message) interface{}
+
+	// SetupHint returns a short instruction for finishing setup after Init
+	SetupHint() string
+}
+
+// backendFactories lists the available backends in detection order
+var backendFactories = []struct {
+	name    string
+	factory func(projectPath string) Backend
+}{
+	{BackendEasyLocalization, newEasyLocalizationBackend},
+	{BackendARB, newARBBackend},
+}
+
+// BackendNames returns the names of all available backends
+func BackendNames() []string {
+	var names []string
+	for _, entry := range backendFactories {
+		names = append(names, entry.name)
+	}
+	return names
+}
+
+// NewBackend returns the named backend for a project
+func NewBackend(projectPath, name string) (Backend, error) {
+	for _, entry := range backendFactories {
+		if entry.name == name {
+			return entry.factory(projectPath), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown localization backend: %s (available: %s)", name, strings.Join(BackendNames(), ", "))
+}
+
+// DetectBackend returns the backend the project is set up for, falling back to
+// easy_localization for projects without localization
+func DetectBackend(projectPath string) Backend {
+	for _, entry := range backendFactories {
+		backend := entry.factory(projectPath)
+		if backend.Detect() {
+			return backend
+		}
+	}
+	return newEasyLocalizationBackend(projectPath)
+}
+
+// GetTranslationsDir returns the directory holding the project's translation files
+func GetTranslationsDir(projectPath string) string {
+	return DetectBackend(projectPath).TranslationsDir()
+}
+
+// GetDefaultLanguage returns the project's default language code
+func GetDefaultLanguage(projectPath string) string {
+	return DetectBackend(projectPath).DefaultLanguage()
+}
+
+// GetTranslationFilePath returns the path of the translation file for a language
+func GetTranslationFilePath(projectPath, language string) string {
+	return DetectBackend(projectPath).FilePath(language)
+}
+
+// easyLocalizationBackend stores nested JSON files in assets/translations
+type easyLocalizationBackend struct {
+	projectPath string
+}
+
+// newEasyLocalizationBackend creates the easy_localization backend for a project
+func newEasyLocalizationBackend(projectPath string) Backend {
+	return &easyLocalizationBackend{projectPath: projectPath}
+}
+
+// Name returns the backend identifier
+func (b *easyLocalizationBackend) Name() string {
+	return BackendEasyLocalization
+}
+
+// Detect reports whether the project uses easy_localization
+func (b *easyLocalizationBackend) Detect() bool {
+	if _, err := os.Stat(b.TranslationsDir()); err == nil {
+		return true
+	}
+
+	pubspecData, err := os.ReadFile(filepath.Join(b.projectPath, "pubspec.yaml"))
+	return err == nil && strings.Contains(string(pubspecData), "easy_localization:")
+}
+
+// Init creates the default translation file and adds the easy_localization dependency
+func (b *easyLocalizationBackend) Init() error {
+	// Create translations directory
+	translationsPath := b.TranslationsDir()
+	if err := utils.EnsureDirExists(translationsPath); err != nil {
+		return fmt.Errorf("failed to create translations directory: %v", err)
+	}
+
+	// Create default translation file (en_US.json)
+	defaultLang := b.DefaultLanguage()
+	defaultTranslationPath := b.FilePath(defaultLang)
+
+	// Check if the file already exists
+	if _, err := os.Stat(defaultTranslationPath); os.IsNotExist(err) {
+		// Create empty translation file with a welcome message
+		defaultTranslations := map[string]interface{}{
+			"app": map[string]interface{}{
+				"title":   "Flutter App",
+				"welcome": "Welcome to Flutter!",
+			},
+		}
+
+		// Write the file
+		if err := writeTranslationFile(defaultTranslationPath, defaultTranslations); err != nil {
+			return fmt.Errorf("failed to create default translation file: %v", err)
+		}
+	}
+
+	// Update pubspec.yaml to add easy_localization dependency and assets
+	if err := updatePubspecForLocalization(b.projectPath); err != nil {
+		return fmt.Errorf("failed to update pubspec.yaml: %v", err)
+	}
+
+	// Update iOS Info.plist for localization
+	if err := updateIOSInfoPlistForLocalization(b.projectPath, defaultLang); err != nil {
+		utils.Info("Warning: Failed to update iOS Info.plist: %v", err)
+		// Don't fail the initialization if iOS config fails
+	}
+
+	return nil
+}
+
+// Status checks the translations directory, files and easy_localization dependency
+func (b *easyLocalizationBackend) Status() InitializationStatus {
+	status := InitializationStatus{Backend: b.Name()}
+
+	// Check if translations directory exists
+	if _, err := os.Stat(b.TranslationsDir()); err == nil {
+		status.HasTranslationsDir = true
+	}
+
+	// Check if translation files exist
+	if status.HasTranslationsDir {
+		translationFiles, err := listTranslationFiles(b)
+		if err == nil && len(translationFiles) > 0 {
+			status.HasTranslationFiles = true
+		}
+	}
+
+	// Check if pubspec.yaml has easy_localization dependency
+	pubspecPath := filepath.Join(b.projectPath, "pubspec.yaml")
+	if pubspecData, err := os.ReadFile(pubspecPath); err == nil {
+		pubspecContent := string(pubspecData)
+		if strings.Contains(pubspecContent, "easy_localization:") {
+			status.HasEasyLocalization = true
+		}
+	}
+
+	status.HasIOSConfig = hasIOSLocalizationConfig(b.projectPath)
+
+	// Determine if fully initialized (iOS config is optional)
+	status.IsInitialized = status.HasTranslationsDir &&
+		status.HasTranslationFiles &&
+		status.HasEasyLocalization
+
+	return status
+}
+
+// TranslationsDir returns the assets/translations directory
+func (b *easyLocalizationBackend) TranslationsDir() string {
+	return filepath.Join(b.projectPath, TranslationsDir)
+}
+
+// DefaultLanguage returns en_US
+func (b *easyLocalizationBackend) DefaultLanguage() string {
+	return fmt.Sprintf("%s_%s", DefaultLanguage, DefaultCountry)
+}
+
+// FilePath returns the JSON file for a language, e.g. assets/translations/es.json
+func (b *easyLocalizationBackend) FilePath(language string) string {
+	return filepath.Join(b.TranslationsDir(), language+".json")
+}
+
+// LanguageFromFile returns the language of a JSON file name
+func (b *easyLocalizationBackend) LanguageFromFile(fileName string) (string, bool) {
+	if !strings.HasSuffix(fileName, ".json") {
+		return "", false
+	}
+	return strings.TrimSuffix(fileName, ".json"), true
+}
+
+// ValidateKey checks that a key uses dot notation
+func (b *easyLocalizationBackend) ValidateKey(key string) error {
+	if !isValidTranslationKey(key) {
+		return fmt.Errorf("invalid translation key format: %s (use dot notation, e.g., 'category.subcategory.key')", key)
+	}
+	return nil
+}
+
+// UpdateKeyMetadata does nothing, as easy_localization files have no metadata
+func (b *easyLocalizationBackend) UpdateKeyMetadata(key string, values map[string]string) error {
+	return nil
+}
+
+// FormatMessage stores plural and gender messages as easy_localization objects,
+// e.g. {"one": "1 item", "other": "{} items"}
+func (b *easyLocalizationBackend) FormatMessage(message Message) interface{} {
+	if message.Kind == MessageText {
+		return message.Text
+	}
+
+	forms := make(map[string]interface{})
+	for form, text := range message.Forms {
+		forms[form] = text
+	}
+	// An object with only other can't be told from a group of keys, so languages
+	// that need no other form, e.g. Japanese, get an empty one form too
+	if _, hasOne := forms["one"]; message.Kind == MessagePlural && len(forms) == 1 && !hasOne {
+		forms["one"] = ""
+	}
+	return forms
+}
+
+// SetupHint returns the pub get reminder for easy_localization
+func (b *easyLocalizationBackend) SetupHint() string {
+	return "Run 'flutter pub get' to install the easy_localization package"
+}
+
+// hasIOSLocalizationConfig checks if the iOS Info.plist lists supported localizations
+func hasIOSLocalizationConfig(projectPath string) bool {
+	iosInfoPlistPath := filepath.Join(projectPath, "ios", "Runner", "Info.plist")
+	infoPlistData, err := os.ReadFile(iosInfoPlistPath)
+	return err == nil && strings.Contains(string(infoPlistData), "CFBundleLocalizations")
+}
@@ -0,0 +1,293 @@
+package localization
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/flutter"
+)
+
+// LocaleKeysFile is the generated LocaleKeys class, relative to the project
+const LocaleKeysFile = "lib/config/locale_keys.dart"
+
+// localeKeysHelperSuffix is appended to a key's member name for its typed translation helper
+const localeKeysHelperSuffix = "Tr"
+
+// dartReservedWords can't be used as member or parameter names
+var dartReservedWords = map[string]bool{
+	"assert": true, "break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "else": true, "enum": true, "extends": true,
+	"false": true, "final": true, "finally": true, "for": true, "if": true, "in": true,
+	"is": true, "new": true, "null": true, "rethrow": true, "return": true, "super": true,
+	"switch": true, "this": true, "throw": true, "true": true, "try": true, "var": true,
+	"void": true, "while": true, "with": true,
+}
+
+// localeKeyNode is a segment of the dotted key tree. Leaves have a key, other
+// nodes become nested classes.
+type localeKeyNode struct {
+	name     string
+	path     string
+	key      string
+	value    interface{}
+	children []*localeKeyNode
+}
+
+// GenerateDartLocaleKeysFile generates lib/config/locale_keys.dart with a LocaleKeys
+// class for the keys in the default language, e.g. LocaleKeys.app.title
+func GenerateDartLocaleKeysFile(projectPath string) error {
+	backend := DetectBackend(projectPath)
+	if backend.Name() != BackendEasyLocalization {
+		return fmt.Errorf("LocaleKeys generation supports easy_localization projects; gen-l10n generates AppLocalizations itself")
+	}
+
+	defaultData, err := readTranslationFile(backend.FilePath(backend.DefaultLanguage()))
+	if err != nil {
+		return fmt.Errorf("failed to read default translations: %v. Please run 'fdawg lang init' first to initialize localization", err)
+	}
+
+	// Build the key tree from the sorted keys so the output is stable
+	root := &localeKeyNode{}
+	for _, key := range MessageKeys(defaultData) {
+		value, _ := getTranslationValue(defaultData, key)
+		node := root
+		parts := strings.Split(key, ".")
+		for i, part := range parts[:len(parts)-1] {
+			var next *localeKeyNode
+			for _, child := range node.children {
+				if child.name == part && child.key == "" {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				next = &localeKeyNode{name: part, path: strings.Join(parts[:i+1], ".")}
+				node.children = append(node.children, next)
+			}
+			node = next
+		}
+		node.children = append(node.children, &localeKeyNode{name: parts[len(parts)-1], path: key, key: key, value: value})
+	}
+
+	var body strings.Builder
+	usedClassNames := map[string]bool{"LocaleKeys": true}
+	hasHelpers := addLocaleKeysClass(&body, "LocaleKeys", root, true, usedClassNames)
+
+	var content strings.Builder
+
+	// Add file header
+	content.WriteString(`// GENERATED CODE - DO NOT MODIFY BY HAND
+// Generated by fdawg
+
+// Member names follow the translation keys
+// ignore_for_file: constant_identifier_names, non_constant_identifier_names
+
+`)
+	if hasHelpers {
+		content.WriteString("import 'package:easy_localization/easy_localization.dart';\n\n")
+	}
+	content.WriteString(`/// Translation keys
+///
+/// This class provides access to the keys defined in the translation files.
+/// It is automatically generated by fdawg and should not be modified manually.
+`)
+	content.WriteString(body.String())
+
+	// Write the file
+	dartFilePath := filepath.Join(projectPath, filepath.FromSlash(LocaleKeysFile))
+
+	// Ensure the directory exists
+	if err := os.MkdirAll(filepath.Dir(dartFilePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	if err := os.WriteFile(dartFilePath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write Dart file: %v", err)
+	}
+
+	return nil
+}
+
+// addLocaleKeysClass adds a class for a node of the key tree, followed by the classes
+// of its subtrees. The root class uses static members; nested classes are const
+// instances so keys read as LocaleKeys.app.title. Returns whether any typed helper
+// was generated.
+func addLocaleKeysClass(content *strings.Builder, className string, node *localeKeyNode, root bool, usedClassNames map[string]bool) bool {
+	if root {
+		content.WriteString(fmt.Sprintf("class %s {\n  // Private constructor to prevent instantiation\n  %s._();\n\n", className, className))
+	} else {
+		content.WriteString(fmt.Sprintf("/// %s keys\nclass %s {\n  const %s._();\n\n", node.path, className, className))
+	}
+
+	// Member names must be unique within a class, including helper names
+	usedNames := make(map[string]bool)
+	memberNames := make([]string, len(node.children))
+	for i, child := range node.children {
+		memberNames[i] = uniqueDartName(dartMemberName(child.name), usedNames)
+	}
+
+	hasHelpers := false
+	subClassNames := make(map[*localeKeyNode]string)
+	for i, child := range node.children {
+		name := memberNames[i]
+
+		if child.key == "" {
+			subClassName := uniqueDartName(className+pascalCase(child.name), usedClassNames)
+			subClassNames[child] = subClassName
+			content.WriteString(fmt.Sprintf("  /// %s keys\n", child.path))
+			if root {
+				content.WriteString(fmt.Sprintf("  static const %s %s = %s._();\n\n", subClassName, name, subClassName))
+			} else {
+				content.WriteString(fmt.Sprintf("  %s get %s => const %s._();\n\n", subClassName, name, subClassName))
+			}
+			continue
+		}
+
+		doc := localeKeyDoc(child)
+		content.WriteString(doc)
+		if root {
+			content.WriteString(fmt.Sprintf("  static const String %s = '%s';\n\n", name, escapeDartString(child.key)))
+		} else {
+			content.WriteString(fmt.Sprintf("  String get %s => '%s';\n\n", name, escapeDartString(child.key)))
+		}
+
+		if helper := localeKeyHelper(child, uniqueDartName(name+localeKeysHelperSuffix, usedNames)); helper != "" {
+			hasHelpers = true
+			content.WriteString(doc)
+			if root {
+				content.WriteString("  static ")
+			} else {
+				content.WriteString("  ")
+			}
+			content.WriteString(helper + "\n\n")
+		}
+	}
+
+	content.WriteString("}\n\n")
+
+	// Add the nested classes for each subtree
+	for _, child := range node.children {
+		if child.key == "" {
+			if addLocaleKeysClass(content, subClassNames[child], child, false, usedClassNames) {
+				hasHelpers = true
+			}
+		}
+	}
+
+	return hasHelpers
+}
+
+// localeKeyHelper returns a typed translation method for keys with placeholders,
+// plural or gender forms, or "" for plain text keys
+func localeKeyHelper(node *localeKeyNode, name string) string {
+	message := ParseMessage(node.value)
+	placeholders := extractPlaceholders(node.value)
+
+	var named []string
+	for placeholder := range placeholders.named {
+		named = append(named, placeholder)
+	}
+	sort.Strings(named)
+
+	// Parameter names must be unique and can't clash with count and gender
+	usedParams := make(map[string]bool)
+	var params, callArgs []string
+
+	switch message.Kind {
+	case MessagePlural:
+		// easy_localization fills {} in plural forms with the count
+		params = append(params, "num "+uniqueDartName("count", usedParams))
+		placeholders.positional = 0
+	case MessageGender:
+		params = append(params, "String "+uniqueDartName("gender", usedParams))
+		callArgs = append(callArgs, "gender: gender")
+	}
+
+	if placeholders.positional > 0 {
+		var args []string
+		for i := 1; i <= placeholders.positional; i++ {
+			arg := uniqueDartName(fmt.Sprintf("arg%d", i), usedParams)
+			params = append(params, "String "+arg)
+			args = append(args, arg)
+		}
+		callArgs = append(callArgs, "args: ["+strings.Join(args, ", ")+"]")
+	}
+
+	if len(named) > 0 {
+		var namedParams, namedArgs []string
+		for _, placeholder := range named {
+			param := uniqueDartName(dartMemberName(placeholder), usedParams)
+			namedParams = append(namedParams, "required String "+param)
+			namedArgs = append(namedArgs, fmt.Sprintf("'%s': %s", escapeDartString(placeholder), param))
+		}
+		params = append(params, "{"+strings.Join(namedParams, ", ")+"}")
+		callArgs = append(callArgs, "namedArgs: {"+strings.Join(namedArgs, ", ")+"}")
+	}
+
+	if message.Kind == MessageText && len(params) == 0 {
+		return ""
+	}
+
+	if message.Kind == MessagePlural {
+		callArgs = append([]string{"count"}, callArgs...)
+		return fmt.Sprintf("String %s(%s) => '%s'.plural(%s);", name, strings.Join(params, ", "), escapeDartString(node.key), strings.Join(callArgs, ", "))
+	}
+
+	return fmt.Sprintf("String %s(%s) => '%s'.tr(%s);", name, strings.Join(params, ", "), escapeDartString(node.key), strings.Join(callArgs, ", "))
+}
+
+// escapeDartString escapes text for a single-quoted Dart string
+func escapeDartString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "'", `\'`, "$", `\$`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
+
+// localeKeyDoc returns a doc comment with the default language text of a key
+func localeKeyDoc(node *localeKeyNode) string {
+	text := strings.TrimSpace(messageText(ParseMessage(node.value)))
+	if text == "" {
+		return fmt.Sprintf("  /// %s\n", node.key)
+	}
+
+	var doc strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		doc.WriteString(strings.TrimRight("  /// "+line, " ") + "\n")
+	}
+	return doc.String()
+}
+
+// dartMemberName keeps a key segment as is where possible, so members match the
+// keys they stand for, e.g. LocaleKeys.app.welcome_message
+func dartMemberName(segment string) string {
+	name := flutter.EnsureValidDartIdentifier(segment)
+	// Leading underscores would make the member private to the generated library
+	if strings.HasPrefix(name, "_") {
+		name = "k" + name
+	}
+	if dartReservedWords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// pascalCase converts a key segment to a class name suffix, e.g. welcome_screen to WelcomeScreen
+func pascalCase(segment string) string {
+	name := flutter.FormatDartVariableName(segment)
+	name = strings.TrimPrefix(name, "_")
+	if name == "" {
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// uniqueDartName appends a numeric suffix to name until it isn't taken, and marks it as used
+func uniqueDartName(name string, usedNames map[string]bool) string {
+	candidate := name
+	for i := 2; usedNames[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	usedNames[candidate] = true
+	return candidate
+}
@@ -0,0 +1,526 @@
+package localization
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// ExportCSV exports one row per key and one column per language
+	ExportCSV = "csv"
+
+	// ExportXLSX exports the same table as CSV as an Excel workbook
+	ExportXLSX = "xlsx"
+
+	// ExportXLIFF exports an XLIFF 1.2 document with one file per target language, for CAT tools
+	ExportXLIFF = "xliff"
+)
+
+// utf8BOM makes spreadsheet apps such as Excel read CSV files as UTF-8
+const utf8BOM = "\xef\xbb\xbf"
+
+// ExportOptions controls which translations are exported and how
+type ExportOptions struct {
+	Format string
+	// MissingOnly exports only keys missing a translation in at least one language
+	MissingOnly bool
+}
+
+// ImportConflict is an imported value that differs from the value already in the project
+type ImportConflict struct {
+	Key      string `json:"key"`
+	Language string `json:"language"`
+	Current  string `json:"current"`
+	Imported string `json:"imported"`
+}
+
+// ImportResult summarizes an import
+type ImportResult struct {
+	Format    string   `json:"format"`
+	Languages []string `json:"languages"`
+	// Updated counts values that were filled in, or overwritten when Overwrite is set
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	Conflicts []ImportConflict `json:"conflicts"`
+	// UnknownKeys and UnknownLanguages are in the file but not in the project, and are skipped
+	UnknownKeys      []string `json:"unknownKeys"`
+	UnknownLanguages []string `json:"unknownLanguages"`
+	DryRun           bool     `json:"dryRun"`
+}
+
+// ImportOptions controls how imported values are merged
+type ImportOptions struct {
+	// Overwrite replaces existing values that differ from the imported ones
+	Overwrite bool
+	// DryRun reports what would change without writing any files
+	DryRun bool
+}
+
+// ExportFormats returns the supported export formats
+func ExportFormats() []string {
+	return []string{ExportCSV, ExportXLSX, ExportXLIFF}
+}
+
+// ExportFileName returns the default file name for an export format
+func ExportFileName(format string) string {
+	if format == ExportXLIFF {
+		return "translations.xlf"
+	}
+	return "translations." + format
+}
+
+// ExportTranslations exports the project's translations. Nested keys are flattened
+// to dot paths, and plural and gender messages are written one "form: text" per line.
+func ExportTranslations(projectPath string, options ExportOptions) ([]byte, error) {
+	backend := DetectBackend(projectPath)
+
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+	// Pseudo-locales are generated from the default language, not translated
+	translationFiles = withoutPseudoLocales(translationFiles)
+
+	if len(translationFiles) == 0 {
+		return nil, fmt.Errorf("no translation files found. Please run 'fdawg lang init' first to initialize localization")
+	}
+
+	languages, keys, values := translationTable(translationFiles, backend.DefaultLanguage())
+
+	if options.MissingOnly {
+		var missing []string
+		for _, key := range keys {
+			for _, language := range languages {
+				if strings.TrimSpace(values[language][key]) == "" {
+					missing = append(missing, key)
+					break
+				}
+			}
+		}
+		keys = missing
+	}
+
+	switch options.Format {
+	case ExportCSV:
+		return exportCSV(languages, keys, values)
+	case ExportXLSX:
+		return writeXLSX("Translations", exportRows(languages, keys, values))
+	case ExportXLIFF:
+		return exportXLIFF(languages, keys, values)
+	}
+
+	return nil, fmt.Errorf("unsupported export format: %s (supported: %s)", options.Format, strings.Join(ExportFormats(), ", "))
+}
+
+// translationTable flattens translation files to dot-path keys and display values.
+// The default language comes first, followed by the other languages in order.
+func translationTable(translationFiles []TranslationFile, defaultLanguage string) ([]string, []string, map[string]map[string]string) {
+	var languages []string
+	keySet := make(map[string]bool)
+	values := make(map[string]map[string]string)
+
+	for _, file := range translationFiles {
+		languages = append(languages, file.Language)
+		values[file.Language] = make(map[string]string)
+
+		for _, key := range MessageKeys(file.Data) {
+			keySet[key] = true
+			value, _ := getTranslationValue(file.Data, key)
+			values[file.Language][key] = messageText(ParseMessage(value))
+		}
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		if languages[i] == defaultLanguage || languages[j] == defaultLanguage {
+			return languages[i] == defaultLanguage
+		}
+		return languages[i] < languages[j]
+	})
+
+	var keys []string
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return languages, keys, values
+}
+
+// exportRows returns a header row of "key" and language codes, then one row per key
+func exportRows(languages, keys []string, values map[string]map[string]string) [][]string {
+	rows := [][]string{append([]string{"key"}, languages...)}
+	for _, key := range keys {
+		row := []string{key}
+		for _, language := range languages {
+			row = append(row, values[language][key])
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// exportCSV writes the translation table as UTF-8 CSV
+func exportCSV(languages, keys []string, values map[string]map[string]string) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(utf8BOM)
+
+	writer := csv.NewWriter(&buffer)
+	if err := writer.WriteAll(exportRows(languages, keys, values)); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %v", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// xliffDocument is an XLIFF 1.2 document
+type xliffDocument struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string      `xml:"version,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+// xliffFile holds the translation units for one target language
+type xliffFile struct {
+	Original       string      `xml:"original,attr"`
+	SourceLanguage string      `xml:"source-language,attr"`
+	TargetLanguage string      `xml:"target-language,attr"`
+	Datatype       string      `xml:"datatype,attr"`
+	Units          []xliffUnit `xml:"body>trans-unit"`
+}
+
+// xliffUnit is a single translation key
+type xliffUnit struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source"`
+	Target string `xml:"target"`
+}
+
+// exportXLIFF writes an XLIFF 1.2 document with one <file> per target language
+func exportXLIFF(languages, keys []string, values map[string]map[string]string) ([]byte, error) {
+	if len(languages) < 2 {
+		return nil, fmt.Errorf("XLIFF export needs at least one language besides %s", languages[0])
+	}
+
+	source := languages[0]
+	document := xliffDocument{Version: "1.2"}
+
+	for _, language := range languages[1:] {
+		file := xliffFile{
+			Original:       "fdawg",
+			SourceLanguage: toBCP47(source),
+			TargetLanguage: toBCP47(language),
+			Datatype:       "plaintext",
+		}
+		for _, key := range keys {
+			file.Units = append(file.Units, xliffUnit{
+				ID:     key,
+				Source: values[source][key],
+				Target: values[language][key],
+			})
+		}
+		document.Files = append(document.Files, file)
+	}
+
+	output, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write XLIFF: %v", err)
+	}
+
+	return append([]byte(xml.Header), append(output, '\n')...), nil
+}
+
+// ImportTranslations merges translations from a CSV, XLSX or XLIFF file into the project.
+// Empty imported values are ignored, values that differ from existing ones are reported
+// as conflicts unless Overwrite is set, and keys missing from the file are left untouched.
+func ImportTranslations(projectPath, path string, options ImportOptions) (*ImportResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return ImportTranslationData(projectPath, filepath.Base(path), data, options)
+}
+
+// ImportTranslationData merges translations from file contents, using the file name to detect the format
+func ImportTranslationData(projectPath, fileName string, data []byte, options ImportOptions) (*ImportResult, error) {
+	format, imported, err := parseImportFile(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := DetectBackend(projectPath)
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+	// Pseudo-locales are generated from the default language, not translated
+	translationFiles = withoutPseudoLocales(translationFiles)
+
+	if len(translationFiles) == 0 {
+		return nil, fmt.Errorf("no translation files found. Please run 'fdawg lang init' first to initialize localization")
+	}
+
+	result := &ImportResult{
+		Format:           format,
+		Conflicts:        []ImportConflict{},
+		UnknownKeys:      []string{},
+		UnknownLanguages: []string{},
+		DryRun:           options.DryRun,
+	}
+
+	// Match imported language codes to the project's, accepting en-US for en_US
+	filesByLanguage := make(map[string]TranslationFile)
+	for _, file := range translationFiles {
+		filesByLanguage[strings.ToLower(file.Language)] = file
+	}
+
+	// Keys must already exist in the default language, so typos don't create new keys
+	defaultKeys := make(map[string]bool)
+	for _, file := range translationFiles {
+		if file.Language == backend.DefaultLanguage() {
+			for _, key := range MessageKeys(file.Data) {
+				defaultKeys[key] = true
+			}
+		}
+	}
+
+	unknownKeys := make(map[string]bool)
+	changedKeys := make(map[string]bool)
+
+	var importedLanguages []string
+	for language := range imported {
+		importedLanguages = append(importedLanguages, language)
+	}
+	sort.Strings(importedLanguages)
+
+	for _, importedLanguage := range importedLanguages {
+		file, ok := filesByLanguage[strings.ToLower(strings.ReplaceAll(importedLanguage, "-", "_"))]
+		if !ok {
+			result.UnknownLanguages = append(result.UnknownLanguages, importedLanguage)
+			continue
+		}
+		result.Languages = append(result.Languages, file.Language)
+
+		var keys []string
+		for key := range imported[importedLanguage] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			text := imported[importedLanguage][key]
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+
+			if !defaultKeys[key] {
+				unknownKeys[key] = true
+				continue
+			}
+
+			currentValue, _ := getTranslationValue(file.Data, key)
+			current := ParseMessage(currentValue)
+			currentText := messageText(current)
+
+			// Plural and gender keys are merged form by form, so filling in
+			// empty forms isn't a conflict
+			var value interface{} = text
+			conflict := strings.TrimSpace(currentText) != ""
+			if kind, argument := keyMessageKind(translationFiles, key); kind != MessageText {
+				var merged Message
+				merged, conflict = mergeForms(current, ParseFormsText(kind, argument, text))
+				text = FormatFormsText(merged)
+				value = backend.FormatMessage(merged)
+			}
+
+			if normalizeLineEndings(currentText) == normalizeLineEndings(text) {
+				result.Unchanged++
+				continue
+			}
+
+			if conflict && !options.Overwrite {
+				result.Conflicts = append(result.Conflicts, ImportConflict{
+					Key:      key,
+					Language: file.Language,
+					Current:  currentText,
+					Imported: imported[importedLanguage][key],
+				})
+				continue
+			}
+
+			result.Updated++
+			if options.DryRun {
+				continue
+			}
+
+			if err := setKeyInTranslationFile(file.Path, key, value); err != nil {
+				return nil, fmt.Errorf("failed to import %s for %s: %v", key, file.Language, err)
+			}
+			if file.Language == backend.DefaultLanguage() {
+				changedKeys[key] = true
+			}
+		}
+	}
+
+	for key := range unknownKeys {
+		result.UnknownKeys = append(result.UnknownKeys, key)
+	}
+	sort.Strings(result.UnknownKeys)
+
+	// Keep backend metadata such as ARB placeholders in sync with new default values
+	if len(changedKeys) > 0 {
+		defaultFile := backend.FilePath(backend.DefaultLanguage())
+		data, err := readTranslationFile(defaultFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read default translations: %v", err)
+		}
+		for key := range changedKeys {
+			value, _ := getTranslationValue(data, key)
+			values := map[string]string{backend.DefaultLanguage(): FormatICUMessage(ParseMessage(value))}
+			if err := backend.UpdateKeyMetadata(key, values); err != nil {
+				return nil, fmt.Errorf("failed to update metadata for %s: %v", key, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// keyMessageKind returns whether a key holds plural or gender messages in any language
+func keyMessageKind(translationFiles []TranslationFile, key string) (string, string) {
+	messages := make(map[string]Message)
+	for _, file := range translationFiles {
+		if value, ok := getTranslationValue(file.Data, key); ok {
+			messages[file.Language] = ParseMessage(value)
+		}
+	}
+	return MessageKind(messages)
+}
+
+// mergeForms overlays the non-empty imported forms on the current ones, and
+// reports a conflict if any non-empty form would change
+func mergeForms(current, imported Message) (Message, bool) {
+	merged := Message{Kind: imported.Kind, Argument: imported.Argument, Forms: make(map[string]string)}
+	if current.Kind == imported.Kind {
+		merged.Argument = current.Argument
+		for form, text := range current.Forms {
+			merged.Forms[form] = text
+		}
+	}
+
+	conflict := false
+	for form, text := range imported.Forms {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if existing := merged.Forms[form]; strings.TrimSpace(existing) != "" && normalizeLineEndings(existing) != normalizeLineEndings(text) {
+			conflict = true
+		}
+		merged.Forms[form] = text
+	}
+
+	return merged, conflict
+}
+
+// parseImportFile reads a CSV, XLSX or XLIFF file into language → key → text
+func parseImportFile(fileName string, data []byte) (string, map[string]map[string]string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM))))
+		reader.FieldsPerRecord = -1
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read CSV: %v", err)
+		}
+		imported, err := parseImportRows(rows)
+		return ExportCSV, imported, err
+	case ".xlsx":
+		rows, err := readXLSX(data)
+		if err != nil {
+			return "", nil, err
+		}
+		imported, err := parseImportRows(rows)
+		return ExportXLSX, imported, err
+	case ".xlf", ".xliff":
+		imported, err := parseXLIFF(data)
+		return ExportXLIFF, imported, err
+	}
+
+	return "", nil, fmt.Errorf("unsupported import file: %s (use .csv, .xlsx, .xlf or .xliff)", fileName)
+}
+
+// parseImportRows reads a table with a "key" column and one column per language
+func parseImportRows(rows [][]string) (map[string]map[string]string, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the file is empty")
+	}
+
+	header := rows[0]
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "key") {
+		return nil, fmt.Errorf("the first row must be a header starting with \"key\" followed by language codes")
+	}
+
+	imported := make(map[string]map[string]string)
+	for _, language := range header[1:] {
+		if language = strings.TrimSpace(language); language != "" {
+			imported[language] = make(map[string]string)
+		}
+	}
+
+	for _, row := range rows[1:] {
+		if len(row) == 0 || strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		key := strings.TrimSpace(row[0])
+		for i, language := range header[1:] {
+			language = strings.TrimSpace(language)
+			if language != "" && i+1 < len(row) {
+				imported[language][key] = row[i+1]
+			}
+		}
+	}
+
+	return imported, nil
+}
+
+// parseXLIFF reads the targets of an XLIFF 1.2 document
+func parseXLIFF(data []byte) (map[string]map[string]string, error) {
+	var document xliffDocument
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to read XLIFF: %v", err)
+	}
+
+	imported := make(map[string]map[string]string)
+	for _, file := range document.Files {
+		if file.TargetLanguage == "" {
+			continue
+		}
+		if imported[file.TargetLanguage] == nil {
+			imported[file.TargetLanguage] = make(map[string]string)
+		}
+		for _, unit := range file.Units {
+			imported[file.TargetLanguage][unit.ID] = unit.Target
+		}
+	}
+
+	if len(imported) == 0 {
+		return nil, fmt.Errorf("no target languages found in the XLIFF file")
+	}
+
+	return imported, nil
+}
+
+// toBCP47 converts a language code such as en_US to the en-US form XLIFF uses
+func toBCP47(code string) string {
+	return strings.ReplaceAll(code, "_", "-")
+}
+
+// normalizeLineEndings makes values edited in spreadsheets comparable to the originals
+func normalizeLineEndings(text string) string {
+	return strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+}
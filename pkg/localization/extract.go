@@ -0,0 +1,660 @@
+package localization
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ExtractCandidate is a hard-coded user-facing string found in Dart code
+type ExtractCandidate struct {
+	// File is the Dart file path relative to the project
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Widget is where the string was found: Text, hintText, labelText or title
+	Widget string `json:"widget"`
+	Text   string `json:"text"`
+	// Key is the suggested translation key
+	Key string `json:"key"`
+	// Existing is set when the key already holds the same text, so it's reused
+	Existing bool `json:"existing"`
+	// Skipped explains why the string can't be extracted, e.g. it's in a const declaration
+	Skipped string `json:"skipped,omitempty"`
+
+	// start and end are the byte offsets of the literal, including quotes
+	start   int
+	end     int
+	literal string
+	// constStarts are the offsets of the const keywords of the constructors and
+	// collection literals enclosing the literal, which are dropped as tr() isn't constant
+	constStarts []int
+}
+
+// ExtractResult reports what an extraction changed
+type ExtractResult struct {
+	// Keys lists the keys added to the translations
+	Keys []string `json:"keys"`
+	// Files lists the Dart files rewritten
+	Files        []string `json:"files"`
+	Replacements int      `json:"replacements"`
+}
+
+// extractStringLiteral matches a single or double quoted Dart string on one line, with escapes
+const extractStringLiteral = `(?:'((?:[^'\\\n]|\\.)*)'|"((?:[^"\\\n]|\\.)*)")`
+
+var (
+	// Text('Sign in') and Text("Sign in", ...), but not Text('key').tr()
+	extractTextRegex = regexp.MustCompile(`\bText\s*\(\s*` + extractStringLiteral + `\s*[,)]`)
+
+	// hintText: 'Email', labelText: 'Email' and title: 'Settings'
+	extractNamedRegex = regexp.MustCompile(`\b(hintText|labelText|title)\s*:\s*` + extractStringLiteral + `\s*[,)\n]`)
+
+	// extractKeySuffixes are added to keys suggested for named arguments
+	extractKeySuffixes = map[string]string{
+		"hintText":  "_hint",
+		"labelText": "_label",
+		"title":     "_title",
+	}
+
+	// easyLocalizationImportRegex matches the easy_localization import
+	easyLocalizationImportRegex = regexp.MustCompile(`import\s+['"]package:easy_localization/`)
+
+	// dartCalleeRegex matches the constructor before an argument list, e.g. EdgeInsets.all
+	// or Foo<String>, at the end of the text
+	dartCalleeRegex = regexp.MustCompile(`[A-Za-z_$][\w$]*(?:\s*\.\s*[A-Za-z_$][\w$]*)*\s*(?:<[^;{}()=]*>\s*)?$`)
+
+	// dartTypeArgumentsRegex matches the type arguments of a collection literal, e.g. <Widget>
+	dartTypeArgumentsRegex = regexp.MustCompile(`<[^;{}()=]*>\s*$`)
+
+	// dartConstKeywordRegex matches a const keyword at the end of the text
+	dartConstKeywordRegex = regexp.MustCompile(`\bconst\s*$`)
+
+	// dartConstDeclarationRegex matches the start of a const declaration's value, e.g.
+	// static const List<Widget> tabs =
+	dartConstDeclarationRegex = regexp.MustCompile(`\bconst\s+(?:[\w$<>?,.\s]+\s+)?[A-Za-z_$][\w$]*\s*=\s*$`)
+
+	// dartImportRegex matches an import, export or part directive line
+	dartImportRegex = regexp.MustCompile(`(?m)^(?:import|export|part)\s+['"][^'"]+['"][^;]*;[ \t]*$`)
+)
+
+// easyLocalizationImport is the import added to files that gain tr() calls
+const easyLocalizationImport = "import 'package:easy_localization/easy_localization.dart';"
+
+// FindHardcodedStrings scans lib/**/*.dart, or only the files under path, for string
+// literals in Text widgets and hintText, labelText and title arguments, and suggests
+// a key for each from the file name and the text. Interpolated strings, strings
+// without letters and generated files are skipped.
+func FindHardcodedStrings(projectPath, path string) ([]ExtractCandidate, error) {
+	backend := DetectBackend(projectPath)
+	if backend.Name() != BackendEasyLocalization {
+		return nil, fmt.Errorf("extract supports easy_localization projects, as strings are replaced with 'key'.tr() calls")
+	}
+
+	defaultData, err := readTranslationFile(backend.FilePath(backend.DefaultLanguage()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read default translations: %v. Please run 'fdawg lang init' first to initialize localization", err)
+	}
+
+	prefix := ""
+	if path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		if prefix, err = filepath.Rel(projectPath, absPath); err != nil {
+			return nil, err
+		}
+		prefix = filepath.ToSlash(prefix)
+	}
+
+	candidates := []ExtractCandidate{}
+	err = scanDartFiles(projectPath, func(relPath, content string) error {
+		if prefix != "" && relPath != prefix && !strings.HasPrefix(relPath, strings.TrimSuffix(prefix, "/")+"/") {
+			return nil
+		}
+		if relPath == LocaleKeysFile || strings.HasSuffix(relPath, ".g.dart") || strings.HasSuffix(relPath, ".freezed.dart") {
+			return nil
+		}
+
+		candidates = append(candidates, findFileHardcodedStrings(relPath, content)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].File != candidates[j].File {
+			return candidates[i].File < candidates[j].File
+		}
+		return candidates[i].start < candidates[j].start
+	})
+
+	suggestExtractKeys(candidates, defaultData)
+	return candidates, nil
+}
+
+// findFileHardcodedStrings returns the hard-coded strings in one Dart file
+func findFileHardcodedStrings(relPath, content string) []ExtractCandidate {
+	var candidates []ExtractCandidate
+
+	add := func(widget string, match []int, literalGroup int) bool {
+		start, end := match[literalGroup*2], match[literalGroup*2+1]
+		if start < 0 {
+			start, end = match[literalGroup*2+2], match[literalGroup*2+3]
+		}
+		// Include the quotes
+		start, end = start-1, end+1
+
+		// Raw strings and commented-out code are left alone
+		if start > 0 && content[start-1] == 'r' {
+			return false
+		}
+		lineStart := strings.LastIndex(content[:start], "\n") + 1
+		if strings.HasPrefix(strings.TrimSpace(content[lineStart:start]), "//") {
+			return false
+		}
+
+		literal := content[start:end]
+		text, ok := unescapeDartString(literal[1 : len(literal)-1])
+		if !ok || !hasLetters(text) {
+			return false
+		}
+
+		candidates = append(candidates, ExtractCandidate{
+			File:    relPath,
+			Line:    strings.Count(content[:start], "\n") + 1,
+			Widget:  widget,
+			Text:    text,
+			start:   start,
+			end:     end,
+			literal: literal,
+		})
+		return true
+	}
+
+	for _, match := range extractTextRegex.FindAllStringSubmatchIndex(content, -1) {
+		// Text('key').tr() is already translated
+		rest := strings.TrimLeft(content[match[1]:], " \t\n")
+		if content[match[1]-1] == ')' && strings.HasPrefix(rest, ".tr(") {
+			continue
+		}
+
+		add("Text", match, 1)
+	}
+
+	for _, match := range extractNamedRegex.FindAllStringSubmatchIndex(content, -1) {
+		add(content[match[2]:match[3]], match, 2)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].start < candidates[j].start
+	})
+	offsets := make([]int, len(candidates))
+	for i, candidate := range candidates {
+		offsets[i] = candidate.start
+	}
+	for i, context := range findConstContexts(content, offsets) {
+		candidates[i].constStarts = context.constStarts
+		if context.implicit {
+			candidates[i].Skipped = "in a const declaration or annotation, where tr() can't be used"
+		}
+	}
+
+	return candidates
+}
+
+// constContext lists what makes a position in Dart code constant
+type constContext struct {
+	// constStarts are the offsets of the const keywords of the enclosing constructors
+	// and collection literals
+	constStarts []int
+	// implicit is set inside a const declaration or an annotation, which are constant
+	// without a const keyword that could be dropped
+	implicit bool
+}
+
+// constOpener is an open bracket in Dart code
+type constOpener struct {
+	// constStart is the offset of the const keyword before its constructor or collection literal, or -1
+	constStart int
+	implicit   bool
+}
+
+// findConstContexts returns the const context of each offset, which must be sorted.
+// Brackets are tracked through the whole file, skipping strings and comments, so
+// consts further out such as const Center(child: Text('Hi')) are found too.
+func findConstContexts(content string, offsets []int) []constContext {
+	contexts := make([]constContext, len(offsets))
+	var stack []constOpener
+	next := 0
+
+	record := func(position int) {
+		for ; next < len(offsets) && offsets[next] <= position; next++ {
+			for _, opener := range stack {
+				if opener.constStart >= 0 {
+					contexts[next].constStarts = append(contexts[next].constStarts, opener.constStart)
+				}
+				contexts[next].implicit = contexts[next].implicit || opener.implicit
+			}
+		}
+	}
+
+	for i := 0; i < len(content); {
+		record(i)
+
+		switch {
+		case strings.HasPrefix(content[i:], "//"):
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = len(content) - i
+			}
+			i += end
+			continue
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				i = len(content)
+			} else {
+				i += end + 4
+			}
+			continue
+		case content[i] == '\'' || content[i] == '"':
+			i = skipDartString(content, i)
+			continue
+		case content[i] == '(' || content[i] == '[' || content[i] == '{':
+			stack = append(stack, dartConstOpener(content, i))
+		case content[i] == ')' || content[i] == ']' || content[i] == '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+		i++
+	}
+	record(len(content))
+
+	return contexts
+}
+
+// dartConstOpener works out whether the bracket at open starts a const constructor
+// call or collection literal, or the value of a const declaration or annotation
+func dartConstOpener(content string, open int) constOpener {
+	windowStart := open - 300
+	if windowStart < 0 {
+		windowStart = 0
+	}
+	before := content[windowStart:open]
+
+	if content[open] == '(' {
+		loc := dartCalleeRegex.FindStringIndex(before)
+		if loc == nil {
+			return constOpener{constStart: -1}
+		}
+		before = before[:loc[0]]
+		if strings.HasSuffix(strings.TrimRight(before, " \t"), "@") {
+			return constOpener{constStart: -1, implicit: true}
+		}
+	} else if loc := dartTypeArgumentsRegex.FindStringIndex(before); loc != nil {
+		before = before[:loc[0]]
+	}
+
+	opener := constOpener{constStart: -1}
+	if loc := dartConstKeywordRegex.FindStringIndex(before); loc != nil {
+		opener.constStart = windowStart + loc[0]
+		before = before[:loc[0]]
+	}
+	opener.implicit = dartConstDeclarationRegex.MatchString(before)
+	return opener
+}
+
+// skipDartString returns the offset after the string literal whose opening quote is at start
+func skipDartString(content string, start int) int {
+	raw := start > 0 && content[start-1] == 'r' && (start < 2 || !isDartIdentifierByte(content[start-2]))
+	quote := content[start : start+1]
+	if strings.HasPrefix(content[start:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+
+	for i := start + len(quote); i < len(content); {
+		switch {
+		case strings.HasPrefix(content[i:], quote):
+			return i + len(quote)
+		case len(quote) == 1 && content[i] == '\n':
+			return i
+		case !raw && content[i] == '\\':
+			i += 2
+		case !raw && strings.HasPrefix(content[i:], "${"):
+			i = skipDartInterpolation(content, i+2)
+		default:
+			i++
+		}
+	}
+	return len(content)
+}
+
+// skipDartInterpolation returns the offset after the } closing an interpolation whose body starts at start
+func skipDartInterpolation(content string, start int) int {
+	depth := 1
+	for i := start; i < len(content); {
+		switch content[i] {
+		case '\'', '"':
+			i = skipDartString(content, i)
+			continue
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+		i++
+	}
+	return len(content)
+}
+
+// isDartIdentifierByte reports whether b can be part of a Dart identifier
+func isDartIdentifierByte(b byte) bool {
+	return b == '_' || b == '$' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// unescapeDartString returns the text of a Dart string literal body, or false if it
+// interpolates values with $
+func unescapeDartString(body string) (string, bool) {
+	var text strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c == '$' {
+			return "", false
+		}
+		if c != '\\' || i+1 == len(body) {
+			text.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch body[i] {
+		case 'n':
+			text.WriteByte('\n')
+		case 'r':
+			text.WriteByte('\r')
+		case 't':
+			text.WriteByte('\t')
+		default:
+			text.WriteByte(body[i])
+		}
+	}
+	return text.String(), true
+}
+
+// suggestExtractKeys fills in the key of each candidate. A key already holding the
+// same text in the default language is reused; otherwise the key is
+// <file>.<text slug>, with a suffix for named arguments, e.g. login_screen.email_hint,
+// and a number if that's taken.
+func suggestExtractKeys(candidates []ExtractCandidate, defaultData map[string]interface{}) {
+	// MessageKeys is sorted, so the first key with a text wins
+	textKeys := make(map[string]string)
+	for _, key := range MessageKeys(defaultData) {
+		value, _ := getTranslationValue(defaultData, key)
+		if text, ok := value.(string); ok && text != "" {
+			if _, seen := textKeys[text]; !seen {
+				textKeys[text] = key
+			}
+		}
+	}
+
+	assigned := make(map[string]string)
+	for i := range candidates {
+		candidate := &candidates[i]
+
+		if key, ok := textKeys[candidate.Text]; ok {
+			candidate.Key = key
+			candidate.Existing = true
+			continue
+		}
+
+		fileName := strings.TrimSuffix(filepath.Base(candidate.File), ".dart")
+		base := extractSlug(fileName, 40) + "." + extractSlug(candidate.Text, 32) + extractKeySuffixes[candidate.Widget]
+
+		for n := 1; ; n++ {
+			key := base
+			if n > 1 {
+				key = fmt.Sprintf("%s_%d", base, n)
+			}
+
+			if text, ok := assigned[key]; ok {
+				if text == candidate.Text {
+					candidate.Key = key
+					break
+				}
+				continue
+			}
+
+			existing, err := checkExtractKey(defaultData, key, candidate.Text)
+			if err != nil {
+				continue
+			}
+
+			candidate.Key = key
+			candidate.Existing = existing
+			assigned[key] = candidate.Text
+			break
+		}
+	}
+}
+
+// extractSlug turns text into a snake_case key part of at most maxLength characters,
+// cut at a word boundary
+func extractSlug(text string, maxLength int) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range text {
+		if r == '\'' || r == '’' {
+			// don't → dont
+			continue
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			word.WriteRune(unicode.ToLower(r))
+		} else {
+			flush()
+		}
+	}
+	flush()
+
+	slug := ""
+	for _, word := range words {
+		if slug != "" && len(slug)+1+len(word) > maxLength {
+			break
+		}
+		if slug != "" {
+			slug += "_"
+		}
+		slug += word
+	}
+
+	if slug == "" {
+		return "text"
+	}
+	if len(slug) > maxLength {
+		slug = slug[:maxLength]
+	}
+	if slug[0] >= '0' && slug[0] <= '9' {
+		slug = "text_" + slug
+	}
+	return slug
+}
+
+// ApplyExtraction adds the keys of the candidates to the translations, with their
+// text in the default language, and replaces each literal with a 'key'.tr() call.
+// Files changed since they were scanned are refused before anything is written.
+func ApplyExtraction(projectPath string, candidates []ExtractCandidate) (*ExtractResult, error) {
+	backend := DetectBackend(projectPath)
+	result := &ExtractResult{
+		Keys:  []string{},
+		Files: []string{},
+	}
+
+	byFile := make(map[string][]ExtractCandidate)
+	var files []string
+	for _, candidate := range candidates {
+		if candidate.Skipped != "" {
+			return nil, fmt.Errorf("%s:%d can't be extracted: %s", candidate.File, candidate.Line, candidate.Skipped)
+		}
+		if err := backend.ValidateKey(candidate.Key); err != nil {
+			return nil, err
+		}
+		if _, ok := byFile[candidate.File]; !ok {
+			files = append(files, candidate.File)
+		}
+		byFile[candidate.File] = append(byFile[candidate.File], candidate)
+	}
+	sort.Strings(files)
+
+	// Rewrite every file in memory first
+	contents := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file, err)
+		}
+
+		updated, err := replaceExtractedLiterals(string(data), byFile[file])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		contents[file] = updated
+	}
+
+	defaultData, err := readTranslationFile(backend.FilePath(backend.DefaultLanguage()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read default translations: %v", err)
+	}
+
+	// Keys may have been edited since they were suggested, so check them again
+	texts := make(map[string]string)
+	for i, candidate := range candidates {
+		if text, ok := texts[candidate.Key]; ok && text != candidate.Text {
+			return nil, fmt.Errorf("key %s is used for both %q and %q", candidate.Key, text, candidate.Text)
+		}
+		texts[candidate.Key] = candidate.Text
+
+		existing, err := checkExtractKey(defaultData, candidate.Key, candidate.Text)
+		if err != nil {
+			return nil, err
+		}
+		candidates[i].Existing = existing
+	}
+
+	added := make(map[string]bool)
+	for _, candidate := range candidates {
+		if candidate.Existing || added[candidate.Key] {
+			continue
+		}
+		values := map[string]string{backend.DefaultLanguage(): candidate.Text}
+		if err := InsertTranslationKey(projectPath, candidate.Key, values); err != nil {
+			return result, fmt.Errorf("failed to add %s: %v", candidate.Key, err)
+		}
+		added[candidate.Key] = true
+		result.Keys = append(result.Keys, candidate.Key)
+	}
+
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(projectPath, filepath.FromSlash(file)), []byte(contents[file]), 0644); err != nil {
+			return result, fmt.Errorf("failed to write %s: %v", file, err)
+		}
+		result.Files = append(result.Files, file)
+		result.Replacements += len(byFile[file])
+	}
+
+	return result, nil
+}
+
+// CheckExtractKey reports whether a key can hold an extracted text: it's free, or
+// already holds the same text and is reused, which existing reports
+func CheckExtractKey(projectPath, key, text string) (bool, error) {
+	backend := DetectBackend(projectPath)
+	if err := backend.ValidateKey(key); err != nil {
+		return false, err
+	}
+
+	defaultData, err := readTranslationFile(backend.FilePath(backend.DefaultLanguage()))
+	if err != nil {
+		return false, fmt.Errorf("failed to read default translations: %v", err)
+	}
+	return checkExtractKey(defaultData, key, text)
+}
+
+// checkExtractKey is CheckExtractKey against the default language data
+func checkExtractKey(defaultData map[string]interface{}, key, text string) (bool, error) {
+	if value, ok := getTranslationValue(defaultData, key); ok {
+		if existing, isText := value.(string); isText && existing == text {
+			return true, nil
+		}
+		return false, fmt.Errorf("key %s already exists with a different text", key)
+	}
+	if err := checkKeyAvailable(defaultData, key); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// replaceExtractedLiterals replaces the literals of a file's candidates with 'key'.tr()
+// calls, dropping the const keywords around them as tr() isn't constant, and adds the
+// easy_localization import if it's missing
+func replaceExtractedLiterals(content string, candidates []ExtractCandidate) (string, error) {
+	type edit struct {
+		start, end  int
+		replacement string
+	}
+
+	var edits []edit
+	dropped := make(map[int]bool)
+	for _, candidate := range candidates {
+		if candidate.end > len(content) || content[candidate.start:candidate.end] != candidate.literal {
+			return "", fmt.Errorf("file changed since it was scanned, run extract again")
+		}
+		edits = append(edits, edit{candidate.start, candidate.end, fmt.Sprintf("'%s'.tr()", escapeDartString(candidate.Key))})
+
+		for _, constStart := range candidate.constStarts {
+			if dropped[constStart] {
+				continue
+			}
+			dropped[constStart] = true
+			constEnd := constStart + len("const")
+			for constEnd < len(content) && (content[constEnd] == ' ' || content[constEnd] == '\t' || content[constEnd] == '\n') {
+				constEnd++
+			}
+			edits = append(edits, edit{constStart, constEnd, ""})
+		}
+	}
+
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, edit := range edits {
+		content = content[:edit.start] + edit.replacement + content[edit.end:]
+	}
+
+	if !easyLocalizationImportRegex.MatchString(content) {
+		imports := dartImportRegex.FindAllStringIndex(content, -1)
+		if len(imports) > 0 {
+			end := imports[len(imports)-1][1]
+			content = content[:end] + "\n" + easyLocalizationImport + content[end:]
+		} else {
+			content = easyLocalizationImport + "\n\n" + content
+		}
+	}
+
+	return content, nil
+}
@@ -81,6 +81,13 @@ var CommonLanguageCodes = []LanguageCode{
 // IsValidLanguageCode checks if a language code is valid
 func IsValidLanguageCode(code string) bool {
 	normalizedCode := strings.ToLower(code)
+
+	// Pseudo-locales such as en_XA use a private region
+	for _, pseudo := range PseudoLocales {
+		if strings.ToLower(pseudo) == normalizedCode {
+			return true
+		}
+	}
 	
 	// Check if it's a simple language code (e.g., "en")
 	if len(normalizedCode) == 2 {
@@ -23,105 +23,42 @@ const (
 
 // TranslationFile represents a translation file
 type TranslationFile struct {
-	Path     string                 // Path to the translation file
-	Language string                 // Language code
-	Data     map[string]interface{} // Translation data
+	Path     string                            // Path to the translation file
+	Language string                            // Language code
+	Data     map[string]interface{}            // Translation data
+	Metadata map[string]map[string]interface{} // Per-key metadata, such as ARB @key entries
 }
 
 // InitializationStatus represents the localization initialization status
 type InitializationStatus struct {
+	Backend             string `json:"backend"`
 	IsInitialized       bool   `json:"isInitialized"`
 	HasTranslationsDir  bool   `json:"hasTranslationsDir"`
 	HasTranslationFiles bool   `json:"hasTranslationFiles"`
 	HasEasyLocalization bool   `json:"hasEasyLocalization"`
+	HasGenL10n          bool   `json:"hasGenL10n"`
 	HasIOSConfig        bool   `json:"hasIOSConfig"`
 	ErrorMessage        string `json:"errorMessage,omitempty"`
 }
 
-// InitLocalization initializes localization in a Flutter project
+// InitLocalization initializes localization in a Flutter project using the
+// detected backend, or easy_localization if none is set up yet
 func InitLocalization(projectPath string) error {
-	// Create translations directory
-	translationsPath := filepath.Join(projectPath, TranslationsDir)
-	if err := utils.EnsureDirExists(translationsPath); err != nil {
-		return fmt.Errorf("failed to create translations directory: %v", err)
-	}
-
-	// Create default translation file (en_US.json)
-	defaultLang := fmt.Sprintf("%s_%s", DefaultLanguage, DefaultCountry)
-	defaultTranslationPath := filepath.Join(translationsPath, defaultLang+".json")
-
-	// Check if the file already exists
-	if _, err := os.Stat(defaultTranslationPath); os.IsNotExist(err) {
-		// Create empty translation file with a welcome message
-		defaultTranslations := map[string]interface{}{
-			"app": map[string]interface{}{
-				"title":   "Flutter App",
-				"welcome": "Welcome to Flutter!",
-			},
-		}
-
-		// Write the file
-		if err := writeTranslationFile(defaultTranslationPath, defaultTranslations); err != nil {
-			return fmt.Errorf("failed to create default translation file: %v", err)
-		}
-	}
-
-	// Update pubspec.yaml to add easy_localization dependency and assets
-	if err := updatePubspecForLocalization(projectPath); err != nil {
-		return fmt.Errorf("failed to update pubspec.yaml: %v", err)
-	}
+	return DetectBackend(projectPath).Init()
+}
 
-	// Update iOS Info.plist for localization
-	if err := updateIOSInfoPlistForLocalization(projectPath, defaultLang); err != nil {
-		utils.Info("Warning: Failed to update iOS Info.plist: %v", err)
-		// Don't fail the initialization if iOS config fails
+// InitLocalizationWithBackend initializes localization using the named backend
+func InitLocalizationWithBackend(projectPath, backendName string) error {
+	backend, err := NewBackend(projectPath, backendName)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	return backend.Init()
 }
 
 // IsInitialized checks if localization is properly initialized in the project
 func IsInitialized(projectPath string) InitializationStatus {
-	status := InitializationStatus{}
-
-	// Check if translations directory exists
-	translationsPath := filepath.Join(projectPath, TranslationsDir)
-	if _, err := os.Stat(translationsPath); err == nil {
-		status.HasTranslationsDir = true
-	}
-
-	// Check if translation files exist
-	if status.HasTranslationsDir {
-		translationFiles, err := ListTranslationFiles(projectPath)
-		if err == nil && len(translationFiles) > 0 {
-			status.HasTranslationFiles = true
-		}
-	}
-
-	// Check if pubspec.yaml has easy_localization dependency
-	pubspecPath := filepath.Join(projectPath, "pubspec.yaml")
-	if pubspecData, err := os.ReadFile(pubspecPath); err == nil {
-		pubspecContent := string(pubspecData)
-		if strings.Contains(pubspecContent, "easy_localization:") {
-			status.HasEasyLocalization = true
-		}
-	}
-
-	// Check if iOS Info.plist has localization configuration
-	iosInfoPlistPath := filepath.Join(projectPath, "ios", "Runner", "Info.plist")
-	if infoPlistData, err := os.ReadFile(iosInfoPlistPath); err == nil {
-		infoPlistContent := string(infoPlistData)
-		if strings.Contains(infoPlistContent, "CFBundleLocalizations") {
-			status.HasIOSConfig = true
-		}
-	}
-
-	// Determine if fully initialized (iOS config is optional)
-	status.IsInitialized = status.HasTranslationsDir &&
-		status.HasTranslationFiles &&
-		status.HasEasyLocalization
-
-	return status
+	return DetectBackend(projectPath).Status()
 }
 
 // AddLanguage adds a new language to the project
@@ -136,25 +73,25 @@ func AddLanguage(projectPath, languageCode string) error {
 	formattedCode := langInfo.String()
 
 	// Check if translations directory exists
-	translationsPath := filepath.Join(projectPath, TranslationsDir)
-	if _, err := os.Stat(translationsPath); os.IsNotExist(err) {
+	backend := DetectBackend(projectPath)
+	if _, err := os.Stat(backend.TranslationsDir()); os.IsNotExist(err) {
 		return fmt.Errorf("translations directory not found. Please run 'fdawg lang init' first to initialize localization")
 	}
 
 	// Check if the language already exists
-	translationFilePath := filepath.Join(translationsPath, formattedCode+".json")
+	translationFilePath := backend.FilePath(formattedCode)
 
 	if _, err := os.Stat(translationFilePath); err == nil {
 		return fmt.Errorf("language %s (%s) already exists", langInfo.DisplayName(), formattedCode)
 	}
 
 	// Get the default translation file to copy structure
-	defaultLang := fmt.Sprintf("%s_%s", DefaultLanguage, DefaultCountry)
-	defaultTranslationPath := filepath.Join(translationsPath, defaultLang+".json")
+	defaultLang := backend.DefaultLanguage()
+	defaultTranslationPath := backend.FilePath(defaultLang)
 
 	// Check if default translation file exists
 	if _, err := os.Stat(defaultTranslationPath); os.IsNotExist(err) {
-		return fmt.Errorf("default translation file (%s.json) not found. Please run 'fdawg lang init' first to initialize localization", defaultLang)
+		return fmt.Errorf("default translation file (%s) not found. Please run 'fdawg lang init' first to initialize localization", filepath.Base(defaultTranslationPath))
 	}
 
 	// Read default translations
@@ -165,7 +102,7 @@ func AddLanguage(projectPath, languageCode string) error {
 
 	// Create a new translation file with the same structure but empty values
 	newTranslations := make(map[string]interface{})
-	copyTranslationStructure(defaultTranslations, newTranslations, "")
+	copyTranslationStructure(defaultTranslations, newTranslations, "", formattedCode)
 
 	// Write the new translation file
 	if err := writeTranslationFile(translationFilePath, newTranslations); err != nil {
@@ -193,19 +130,19 @@ func RemoveLanguage(projectPath, languageCode string) error {
 	formattedCode := langInfo.String()
 
 	// Check if translations directory exists
-	translationsPath := filepath.Join(projectPath, TranslationsDir)
-	if _, err := os.Stat(translationsPath); os.IsNotExist(err) {
+	backend := DetectBackend(projectPath)
+	if _, err := os.Stat(backend.TranslationsDir()); os.IsNotExist(err) {
 		return fmt.Errorf("translations directory not found. Please run 'fdawg lang init' first to initialize localization")
 	}
 
 	// Check if it's the default language
-	defaultLang := fmt.Sprintf("%s_%s", DefaultLanguage, DefaultCountry)
+	defaultLang := backend.DefaultLanguage()
 	if formattedCode == defaultLang {
 		return fmt.Errorf("cannot remove the default language (%s)", defaultLang)
 	}
 
 	// Check if the language exists
-	translationFilePath := filepath.Join(translationsPath, formattedCode+".json")
+	translationFilePath := backend.FilePath(formattedCode)
 
 	if _, err := os.Stat(translationFilePath); os.IsNotExist(err) {
 		return fmt.Errorf("language %s (%s) does not exist. Use 'fdawg lang list' to see available languages", langInfo.DisplayName(), formattedCode)
@@ -216,10 +153,13 @@ func RemoveLanguage(projectPath, languageCode string) error {
 		return fmt.Errorf("failed to remove translation file: %v", err)
 	}
 
-	// Update iOS Info.plist to remove the language
-	if err := removeLanguageFromIOSInfoPlist(projectPath, formattedCode); err != nil {
-		utils.Info("Warning: Failed to update iOS Info.plist: %v", err)
-		// Don't fail if iOS config fails
+	// Update iOS Info.plist to remove the language, unless another language such as
+	// the en_XA pseudo-locale still needs its base code
+	if !hasLanguageWithBase(backend, baseLanguage(formattedCode)) {
+		if err := removeLanguageFromIOSInfoPlist(projectPath, formattedCode); err != nil {
+			utils.Info("Warning: Failed to update iOS Info.plist: %v", err)
+			// Don't fail if iOS config fails
+		}
 	}
 
 	utils.Info("Language %s removed from translation files", formattedCode)
@@ -231,18 +171,18 @@ func RemoveLanguage(projectPath, languageCode string) error {
 // InsertTranslationKey adds a new translation key to all language files
 func InsertTranslationKey(projectPath, key string, values map[string]string) error {
 	// Validate key format
-	if !isValidTranslationKey(key) {
-		return fmt.Errorf("invalid translation key format: %s (use dot notation, e.g., 'category.subcategory.key')", key)
+	backend := DetectBackend(projectPath)
+	if err := backend.ValidateKey(key); err != nil {
+		return err
 	}
 
 	// Check if translations directory exists
-	translationsPath := filepath.Join(projectPath, TranslationsDir)
-	if _, err := os.Stat(translationsPath); os.IsNotExist(err) {
+	if _, err := os.Stat(backend.TranslationsDir()); os.IsNotExist(err) {
 		return fmt.Errorf("translations directory not found. Please run 'fdawg lang init' first to initialize localization")
 	}
 
 	// Get all translation files
-	translationFiles, err := ListTranslationFiles(projectPath)
+	translationFiles, err := listTranslationFiles(backend)
 	if err != nil {
 		return fmt.Errorf("failed to list translation files: %v", err)
 	}
@@ -266,19 +206,29 @@ func InsertTranslationKey(projectPath, key string, values map[string]string) err
 		}
 	}
 
+	// Keep backend metadata such as ARB placeholders in sync with the new values
+	if err := backend.UpdateKeyMetadata(key, values); err != nil {
+		return fmt.Errorf("failed to update metadata for %s: %v", key, err)
+	}
+
 	return nil
 }
 
+// ValidateTranslationKey checks that a key can be stored by the project's localization backend
+func ValidateTranslationKey(projectPath, key string) error {
+	return DetectBackend(projectPath).ValidateKey(key)
+}
+
 // DeleteTranslationKey deletes a translation key from all language files
 func DeleteTranslationKey(projectPath, key string) error {
 	// Check if translations directory exists
-	translationsPath := filepath.Join(projectPath, TranslationsDir)
-	if _, err := os.Stat(translationsPath); os.IsNotExist(err) {
+	backend := DetectBackend(projectPath)
+	if _, err := os.Stat(backend.TranslationsDir()); os.IsNotExist(err) {
 		return fmt.Errorf("translations directory not found. Please run 'fdawg lang init' first to initialize localization")
 	}
 
 	// Get all translation files
-	translationFiles, err := ListTranslationFiles(projectPath)
+	translationFiles, err := listTranslationFiles(backend)
 	if err != nil {
 		return fmt.Errorf("failed to list translation files: %v", err)
 	}
@@ -299,7 +249,12 @@ func DeleteTranslationKey(projectPath, key string) error {
 
 // ListTranslationFiles lists all translation files in the project
 func ListTranslationFiles(projectPath string) ([]TranslationFile, error) {
-	translationsPath := filepath.Join(projectPath, TranslationsDir)
+	return listTranslationFiles(DetectBackend(projectPath))
+}
+
+// listTranslationFiles lists the translation files managed by a backend
+func listTranslationFiles(backend Backend) ([]TranslationFile, error) {
+	translationsPath := backend.TranslationsDir()
 
 	// Check if the translations directory exists
 	if _, err := os.Stat(translationsPath); os.IsNotExist(err) {
@@ -314,14 +269,17 @@ func ListTranslationFiles(projectPath string) ([]TranslationFile, error) {
 
 	var files []TranslationFile
 
-	// Process each JSON file
+	// Process each translation file
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+		if entry.IsDir() {
 			continue
 		}
 
 		// Extract language code from filename
-		langCode := strings.TrimSuffix(entry.Name(), ".json")
+		langCode, ok := backend.LanguageFromFile(entry.Name())
+		if !ok {
+			continue
+		}
 
 		// Create TranslationFile
 		filePath := filepath.Join(translationsPath, entry.Name())
@@ -330,10 +288,16 @@ func ListTranslationFiles(projectPath string) ([]TranslationFile, error) {
 			return nil, fmt.Errorf("failed to read translation file %s: %v", entry.Name(), err)
 		}
 
+		metadata, err := readTranslationMetadata(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read translation file %s: %v", entry.Name(), err)
+		}
+
 		files = append(files, TranslationFile{
 			Path:     filePath,
 			Language: langCode,
 			Data:     data,
+			Metadata: metadata,
 		})
 	}
 
@@ -342,18 +306,24 @@ func ListTranslationFiles(projectPath string) ([]TranslationFile, error) {
 
 // Helper functions
 
-// writeTranslationFile writes translation data to a JSON file
+// writeTranslationFile writes translation data to a JSON or ARB file
 func writeTranslationFile(path string, data map[string]interface{}) error {
+	if filepath.Ext(path) == ".arb" {
+		return writeARBFile(path, data)
+	}
+
 	// Create the directory if it doesn't exist
 	dir := filepath.Dir(path)
 	if err := utils.EnsureDirExists(dir); err != nil {
 		return err
 	}
 
-	// Marshal the data with indentation for readability
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+	// Keep the file's key order, so hand-written and synced files don't get reshuffled
+	order, _ := readKeyOrder(path)
+
+	jsonData, err := encodeOrderedTranslations(data, order)
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %v", err)
+		return err
 	}
 
 	// Write the file
@@ -364,8 +334,12 @@ func writeTranslationFile(path string, data map[string]interface{}) error {
 	return nil
 }
 
-// readTranslationFile reads translation data from a JSON file
+// readTranslationFile reads translation data from a JSON or ARB file
 func readTranslationFile(path string) (map[string]interface{}, error) {
+	if filepath.Ext(path) == ".arb" {
+		return readARBFile(path)
+	}
+
 	// Read the file
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -381,8 +355,18 @@ func readTranslationFile(path string) (map[string]interface{}, error) {
 	return translations, nil
 }
 
-// copyTranslationStructure copies the structure of a translation map but with empty values
-func copyTranslationStructure(src, dst map[string]interface{}, prefix string) {
+// readTranslationMetadata reads per-key metadata from a translation file.
+// Only ARB files carry metadata; other formats return nil.
+func readTranslationMetadata(path string) (map[string]map[string]interface{}, error) {
+	if filepath.Ext(path) == ".arb" {
+		return readARBMetadata(path)
+	}
+	return nil, nil
+}
+
+// copyTranslationStructure copies the structure of a translation map but with empty values.
+// Plural and gender objects get empty values for the forms the target language needs.
+func copyTranslationStructure(src, dst map[string]interface{}, prefix, language string) {
 	for key, value := range src {
 		// Build the full key path
 		fullKey := key
@@ -390,12 +374,22 @@ func copyTranslationStructure(src, dst map[string]interface{}, prefix string) {
 			fullKey = prefix + "." + key
 		}
 
+		// Plural and gender objects get the target language's forms
+		if nestedMap, ok := value.(map[string]interface{}); ok && IsMessageMap(nestedMap) {
+			forms := make(map[string]interface{})
+			for _, form := range RequiredForms(messageMapKind(nestedMap), language) {
+				forms[form] = ""
+			}
+			dst[key] = forms
+			continue
+		}
+
 		// Handle nested maps
 		if nestedMap, ok := value.(map[string]interface{}); ok {
 			// Create a new map for this key
 			dst[key] = make(map[string]interface{})
 			// Recursively copy the structure
-			copyTranslationStructure(nestedMap, dst[key].(map[string]interface{}), fullKey)
+			copyTranslationStructure(nestedMap, dst[key].(map[string]interface{}), fullKey, language)
 		} else {
 			// For leaf nodes, use an empty string
 			dst[key] = ""
@@ -437,12 +431,26 @@ func isAlphaNumeric(char rune) bool {
 
 // addKeyToTranslationFile adds a key to a translation file
 func addKeyToTranslationFile(path, key, value string) error {
+	return setKeyInTranslationFile(path, key, value)
+}
+
+// setKeyInTranslationFile sets a key to a string or plural/gender object in a translation file
+func setKeyInTranslationFile(path, key string, value interface{}) error {
 	// Read the translation file
 	translations, err := readTranslationFile(path)
 	if err != nil {
 		return err
 	}
 
+	setTranslationValue(translations, key, value)
+
+	// Write the updated translations back to the file
+	return writeTranslationFile(path, translations)
+}
+
+// setTranslationValue sets a dot-separated key in nested translation data,
+// creating parent maps as needed
+func setTranslationValue(translations map[string]interface{}, key string, value interface{}) {
 	// Split the key into parts
 	parts := strings.Split(key, ".")
 
@@ -468,9 +476,6 @@ func addKeyToTranslationFile(path, key, value string) error {
 			}
 		}
 	}
-
-	// Write the updated translations back to the file
-	return writeTranslationFile(path, translations)
 }
 
 // deleteKeyFromTranslationFile deletes a key from a translation file
@@ -481,6 +486,18 @@ func deleteKeyFromTranslationFile(path, key string) error {
 		return err
 	}
 
+	if !deleteTranslationValue(translations, key) {
+		// Key doesn't exist, nothing to delete
+		return nil
+	}
+
+	// Write the updated translations back to the file
+	return writeTranslationFile(path, translations)
+}
+
+// deleteTranslationValue deletes a dot-separated key from nested translation data,
+// removing parent maps left empty. It reports whether the key's parents existed.
+func deleteTranslationValue(translations map[string]interface{}, key string) bool {
 	// Split the key into parts
 	parts := strings.Split(key, ".")
 
@@ -498,7 +515,7 @@ func deleteKeyFromTranslationFile(path, key string) error {
 			current = nestedMap
 		} else {
 			// Key doesn't exist, nothing to delete
-			return nil
+			return false
 		}
 	}
 
@@ -518,8 +535,7 @@ func deleteKeyFromTranslationFile(path, key string) error {
 		}
 	}
 
-	// Write the updated translations back to the file
-	return writeTranslationFile(path, translations)
+	return true
 }
 
 // updatePubspecForLocalization updates the pubspec.yaml file to add easy_localization dependency and assets
@@ -624,6 +640,20 @@ func updatePubspecForLocalization(projectPath string) error {
 	return nil
 }
 
+// hasLanguageWithBase reports whether any translation file's language has the given base code, e.g. "en"
+func hasLanguageWithBase(backend Backend, base string) bool {
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return false
+	}
+	for _, file := range translationFiles {
+		if baseLanguage(file.Language) == base {
+			return true
+		}
+	}
+	return false
+}
+
 // updateIOSInfoPlistForLocalization updates the iOS Info.plist file to add localization support
 func updateIOSInfoPlistForLocalization(projectPath, defaultLang string) error {
 	iosInfoPlistPath := filepath.Join(projectPath, "ios", "Runner", "Info.plist")
@@ -0,0 +1,305 @@
+package localization
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/translate"
+)
+
+// defaultMachineBatchSize is the number of texts sent to the provider per request
+const defaultMachineBatchSize = 50
+
+// MachineTranslateOptions controls bulk machine translation of missing values
+type MachineTranslateOptions struct {
+	// Languages to fill in. Every language but the default is filled if empty.
+	Languages []string
+
+	// BatchSize is the number of texts sent to the provider per request
+	BatchSize int
+
+	// Progress is called after every request with the texts translated so far and the total
+	Progress func(done, total int)
+}
+
+// MachineTranslateFailure is a value that couldn't be translated
+type MachineTranslateFailure struct {
+	Key      string `json:"key"`
+	Language string `json:"language"`
+	Error    string `json:"error"`
+}
+
+// MachineTranslateResult summarizes a bulk machine translation
+type MachineTranslateResult struct {
+	SourceLanguage string `json:"sourceLanguage"`
+	// Translated is the number of values filled in per language
+	Translated map[string]int            `json:"translated"`
+	Failures   []MachineTranslateFailure `json:"failures"`
+	// Issues are placeholder errors in the machine translations, which need a manual fix
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// missingValue is a value missing from a language, with the texts to translate.
+// Plural and gender messages have one text per missing form.
+type missingValue struct {
+	key         string
+	sourceValue interface{}
+	message     Message
+	forms       []string
+	texts       []string
+	translated  []string
+	failed      bool
+}
+
+// TranslateMissing fills in every empty or missing value with a machine translation
+// of the default language text, in batches through the translation service
+func TranslateMissing(projectPath string, service *translate.Service, options MachineTranslateOptions) (*MachineTranslateResult, error) {
+	backend := DetectBackend(projectPath)
+	defaultLanguage := backend.DefaultLanguage()
+
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+	// Pseudo-locales are generated from the default language, not translated
+	translationFiles = withoutPseudoLocales(translationFiles)
+
+	if len(translationFiles) == 0 {
+		return nil, fmt.Errorf("no translation files found. Please run 'fdawg lang init' first to initialize localization")
+	}
+
+	var source *TranslationFile
+	files := make(map[string]TranslationFile)
+	for i, file := range translationFiles {
+		files[file.Language] = file
+		if file.Language == defaultLanguage {
+			source = &translationFiles[i]
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("default language file (%s) not found", defaultLanguage)
+	}
+
+	languages := options.Languages
+	if len(languages) == 0 {
+		for _, file := range translationFiles {
+			if file.Language != defaultLanguage {
+				languages = append(languages, file.Language)
+			}
+		}
+	}
+	for _, language := range languages {
+		if language == defaultLanguage {
+			return nil, fmt.Errorf("%s is the default language and is translated from, not to", language)
+		}
+		if _, ok := files[language]; !ok {
+			return nil, fmt.Errorf("language %s not found. Add it first with 'fdawg lang add %s'", language, language)
+		}
+	}
+
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultMachineBatchSize
+	}
+
+	// Collect all the work first so progress has a total
+	keys := MessageKeys(source.Data)
+	missing := make(map[string][]*missingValue)
+	total := 0
+	for _, language := range languages {
+		for _, key := range keys {
+			sourceValue, _ := getTranslationValue(source.Data, key)
+			value, _ := getTranslationValue(files[language].Data, key)
+			if item := findMissingValue(key, language, sourceValue, value); item != nil {
+				missing[language] = append(missing[language], item)
+				total += len(item.texts)
+			}
+		}
+	}
+
+	state, err := LoadReviewState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	state.Prune(translationFiles)
+
+	result := &MachineTranslateResult{
+		SourceLanguage: defaultLanguage,
+		Translated:     make(map[string]int),
+		Failures:       []MachineTranslateFailure{},
+		Issues:         []ValidationIssue{},
+	}
+
+	done := 0
+	if options.Progress != nil {
+		options.Progress(done, total)
+	}
+
+	for _, language := range languages {
+		items := missing[language]
+		if len(items) == 0 {
+			continue
+		}
+
+		// Flatten the texts of every value so requests are filled up to the batch size
+		type textRef struct {
+			item  *missingValue
+			index int
+		}
+		var refs []textRef
+		for _, item := range items {
+			item.translated = make([]string, len(item.texts))
+			for i := range item.texts {
+				refs = append(refs, textRef{item, i})
+			}
+		}
+
+		for start := 0; start < len(refs); start += batchSize {
+			end := start + batchSize
+			if end > len(refs) {
+				end = len(refs)
+			}
+			batch := refs[start:end]
+
+			texts := make([]string, len(batch))
+			for i, ref := range batch {
+				texts[i] = ref.item.texts[ref.index]
+			}
+
+			resp, err := service.BatchTranslate(translate.BatchTranslationRequest{
+				Texts:      texts,
+				SourceLang: defaultLanguage,
+				TargetLang: language,
+			})
+			for i, ref := range batch {
+				if err != nil {
+					if !ref.item.failed {
+						result.Failures = append(result.Failures, MachineTranslateFailure{
+							Key:      ref.item.key,
+							Language: language,
+							Error:    err.Error(),
+						})
+					}
+					ref.item.failed = true
+					continue
+				}
+				ref.item.translated[ref.index] = resp.Translations[i].TranslatedText
+			}
+
+			done += len(batch)
+			if options.Progress != nil {
+				options.Progress(done, total)
+			}
+		}
+
+		// Write the language's translations before moving on, so a later failure keeps them
+		path := files[language].Path
+		for _, item := range items {
+			if item.failed {
+				continue
+			}
+
+			var value interface{}
+			if item.message.Kind == MessageText {
+				value = item.translated[0]
+			} else {
+				for i, form := range item.forms {
+					item.message.Forms[form] = item.translated[i]
+				}
+				value = backend.FormatMessage(item.message)
+			}
+
+			if err := setKeyInTranslationFile(path, item.key, value); err != nil {
+				state.Save()
+				return result, fmt.Errorf("failed to write %s: %v", language, err)
+			}
+			result.Translated[language]++
+
+			// Translations with placeholder errors are flagged for review rather than left as machine output
+			status := StatusMachine
+			for _, issue := range checkTranslation(item.key, language, defaultLanguage, item.sourceValue, value) {
+				if issue.Severity == SeverityError {
+					result.Issues = append(result.Issues, issue)
+					status = StatusNeedsReview
+				}
+			}
+			state.Set(language, item.key, value, status)
+		}
+
+		if err := state.Save(); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// findMissingValue returns the texts to translate for a key in a language, or nil
+// if the value is already translated or the default language has no text for it.
+// Plural and gender messages keep their translated forms and only fill in empty ones.
+func findMissingValue(key, language string, sourceValue, value interface{}) *missingValue {
+	source := ParseMessage(sourceValue)
+
+	if source.Kind == MessageText {
+		if strings.TrimSpace(source.Text) == "" {
+			return nil
+		}
+		if !isEmptyValue(value) {
+			return nil
+		}
+		return &missingValue{
+			key:         key,
+			sourceValue: sourceValue,
+			message:     Message{Kind: MessageText},
+			texts:       []string{source.Text},
+		}
+	}
+
+	target := parseMessageAs(value, source.Kind)
+	if target.Kind != source.Kind {
+		target = Message{Kind: source.Kind, Argument: source.Argument}
+	}
+	if target.Argument == "" {
+		target.Argument = source.Argument
+	}
+	forms := make(map[string]string)
+	for form, text := range target.Forms {
+		forms[form] = text
+	}
+	target.Forms = forms
+
+	// Explicit value forms such as =0 are kept alongside the language's categories
+	names := RequiredForms(source.Kind, language)
+	for _, form := range SortForms(source.Forms) {
+		if strings.HasPrefix(form, "=") {
+			names = append(names, form)
+		}
+	}
+
+	item := &missingValue{key: key, sourceValue: sourceValue, message: target}
+	for _, form := range names {
+		if strings.TrimSpace(target.Forms[form]) != "" {
+			continue
+		}
+		text := source.Forms[form]
+		if strings.TrimSpace(text) == "" {
+			text = source.Forms["other"]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		item.forms = append(item.forms, form)
+		item.texts = append(item.texts, text)
+	}
+
+	if len(item.texts) == 0 {
+		return nil
+	}
+	return item
+}
+
+// isEmptyValue reports whether a value has no text, such as an empty string or a
+// plural object whose forms are all empty
+func isEmptyValue(value interface{}) bool {
+	return strings.TrimSpace(messageText(ParseMessage(value))) == ""
+}
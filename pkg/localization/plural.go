@@ -0,0 +1,666 @@
+package localization
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MessageText is a plain string message
+	MessageText = "text"
+
+	// MessagePlural is a message with one form per CLDR plural category
+	MessagePlural = "plural"
+
+	// MessageGender is a message with male, female and other forms
+	MessageGender = "gender"
+)
+
+// PluralCategories are the CLDR plural categories in their conventional order
+var PluralCategories = []string{"zero", "one", "two", "few", "many", "other"}
+
+// GenderForms are the forms of a gender message
+var GenderForms = []string{"male", "female", "other"}
+
+// cldrPluralForms maps a language to the cardinal plural categories CLDR defines for it
+var cldrPluralForms = map[string][]string{
+	"af": {"one", "other"}, "am": {"one", "other"}, "ar": {"zero", "one", "two", "few", "many", "other"},
+	"az": {"one", "other"}, "be": {"one", "few", "many", "other"}, "bg": {"one", "other"},
+	"bn": {"one", "other"}, "bs": {"one", "few", "other"}, "ca": {"one", "many", "other"},
+	"cs": {"one", "few", "many", "other"}, "cy": {"zero", "one", "two", "few", "many", "other"},
+	"da": {"one", "other"}, "de": {"one", "other"}, "el": {"one", "other"}, "en": {"one", "other"},
+	"es": {"one", "many", "other"}, "et": {"one", "other"}, "eu": {"one", "other"},
+	"fa": {"one", "other"}, "fi": {"one", "other"}, "fil": {"one", "other"},
+	"fr": {"one", "many", "other"}, "ga": {"one", "two", "few", "many", "other"},
+	"gl": {"one", "other"}, "gu": {"one", "other"}, "he": {"one", "two", "other"},
+	"hi": {"one", "other"}, "hr": {"one", "few", "other"}, "hu": {"one", "other"},
+	"hy": {"one", "other"}, "id": {"other"}, "is": {"one", "other"},
+	"it": {"one", "many", "other"}, "ja": {"other"}, "ka": {"one", "other"},
+	"kk": {"one", "other"}, "km": {"other"}, "kn": {"one", "other"}, "ko": {"other"},
+	"ky": {"one", "other"}, "lo": {"other"}, "lt": {"one", "few", "many", "other"},
+	"lv": {"zero", "one", "other"}, "mk": {"one", "other"}, "ml": {"one", "other"},
+	"mn": {"one", "other"}, "mr": {"one", "other"}, "ms": {"other"},
+	"mt": {"one", "two", "few", "many", "other"}, "my": {"other"}, "nb": {"one", "other"},
+	"ne": {"one", "other"}, "nl": {"one", "other"}, "no": {"one", "other"},
+	"pa": {"one", "other"}, "pl": {"one", "few", "many", "other"},
+	"pt": {"one", "many", "other"}, "ro": {"one", "few", "other"},
+	"ru": {"one", "few", "many", "other"}, "si": {"one", "other"},
+	"sk": {"one", "few", "many", "other"}, "sl": {"one", "two", "few", "other"},
+	"sq": {"one", "other"}, "sr": {"one", "few", "other"}, "sv": {"one", "other"},
+	"sw": {"one", "other"}, "ta": {"one", "other"}, "te": {"one", "other"}, "th": {"other"},
+	"tl": {"one", "other"}, "tr": {"one", "other"}, "uk": {"one", "few", "many", "other"},
+	"ur": {"one", "other"}, "uz": {"one", "other"}, "vi": {"other"}, "zh": {"other"},
+	"zu": {"one", "other"},
+}
+
+// icuArgumentRegex matches the start of an ICU plural or select argument, e.g. {count, plural,
+var icuArgumentRegex = regexp.MustCompile(`^\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*,\s*(plural|select)\s*,`)
+
+// Message is a translation value that may have several forms
+type Message struct {
+	Kind string `json:"kind"`
+	// Text is the value of a plain text message
+	Text string `json:"text,omitempty"`
+	// Forms maps a plural category, explicit value such as =0, or gender to its text
+	Forms map[string]string `json:"forms,omitempty"`
+	// Argument is the ICU argument the forms select on, e.g. count
+	Argument string `json:"argument,omitempty"`
+}
+
+// PluralIssue describes a language missing forms of a plural or gender message
+type PluralIssue struct {
+	Key      string   `json:"key"`
+	Language string   `json:"language"`
+	Kind     string   `json:"kind"`
+	Missing  []string `json:"missing"`
+	Message  string   `json:"message"`
+}
+
+// RequiredPluralForms returns the CLDR plural categories a language needs, e.g. one, few, many
+// and other for Russian. Unknown languages fall back to one and other.
+func RequiredPluralForms(language string) []string {
+	base := strings.ToLower(strings.Split(language, "_")[0])
+	if forms, ok := cldrPluralForms[base]; ok {
+		return forms
+	}
+	return []string{"one", "other"}
+}
+
+// RequiredForms returns the forms a message of the given kind needs in a language
+func RequiredForms(kind, language string) []string {
+	switch kind {
+	case MessagePlural:
+		return RequiredPluralForms(language)
+	case MessageGender:
+		return GenderForms
+	}
+	return nil
+}
+
+// IsMessageMap reports whether a nested translation map is an easy_localization
+// plural or gender object rather than a group of keys
+func IsMessageMap(value map[string]interface{}) bool {
+	return messageMapKind(value) != MessageText
+}
+
+// messageMapKind returns the message kind a map's keys describe. A gender object needs
+// other and male or female, and a plural object at least two categories including other,
+// so groups of keys such as {"male": ..., "female": ...} or {"other": ...} stay groups.
+func messageMapKind(value map[string]interface{}) string {
+	if len(value) == 0 {
+		return MessageText
+	}
+
+	isPlural, isGender := true, true
+	for key, form := range value {
+		if _, ok := form.(string); !ok {
+			return MessageText
+		}
+		if !containsString(PluralCategories, key) {
+			isPlural = false
+		}
+		if !containsString(GenderForms, key) {
+			isGender = false
+		}
+	}
+
+	_, hasOther := value["other"]
+	_, hasMale := value["male"]
+	_, hasFemale := value["female"]
+
+	switch {
+	case isGender && hasOther && (hasMale || hasFemale):
+		return MessageGender
+	case isPlural && hasOther && len(value) >= 2:
+		return MessagePlural
+	}
+	return MessageText
+}
+
+// isSingleFormPlural reports whether a map is a plural object with only the other form,
+// as languages such as Japanese need no other. It's only a plural where the default
+// language's message is one, since on its own it can't be told from a group of keys.
+func isSingleFormPlural(value map[string]interface{}, kind string) bool {
+	if kind != MessagePlural || len(value) != 1 {
+		return false
+	}
+	_, ok := value["other"].(string)
+	return ok
+}
+
+// parseMessageAs interprets a value like ParseMessage, reading a single form plural
+// object as a plural where the default language's message, of the given kind, is one
+func parseMessageAs(value interface{}, kind string) Message {
+	if v, ok := value.(map[string]interface{}); ok && isSingleFormPlural(v, kind) {
+		text, _ := v["other"].(string)
+		return Message{Kind: MessagePlural, Forms: map[string]string{"other": text}, Argument: "count"}
+	}
+	return ParseMessage(value)
+}
+
+// ParseMessage interprets a translation value: easy_localization plural and gender
+// objects, ICU plural and select messages, or plain text
+func ParseMessage(value interface{}) Message {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		kind := messageMapKind(v)
+		if kind == MessageText {
+			return Message{Kind: MessageText, Text: fmt.Sprintf("%v", v)}
+		}
+
+		forms := make(map[string]string)
+		for key, form := range v {
+			forms[key], _ = form.(string)
+		}
+
+		argument := "count"
+		if kind == MessageGender {
+			argument = "gender"
+		}
+		return Message{Kind: kind, Forms: forms, Argument: argument}
+	case string:
+		if message, ok := parseICUMessage(v); ok {
+			return message
+		}
+		return Message{Kind: MessageText, Text: v}
+	case nil:
+		return Message{Kind: MessageText}
+	}
+
+	return Message{Kind: MessageText, Text: fmt.Sprintf("%v", value)}
+}
+
+// parseICUMessage parses a message containing a single ICU plural or select
+// argument. Text around the argument is folded into every form, so
+// "Hi {name}, {count, plural, one{# item} other{# items}}" gives the forms
+// "Hi {name}, # item" and "Hi {name}, # items".
+func parseICUMessage(text string) (Message, bool) {
+	start := -1
+	var match []string
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' {
+			continue
+		}
+		if m := icuArgumentRegex.FindStringSubmatch(text[i:]); m != nil {
+			start = i
+			match = m
+			break
+		}
+		// Skip over simple placeholders
+		if end := matchingBrace(text, i); end > i {
+			i = end
+		}
+	}
+	if start < 0 {
+		return Message{}, false
+	}
+
+	end := matchingBrace(text, start)
+	if end < 0 {
+		return Message{}, false
+	}
+
+	prefix, suffix := text[:start], text[end+1:]
+	if strings.Contains(prefix, ", plural,") || strings.Contains(suffix, ", plural,") ||
+		strings.Contains(prefix, ", select,") || strings.Contains(suffix, ", select,") {
+		// More than one complex argument can't be represented as a single set of forms
+		return Message{}, false
+	}
+
+	// Parse "selector{text}" pairs in the body
+	body := text[start+len(match[0]) : end]
+	forms := make(map[string]string)
+	for i := 0; i < len(body); {
+		for i < len(body) && (body[i] == ' ' || body[i] == '\t' || body[i] == '\n' || body[i] == '\r') {
+			i++
+		}
+		if i >= len(body) {
+			break
+		}
+
+		selectorStart := i
+		for i < len(body) && body[i] != '{' && body[i] != ' ' && body[i] != '\t' && body[i] != '\n' {
+			i++
+		}
+		selector := body[selectorStart:i]
+		for i < len(body) && body[i] != '{' {
+			i++
+		}
+		if i >= len(body) {
+			return Message{}, false
+		}
+
+		formEnd := matchingBrace(body, i)
+		if formEnd < 0 {
+			return Message{}, false
+		}
+
+		// offset:n applies to the whole argument and has no form of its own
+		if !strings.HasPrefix(selector, "offset:") {
+			forms[selector] = prefix + body[i+1:formEnd] + suffix
+		}
+		i = formEnd + 1
+	}
+
+	if _, ok := forms["other"]; !ok {
+		return Message{}, false
+	}
+
+	kind := MessagePlural
+	if match[2] == "select" {
+		for selector := range forms {
+			if !containsString(GenderForms, selector) {
+				// Other select messages are kept as plain text
+				return Message{}, false
+			}
+		}
+		kind = MessageGender
+	}
+
+	return Message{Kind: kind, Forms: forms, Argument: match[1]}, true
+}
+
+// matchingBrace returns the index of the brace closing the one at start, or -1
+func matchingBrace(text string, start int) int {
+	depth := 0
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// FormatICUMessage formats a message as an ICU string, e.g. {count, plural, one{...} other{...}}
+func FormatICUMessage(message Message) string {
+	if message.Kind == MessageText {
+		return message.Text
+	}
+
+	argument := message.Argument
+	if argument == "" {
+		argument = "count"
+		if message.Kind == MessageGender {
+			argument = "gender"
+		}
+	}
+
+	icuType := "plural"
+	if message.Kind == MessageGender {
+		icuType = "select"
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "{%s, %s,", argument, icuType)
+	for _, form := range SortForms(message.Forms) {
+		fmt.Fprintf(&builder, " %s{%s}", form, message.Forms[form])
+	}
+	builder.WriteString("}")
+
+	return builder.String()
+}
+
+// FormatFormsText formats a message for editing as text, one "form: text" line per form
+func FormatFormsText(message Message) string {
+	if message.Kind == MessageText {
+		return message.Text
+	}
+
+	var lines []string
+	empty := true
+	for _, form := range SortForms(message.Forms) {
+		lines = append(lines, fmt.Sprintf("%s: %s", form, message.Forms[form]))
+		if strings.TrimSpace(message.Forms[form]) != "" {
+			empty = false
+		}
+	}
+
+	// A message with no text in any form is shown as missing
+	if empty {
+		return ""
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseFormsText parses text written by FormatFormsText back into a message of the given kind.
+// Lines that don't start with a form name continue the previous form, and text
+// before the first form name is used as the "other" form.
+func ParseFormsText(kind, argument, text string) Message {
+	message := Message{Kind: kind, Forms: make(map[string]string), Argument: argument}
+
+	if strings.TrimSpace(text) == "" {
+		return message
+	}
+
+	current := "other"
+	for _, line := range strings.Split(text, "\n") {
+		if name, value, ok := strings.Cut(line, ":"); ok && isFormName(kind, strings.TrimSpace(name)) {
+			current = strings.TrimSpace(name)
+			message.Forms[current] = strings.TrimSpace(value)
+		} else if existing, ok := message.Forms[current]; ok {
+			message.Forms[current] = existing + "\n" + line
+		} else {
+			message.Forms[current] = line
+		}
+	}
+
+	return message
+}
+
+// isFormName reports whether a name is a valid form for a message kind
+func isFormName(kind, name string) bool {
+	if kind == MessageGender {
+		return containsString(GenderForms, name)
+	}
+	if strings.HasPrefix(name, "=") {
+		_, err := strconv.Atoi(name[1:])
+		return err == nil
+	}
+	return containsString(PluralCategories, name)
+}
+
+// SortForms returns form names in order: explicit values such as =0 first,
+// then plural categories or genders in their conventional order
+func SortForms(forms map[string]string) []string {
+	var names []string
+	for name := range forms {
+		names = append(names, name)
+	}
+
+	rank := func(name string) (int, int) {
+		if strings.HasPrefix(name, "=") {
+			n, _ := strconv.Atoi(name[1:])
+			return 0, n
+		}
+		for i, category := range PluralCategories {
+			if name == category {
+				return 1, i
+			}
+		}
+		for i, gender := range GenderForms {
+			if name == gender {
+				return 1, i
+			}
+		}
+		return 2, 0
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		groupI, orderI := rank(names[i])
+		groupJ, orderJ := rank(names[j])
+		if groupI != groupJ {
+			return groupI < groupJ
+		}
+		if orderI != orderJ {
+			return orderI < orderJ
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// GetTranslationMessages returns the message stored for a key in every language
+func GetTranslationMessages(projectPath, key string) (map[string]Message, error) {
+	translationFiles, err := ListTranslationFiles(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+
+	messages := make(map[string]Message)
+	for _, file := range translationFiles {
+		if value, ok := getTranslationValue(file.Data, key); ok {
+			messages[file.Language] = ParseMessage(value)
+		}
+	}
+
+	return messages, nil
+}
+
+// MessageKind returns the kind of a key's message, plural or gender if any language uses one
+func MessageKind(messages map[string]Message) (string, string) {
+	for _, message := range messages {
+		if message.Kind != MessageText {
+			return message.Kind, message.Argument
+		}
+	}
+	return MessageText, ""
+}
+
+// SetTranslationMessages writes messages for a key. Languages without a message keep
+// their current value, or get empty forms for the categories they need if the key is new.
+func SetTranslationMessages(projectPath, key string, messages map[string]Message) error {
+	backend := DetectBackend(projectPath)
+	if err := backend.ValidateKey(key); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(backend.TranslationsDir()); os.IsNotExist(err) {
+		return fmt.Errorf("translations directory not found. Please run 'fdawg lang init' first to initialize localization")
+	}
+
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return fmt.Errorf("failed to list translation files: %v", err)
+	}
+
+	if len(translationFiles) == 0 {
+		return fmt.Errorf("no translation files found. Please run 'fdawg lang init' first to initialize localization")
+	}
+
+	kind, argument := MessageKind(messages)
+
+	values := make(map[string]string)
+	for _, file := range translationFiles {
+		message, ok := messages[file.Language]
+		if !ok {
+			if _, exists := getTranslationValue(file.Data, key); exists {
+				continue
+			}
+
+			message = Message{Kind: kind, Argument: argument}
+		}
+
+		// Add empty required forms so translators can see what's needed
+		if message.Kind != MessageText {
+			forms := make(map[string]string)
+			for _, form := range RequiredForms(message.Kind, file.Language) {
+				forms[form] = ""
+			}
+			for form, text := range message.Forms {
+				forms[form] = text
+			}
+			message.Forms = forms
+		}
+
+		if err := setKeyInTranslationFile(file.Path, key, backend.FormatMessage(message)); err != nil {
+			return fmt.Errorf("failed to add key to %s: %v", file.Language, err)
+		}
+		values[file.Language] = FormatICUMessage(message)
+	}
+
+	if err := backend.UpdateKeyMetadata(key, values); err != nil {
+		return fmt.Errorf("failed to update metadata for %s: %v", key, err)
+	}
+
+	return nil
+}
+
+// ValidatePluralForms checks that every plural and gender message has the forms each language needs
+func ValidatePluralForms(projectPath string) ([]PluralIssue, error) {
+	translationFiles, err := ListTranslationFiles(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+	return CheckPluralForms(translationFiles), nil
+}
+
+// CheckPluralForms checks the plural and gender messages of translation files
+func CheckPluralForms(translationFiles []TranslationFile) []PluralIssue {
+	translationFiles = withoutPseudoLocales(translationFiles)
+	keys := make(map[string]bool)
+	for _, file := range translationFiles {
+		collectMessageKeys(file.Data, "", keys)
+	}
+
+	var sortedKeys []string
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	var issues []PluralIssue
+	for _, key := range sortedKeys {
+		messages := make(map[string]Message)
+		for _, file := range translationFiles {
+			value, _ := getTranslationValue(file.Data, key)
+			messages[file.Language] = ParseMessage(value)
+		}
+
+		kind, _ := MessageKind(messages)
+		if kind == MessageText {
+			continue
+		}
+
+		for _, file := range translationFiles {
+			value, _ := getTranslationValue(file.Data, key)
+			message := parseMessageAs(value, kind)
+			required := RequiredForms(kind, file.Language)
+
+			if message.Kind != kind {
+				if strings.TrimSpace(message.Text) == "" {
+					issues = append(issues, PluralIssue{
+						Key:      key,
+						Language: file.Language,
+						Kind:     kind,
+						Missing:  required,
+						Message:  fmt.Sprintf("missing %s forms: %s", kind, strings.Join(required, ", ")),
+					})
+				} else {
+					issues = append(issues, PluralIssue{
+						Key:      key,
+						Language: file.Language,
+						Kind:     kind,
+						Missing:  required,
+						Message:  fmt.Sprintf("expected a %s message with forms: %s", kind, strings.Join(required, ", ")),
+					})
+				}
+				continue
+			}
+
+			var missing []string
+			for _, form := range required {
+				if strings.TrimSpace(message.Forms[form]) == "" {
+					missing = append(missing, form)
+				}
+			}
+			if len(missing) > 0 {
+				issues = append(issues, PluralIssue{
+					Key:      key,
+					Language: file.Language,
+					Kind:     kind,
+					Missing:  missing,
+					Message:  fmt.Sprintf("missing %s forms: %s", kind, strings.Join(missing, ", ")),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// MessageKeys returns every translation key in the data, treating plural and
+// gender objects as single keys
+func MessageKeys(data map[string]interface{}) []string {
+	keys := make(map[string]bool)
+	collectMessageKeys(data, "", keys)
+
+	var result []string
+	for key := range keys {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// collectMessageKeys recursively collects keys, stopping at plural and gender objects
+func collectMessageKeys(data map[string]interface{}, prefix string, keys map[string]bool) {
+	for key, value := range data {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+
+		if nestedMap, ok := value.(map[string]interface{}); ok && !IsMessageMap(nestedMap) {
+			collectMessageKeys(nestedMap, fullKey, keys)
+		} else {
+			keys[fullKey] = true
+		}
+	}
+}
+
+// getTranslationValue returns the value at a dot-notation key
+func getTranslationValue(data map[string]interface{}, key string) (interface{}, bool) {
+	// Flat keys, as used by ARB files, may contain no dots at all
+	if value, ok := data[key]; ok {
+		return value, true
+	}
+
+	parts := strings.Split(key, ".")
+	current := data
+	for i, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return value, true
+		}
+		if current, ok = value.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+// containsString reports whether a slice contains a string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,269 @@
+package localization
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Jerinji2016/fdawg/pkg/utils"
+)
+
+const (
+	// PseudoLocaleAccented is the pseudo-locale with accented, padded and bracketed text
+	PseudoLocaleAccented = "en_XA"
+
+	// PseudoLocaleBidi is the right-to-left pseudo-locale with mirrored text
+	PseudoLocaleBidi = "ar_XB"
+
+	// DefaultPseudoPadding is the percentage of extra length added to en_XA text
+	DefaultPseudoPadding = 30
+)
+
+// PseudoLocales lists the pseudo-locales that can be generated
+var PseudoLocales = []string{PseudoLocaleAccented, PseudoLocaleBidi}
+
+// PseudoOptions controls pseudo-locale generation
+type PseudoOptions struct {
+	// Locale is one of PseudoLocales
+	Locale string
+
+	// Padding is the percentage of extra length added to each en_XA text, to catch truncation
+	Padding int
+}
+
+// PseudoResult reports a generated pseudo-locale file
+type PseudoResult struct {
+	Locale string `json:"locale"`
+	Path   string `json:"path"`
+	Keys   int    `json:"keys"`
+}
+
+// pseudoAccents maps ASCII letters to accented look-alikes that stay readable
+var pseudoAccents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î',
+	'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ',
+	's': 'š', 't': 'ţ', 'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î',
+	'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ',
+	'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// pseudoPaddingWords pad en_XA text, as readable words so the padding is easy to spot
+var pseudoPaddingWords = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+
+// pseudoProtectedRegex matches the parts of a run of text that must survive
+// pseudo-localization: {} and {name} placeholders, the ICU plural # and
+// easy_localization @:key links. ICU arguments are handled by writePseudoMessage.
+var pseudoProtectedRegex = regexp.MustCompile(`\{[^{}]*\}|@(?:\.[a-z]+)?:[A-Za-z0-9_.]*[A-Za-z0-9_]|#`)
+
+const (
+	// rightToLeftOverride and popDirectionalFormatting wrap each word of ar_XB text, so
+	// it's drawn mirrored
+	rightToLeftOverride      = "\u202e"
+	popDirectionalFormatting = "\u202c"
+)
+
+// IsPseudoLocale reports whether a language is one of PseudoLocales
+func IsPseudoLocale(language string) bool {
+	return containsString(PseudoLocales, language)
+}
+
+// withoutPseudoLocales drops pseudo-locale files, which are regenerated from the default
+// language rather than translated, reviewed, validated, exported or synced
+func withoutPseudoLocales(files []TranslationFile) []TranslationFile {
+	var result []TranslationFile
+	for _, file := range files {
+		if !IsPseudoLocale(file.Language) {
+			result = append(result, file)
+		}
+	}
+	return result
+}
+
+// GeneratePseudoLocale writes a pseudo-locale file built from the default language,
+// replacing any earlier one. Placeholders, ICU # and linked keys are kept as they are.
+func GeneratePseudoLocale(projectPath string, options PseudoOptions) (*PseudoResult, error) {
+	if !IsPseudoLocale(options.Locale) {
+		return nil, fmt.Errorf("unknown pseudo-locale %s, expected one of: %s", options.Locale, strings.Join(PseudoLocales, ", "))
+	}
+	if options.Padding < 0 {
+		return nil, fmt.Errorf("padding must not be negative")
+	}
+
+	backend := DetectBackend(projectPath)
+	defaultLanguage := backend.DefaultLanguage()
+
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+
+	var source *TranslationFile
+	for i, file := range translationFiles {
+		if file.Language == defaultLanguage {
+			source = &translationFiles[i]
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("default language file (%s) not found. Please run 'fdawg lang init' first to initialize localization", defaultLanguage)
+	}
+
+	pseudo := make(map[string]interface{})
+	keys := MessageKeys(source.Data)
+	for _, key := range keys {
+		value, _ := getTranslationValue(source.Data, key)
+		message := ParseMessage(value)
+
+		if message.Kind == MessageText {
+			message.Text = pseudoLocalize(message.Text, options)
+		} else {
+			forms := make(map[string]string)
+			for form, text := range message.Forms {
+				forms[form] = pseudoLocalize(text, options)
+			}
+			message.Forms = forms
+		}
+
+		setTranslationValue(pseudo, key, backend.FormatMessage(message))
+	}
+
+	path := backend.FilePath(options.Locale)
+	if err := writeTranslationFile(path, pseudo); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %v", options.Locale, err)
+	}
+
+	// Register the locale for iOS, as lang add does
+	if err := addLanguageToIOSInfoPlist(projectPath, options.Locale); err != nil {
+		utils.Info("Warning: Failed to update iOS Info.plist: %v", err)
+		// Don't fail if iOS config fails
+	}
+
+	return &PseudoResult{
+		Locale: options.Locale,
+		Path:   path,
+		Keys:   len(keys),
+	}, nil
+}
+
+// pseudoLocalize transforms a text for a pseudo-locale. Empty texts stay empty, so
+// missing values still show up as missing.
+func pseudoLocalize(text string, options PseudoOptions) string {
+	if text == "" {
+		return text
+	}
+
+	var out strings.Builder
+	letters := writePseudoMessage(&out, text, options.Locale)
+
+	if options.Locale == PseudoLocaleBidi {
+		return "[" + out.String() + "]"
+	}
+
+	return "[" + out.String() + pseudoPadding(letters*options.Padding/100) + "]"
+}
+
+// writePseudoMessage writes a text for a pseudo-locale, keeping placeholders as they are.
+// In ICU plural and select arguments such as {kind, select, a{...} other{...}} only the
+// form bodies are transformed, so the argument name, keyword and selectors stay valid.
+func writePseudoMessage(out *strings.Builder, text, locale string) int {
+	count := 0
+	last := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' {
+			continue
+		}
+		end := matchingBrace(text, i)
+		if end < 0 {
+			break
+		}
+		count += writePseudoRun(out, text[last:i], locale)
+
+		header := icuArgumentRegex.FindString(text[i:])
+		if header == "" {
+			// A placeholder, kept whole
+			out.WriteString(text[i : end+1])
+		} else {
+			out.WriteString(header)
+			j := i + len(header)
+			for j < end {
+				bodyEnd := -1
+				if text[j] == '{' {
+					bodyEnd = matchingBrace(text, j)
+				}
+				if bodyEnd < 0 || bodyEnd > end {
+					out.WriteByte(text[j])
+					j++
+					continue
+				}
+				out.WriteByte('{')
+				count += writePseudoMessage(out, text[j+1:bodyEnd], locale)
+				out.WriteByte('}')
+				j = bodyEnd + 1
+			}
+			out.WriteByte('}')
+		}
+
+		i = end
+		last = end + 1
+	}
+	count += writePseudoRun(out, text[last:], locale)
+	return count
+}
+
+// writePseudoRun writes a run of text outside ICU arguments, keeping its placeholders,
+// # and linked keys as they are
+func writePseudoRun(out *strings.Builder, text, locale string) int {
+	count := 0
+	last := 0
+	for _, match := range pseudoProtectedRegex.FindAllStringIndex(text, -1) {
+		count += writePseudoText(out, text[last:match[0]], locale)
+		out.WriteString(text[match[0]:match[1]])
+		last = match[1]
+	}
+	return count + writePseudoText(out, text[last:], locale)
+}
+
+// writePseudoText writes a run of text without placeholders: accented for en_XA, or
+// with each word wrapped in a right-to-left override for ar_XB. It returns the
+// number of characters written, which the padding is based on.
+func writePseudoText(out *strings.Builder, text, locale string) int {
+	count := 0
+	if locale == PseudoLocaleBidi {
+		inWord := false
+		for _, r := range text {
+			isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+			if isWordRune && !inWord {
+				out.WriteString(rightToLeftOverride)
+			} else if !isWordRune && inWord {
+				out.WriteString(popDirectionalFormatting)
+			}
+			inWord = isWordRune
+			out.WriteRune(r)
+			count++
+		}
+		if inWord {
+			out.WriteString(popDirectionalFormatting)
+		}
+		return count
+	}
+
+	for _, r := range text {
+		if accented, ok := pseudoAccents[r]; ok {
+			r = accented
+		}
+		out.WriteRune(r)
+		count++
+	}
+	return count
+}
+
+// pseudoPadding returns padding words of at least n characters, starting with a space
+func pseudoPadding(n int) string {
+	var padding strings.Builder
+	for i := 0; padding.Len() < n; i++ {
+		padding.WriteString(" ")
+		padding.WriteString(pseudoPaddingWords[i%len(pseudoPaddingWords)])
+	}
+	return padding.String()
+}
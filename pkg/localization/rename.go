@@ -0,0 +1,416 @@
+package localization
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// KeyRename is a translation key moved by a rename
+type KeyRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RenameResult reports what a rename changed
+type RenameResult struct {
+	// Keys lists every key moved, which is several for a subtree
+	Keys []KeyRename `json:"keys"`
+	// Languages lists the translation files that had the key
+	Languages []string `json:"languages"`
+	// CodeFiles lists the Dart files rewritten, relative to the project
+	CodeFiles []string `json:"codeFiles"`
+	// CodeReferences is the number of references rewritten in Dart code
+	CodeReferences int `json:"codeReferences"`
+	// Candidates are accesses named like a renamed gen-l10n getter on a receiver
+	// that isn't known to be the localizations; they're left unchanged
+	Candidates []KeyUsage `json:"candidates,omitempty"`
+	// CodeError is why rewriting Dart code stopped, after the translation files were
+	// renamed. CodeFiles lists the files rewritten before that.
+	CodeError string `json:"codeError,omitempty"`
+}
+
+// renamedFile is a translation file with its original content, kept to roll back a failed rename
+type renamedFile struct {
+	path     string
+	language string
+	original []byte
+	entries  map[string]interface{}
+}
+
+// RenameTranslationKey moves a key, or a subtree of keys, to a new name in every
+// language file. All files are checked before any is written, and written files
+// are restored if a later write fails. With updateCode, references to the moved
+// keys in lib/**/*.dart are rewritten too; as the translation files are renamed
+// by then, a failure there is reported in the result's CodeError, not as an error.
+func RenameTranslationKey(projectPath, oldKey, newKey string, updateCode bool) (*RenameResult, error) {
+	backend := DetectBackend(projectPath)
+
+	if oldKey == newKey {
+		return nil, fmt.Errorf("the new key is the same as the old key")
+	}
+	if err := backend.ValidateKey(newKey); err != nil {
+		return nil, err
+	}
+
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+	if len(translationFiles) == 0 {
+		return nil, fmt.Errorf("no translation files found. Please run 'fdawg lang init' first to initialize localization")
+	}
+
+	arb := backend.Name() == BackendARB
+	renamed := make(map[string]string)
+	var files []renamedFile
+
+	// Move the key in memory in every file first, so a conflict leaves every file untouched
+	for _, file := range translationFiles {
+		original, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file.Language, err)
+		}
+
+		entries := file.Data
+		if arb {
+			// ARB files are renamed with their @key metadata
+			if entries, err = readARBEntries(file.Path); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %v", file.Language, err)
+			}
+		}
+
+		value, ok := getTranslationValue(entries, oldKey)
+		if !ok {
+			continue
+		}
+
+		deleteTranslationValue(entries, oldKey)
+		if err := checkKeyAvailable(entries, newKey); err != nil {
+			return nil, fmt.Errorf("cannot rename %s to %s in %s: %v", oldKey, newKey, file.Language, err)
+		}
+		setTranslationValue(entries, newKey, value)
+
+		if arb {
+			if metadata, ok := entries["@"+oldKey]; ok {
+				delete(entries, "@"+oldKey)
+				entries["@"+newKey] = metadata
+			}
+		}
+
+		// A nested map that isn't a plural or gender message is a subtree of keys
+		if subtree, ok := value.(map[string]interface{}); ok && !IsMessageMap(subtree) {
+			for _, key := range MessageKeys(subtree) {
+				renamed[oldKey+"."+key] = newKey + "." + key
+			}
+		} else {
+			renamed[oldKey] = newKey
+		}
+
+		files = append(files, renamedFile{
+			path:     file.Path,
+			language: file.Language,
+			original: original,
+			entries:  entries,
+		})
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("translation key %s not found", oldKey)
+	}
+
+	for i, file := range files {
+		if arb {
+			err = writeARBEntries(file.path, file.entries)
+		} else {
+			err = writeTranslationFile(file.path, file.entries)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v; %s", file.language, err, restoreRenamedFiles(files[:i+1]))
+		}
+	}
+
+	result := &RenameResult{
+		Keys:      []KeyRename{},
+		Languages: []string{},
+		CodeFiles: []string{},
+	}
+	for _, file := range files {
+		result.Languages = append(result.Languages, file.language)
+	}
+	sort.Strings(result.Languages)
+	for from, to := range renamed {
+		result.Keys = append(result.Keys, KeyRename{From: from, To: to})
+	}
+	sort.Slice(result.Keys, func(i, j int) bool {
+		return result.Keys[i].From < result.Keys[j].From
+	})
+
+	// Review statuses follow their keys
+	if state, err := LoadReviewState(projectPath); err == nil {
+		state.RenameKeys(renamed)
+		if err := state.Save(); err != nil {
+			return nil, fmt.Errorf("%v; %s", err, restoreRenamedFiles(files))
+		}
+	}
+
+	if updateCode {
+		if err := rewriteKeyReferences(projectPath, backend, oldKey, newKey, result); err != nil {
+			result.CodeError = err.Error()
+		}
+	}
+
+	return result, nil
+}
+
+// restoreRenamedFiles puts back the original content of translation files written
+// by a rename, and describes the outcome for the error that caused it
+func restoreRenamedFiles(files []renamedFile) string {
+	var failed []string
+	for _, file := range files {
+		if err := os.WriteFile(file.path, file.original, 0644); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", file.path, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Sprintf("failed to restore %s, which may hold a partial rename", strings.Join(failed, ", "))
+	}
+	return "no files were changed"
+}
+
+// checkKeyAvailable reports an error if a key, or a leaf value on its path, exists
+func checkKeyAvailable(translations map[string]interface{}, key string) error {
+	parts := strings.Split(key, ".")
+	current := translations
+	for i, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return nil
+		}
+		if i == len(parts)-1 {
+			return fmt.Errorf("key %s already exists", key)
+		}
+
+		nested, ok := value.(map[string]interface{})
+		if !ok || IsMessageMap(nested) {
+			return fmt.Errorf("key %s already has a value", strings.Join(parts[:i+1], "."))
+		}
+		current = nested
+	}
+	return nil
+}
+
+// RenameKeys moves the review statuses of renamed keys in every language
+func (s *ReviewState) RenameKeys(renamed map[string]string) {
+	for _, entries := range s.Languages {
+		for from, to := range renamed {
+			if entry, ok := entries[from]; ok {
+				delete(entries, from)
+				entries[to] = entry
+			}
+		}
+	}
+}
+
+// rewriteKeyReferences rewrites references to renamed keys in lib/**/*.dart. Generated
+// localization files are skipped, as they're regenerated from the translations.
+func rewriteKeyReferences(projectPath string, backend Backend, oldKey, newKey string, result *RenameResult) error {
+	translationsDir, _ := filepath.Rel(projectPath, backend.TranslationsDir())
+	translationsDir = filepath.ToSlash(translationsDir)
+
+	var rewrite func(relPath, content string) (string, int)
+	if backend.Name() == BackendARB {
+		getters, err := findLocalizationsGetters(projectPath)
+		if err != nil {
+			return err
+		}
+		rewrite = func(relPath, content string) (string, int) {
+			updated, count, lines := rewriteARBReferences(content, oldKey, newKey, getters)
+			for _, line := range lines {
+				result.Candidates = append(result.Candidates, KeyUsage{Key: oldKey, File: relPath, Line: line})
+			}
+			return updated, count
+		}
+	} else {
+		rewrite = func(relPath, content string) (string, int) {
+			return rewriteEasyLocalizationReferences(content, oldKey, newKey, result.Keys)
+		}
+	}
+
+	return scanDartFiles(projectPath, func(relPath, content string) error {
+		if relPath == LocaleKeysFile || strings.HasPrefix(relPath, translationsDir+"/") {
+			return nil
+		}
+
+		updated, count := rewrite(relPath, content)
+		if count == 0 {
+			return nil
+		}
+
+		if err := os.WriteFile(filepath.Join(projectPath, filepath.FromSlash(relPath)), []byte(updated), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", relPath, err)
+		}
+		result.CodeFiles = append(result.CodeFiles, relPath)
+		result.CodeReferences += count
+		return nil
+	})
+}
+
+// dartStringLiteralRegex matches a single or double quoted Dart string on one line
+var dartStringLiteralRegex = regexp.MustCompile(dartStringLiteral)
+
+// rewriteEasyLocalizationReferences rewrites string literals naming a renamed key,
+// literals building keys in the renamed subtree such as 'home.$name', and LocaleKeys
+// references. Literals without a dot are only rewritten where they're translated,
+// e.g. 'title'.tr(), since a bare word may not be a key.
+func rewriteEasyLocalizationReferences(content, oldKey, newKey string, keys []KeyRename) (string, int) {
+	renamed := make(map[string]string)
+	for _, key := range keys {
+		renamed[key.From] = key.To
+	}
+
+	translated := make(map[int]bool)
+	for _, regex := range []*regexp.Regexp{trExtensionRegex, trFunctionRegex, textWidgetRegex} {
+		for _, match := range regex.FindAllStringSubmatchIndex(content, -1) {
+			if match[2] >= 0 {
+				translated[match[2]] = true
+			} else if match[4] >= 0 {
+				translated[match[4]] = true
+			}
+		}
+	}
+
+	var out strings.Builder
+	count := 0
+	last := 0
+	for _, match := range dartStringLiteralRegex.FindAllStringSubmatchIndex(content, -1) {
+		start, end := match[2], match[3]
+		if start < 0 {
+			start, end = match[4], match[5]
+		}
+		literal := content[start:end]
+
+		replacement, ok := renamed[literal]
+		if ok && !strings.Contains(literal, ".") && !translated[start] {
+			ok = false
+		}
+		if !ok && strings.Contains(literal, "$") && strings.HasPrefix(literal, oldKey+".") {
+			replacement, ok = newKey+strings.TrimPrefix(literal, oldKey), true
+		}
+		if !ok {
+			continue
+		}
+
+		out.WriteString(content[last:start])
+		out.WriteString(replacement)
+		last = end
+		count++
+	}
+	out.WriteString(content[last:])
+	content = out.String()
+
+	// LocaleKeys.home.title from fdawg's generated class, or LocaleKeys.home_title
+	// from easy_localization's generator
+	for _, key := range keys {
+		for _, accessor := range []struct{ from, to string }{
+			{localeKeysAccessor(key.From), localeKeysAccessor(key.To)},
+			{"LocaleKeys." + strings.ReplaceAll(key.From, ".", "_"), "LocaleKeys." + strings.ReplaceAll(key.To, ".", "_")},
+		} {
+			regex := regexp.MustCompile(`\b` + regexp.QuoteMeta(accessor.from) + `(` + localeKeysHelperSuffix + `)?\b`)
+			content = regex.ReplaceAllStringFunc(content, func(match string) string {
+				count++
+				return accessor.to + strings.TrimPrefix(match, accessor.from)
+			})
+		}
+	}
+
+	return content, count
+}
+
+// localeKeysAccessor returns the reference to a key in fdawg's generated LocaleKeys class
+func localeKeysAccessor(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = dartMemberName(part)
+	}
+	return "LocaleKeys." + strings.Join(parts, ".")
+}
+
+var (
+	// localizationsVariableRegex matches a variable assigned from the generated localizations,
+	// e.g. final l10n = AppLocalizations.of(context)!;
+	localizationsVariableRegex = regexp.MustCompile(`\b(\w+)\s*=\s*AppLocalizations\.of\(`)
+	// localizationsTypedRegex matches a variable or parameter declared as AppLocalizations
+	localizationsTypedRegex = regexp.MustCompile(`\bAppLocalizations\??\s+(\w+)\s*[,;=)]`)
+	// localizationsGetterRegex matches a getter returning the localizations, usually an
+	// extension on BuildContext, e.g. AppLocalizations get l10n => AppLocalizations.of(this)!;
+	localizationsGetterRegex = regexp.MustCompile(`\bget\s+(\w+)\s*=>\s*AppLocalizations\.of\(`)
+)
+
+// findLocalizationsGetters returns the names of getters in lib/ that return the
+// generated localizations, which can be used from any file
+func findLocalizationsGetters(projectPath string) ([]string, error) {
+	seen := make(map[string]bool)
+	var getters []string
+	err := scanDartFiles(projectPath, func(relPath, content string) error {
+		for _, match := range localizationsGetterRegex.FindAllStringSubmatch(content, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				getters = append(getters, match[1])
+			}
+		}
+		return nil
+	})
+	return getters, err
+}
+
+// rewriteARBReferences renames the gen-l10n getter of a key where it's read from the
+// localizations: AppLocalizations.of(context)!.oldKey, a variable assigned from it or
+// declared as AppLocalizations, or a getter returning it such as context.l10n.oldKey.
+// Other accesses named like the key, e.g. widget.oldKey, are left alone and their lines
+// returned as candidates to check by hand.
+func rewriteARBReferences(content, oldKey, newKey string, getters []string) (string, int, []int) {
+	usesLocalizations := strings.Contains(content, "AppLocalizations") || strings.Contains(content, "l10n")
+	for _, name := range getters {
+		usesLocalizations = usesLocalizations || strings.Contains(content, "."+name)
+	}
+	if !usesLocalizations {
+		return content, 0, nil
+	}
+
+	receivers := []string{`AppLocalizations\.of\([^()]*\)`}
+	seen := make(map[string]bool)
+	addReceiver := func(name string) {
+		if name != "get" && !seen[name] {
+			seen[name] = true
+			receivers = append(receivers, `\b`+regexp.QuoteMeta(name))
+		}
+	}
+	for _, name := range getters {
+		addReceiver(name)
+	}
+	for _, match := range localizationsVariableRegex.FindAllStringSubmatch(content, -1) {
+		addReceiver(match[1])
+	}
+	for _, match := range localizationsTypedRegex.FindAllStringSubmatch(content, -1) {
+		addReceiver(match[1])
+	}
+
+	count := 0
+	regex := regexp.MustCompile(`((?:` + strings.Join(receivers, "|") + `)\s*[!?]?\s*\.\s*)` + regexp.QuoteMeta(oldKey) + `\b`)
+	content = regex.ReplaceAllStringFunc(content, func(match string) string {
+		count++
+		return strings.TrimSuffix(match, oldKey) + newKey
+	})
+
+	var candidates []int
+	candidateRegex := regexp.MustCompile(`\.\s*` + regexp.QuoteMeta(oldKey) + `\b`)
+	for _, loc := range candidateRegex.FindAllStringIndex(content, -1) {
+		candidates = append(candidates, strings.Count(content[:loc[0]], "\n")+1)
+	}
+	return content, count, candidates
+}
@@ -0,0 +1,410 @@
+package localization
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jerinji2016/fdawg/pkg/translate"
+)
+
+const (
+	// StatusMachine is a machine translation nobody has checked yet
+	StatusMachine = "machine"
+
+	// StatusNeedsReview is a translation flagged for a check, e.g. because the source text changed
+	StatusNeedsReview = "needs-review"
+
+	// StatusReviewed is a translation written or approved by a person
+	StatusReviewed = "reviewed"
+
+	// ReviewStatusFileName is the file in the state directory holding the review statuses
+	ReviewStatusFileName = "translation_status.json"
+)
+
+// ReviewStatuses lists every review status, in workflow order
+var ReviewStatuses = []string{StatusMachine, StatusNeedsReview, StatusReviewed}
+
+// ReviewEntry is the review status of one translation value. Hash fingerprints the
+// value the status was recorded for, so a value edited outside fdawg drops its status.
+type ReviewEntry struct {
+	Status    string    `json:"status"`
+	Hash      string    `json:"hash"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReviewState holds the review statuses of a project's translations, by language and
+// key. It lives in a sidecar file so the translation files stay clean.
+type ReviewState struct {
+	path      string
+	Languages map[string]map[string]ReviewEntry `json:"languages"`
+}
+
+// ReviewItem is a translation value with its review status
+type ReviewItem struct {
+	Key      string `json:"key"`
+	Language string `json:"language"`
+	Status   string `json:"status"`
+	Source   string `json:"source"`
+	Value    string `json:"value"`
+}
+
+// GetReviewStatusPath returns the path to the review status file for a Flutter project
+func GetReviewStatusPath(projectPath string) string {
+	return filepath.Join(projectPath, translate.StateDirName, ReviewStatusFileName)
+}
+
+// IsValidReviewStatus reports whether status is one of ReviewStatuses
+func IsValidReviewStatus(status string) bool {
+	return containsString(ReviewStatuses, status)
+}
+
+// LoadReviewState reads the project's review statuses, which are empty if the file doesn't exist
+func LoadReviewState(projectPath string) (*ReviewState, error) {
+	state := &ReviewState{
+		path:      GetReviewStatusPath(projectPath),
+		Languages: make(map[string]map[string]ReviewEntry),
+	}
+
+	data, err := os.ReadFile(state.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review statuses: %v", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse review statuses %s: %v", state.path, err)
+	}
+	if state.Languages == nil {
+		state.Languages = make(map[string]map[string]ReviewEntry)
+	}
+
+	return state, nil
+}
+
+// Save writes the review statuses
+func (s *ReviewState) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal review statuses: %v", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write review statuses: %v", err)
+	}
+
+	return nil
+}
+
+// Status returns the review status of a value. Values without a recorded status,
+// or edited since it was recorded, were written by a person and count as reviewed.
+func (s *ReviewState) Status(language, key string, value interface{}) string {
+	entry, ok := s.Languages[language][key]
+	if !ok || entry.Hash != reviewValueHash(value) {
+		return StatusReviewed
+	}
+	return entry.Status
+}
+
+// Set records the review status of a value. Reviewed values need no entry, as
+// that's the status of any value without one.
+func (s *ReviewState) Set(language, key string, value interface{}, status string) {
+	if status == StatusReviewed {
+		s.Remove(language, key)
+		return
+	}
+
+	if s.Languages[language] == nil {
+		s.Languages[language] = make(map[string]ReviewEntry)
+	}
+	s.Languages[language][key] = ReviewEntry{
+		Status:    status,
+		Hash:      reviewValueHash(value),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Remove drops the review status of a value
+func (s *ReviewState) Remove(language, key string) {
+	delete(s.Languages[language], key)
+	if len(s.Languages[language]) == 0 {
+		delete(s.Languages, language)
+	}
+}
+
+// Prune drops statuses of keys and languages that no longer exist, and of values
+// edited since their status was recorded
+func (s *ReviewState) Prune(translationFiles []TranslationFile) {
+	files := make(map[string]TranslationFile)
+	for _, file := range translationFiles {
+		files[file.Language] = file
+	}
+
+	for language, entries := range s.Languages {
+		file, ok := files[language]
+		for key, entry := range entries {
+			var value interface{}
+			if ok {
+				value, _ = getTranslationValue(file.Data, key)
+			}
+			if !ok || isEmptyValue(value) || entry.Hash != reviewValueHash(value) {
+				s.Remove(language, key)
+			}
+		}
+	}
+}
+
+// reviewValueHash fingerprints a translation value. Plural and gender objects
+// marshal with sorted keys, so the hash doesn't depend on form order.
+func reviewValueHash(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%v", value))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// TranslationReviewStatuses returns the review status of each translated value by
+// language and key, for every language but the default one. Empty values are left out.
+func TranslationReviewStatuses(translationFiles []TranslationFile, defaultLanguage string, state *ReviewState) map[string]map[string]string {
+	statuses := make(map[string]map[string]string)
+	for _, file := range withoutPseudoLocales(translationFiles) {
+		if file.Language == defaultLanguage {
+			continue
+		}
+
+		statuses[file.Language] = make(map[string]string)
+		for _, key := range MessageKeys(file.Data) {
+			value, _ := getTranslationValue(file.Data, key)
+			if isEmptyValue(value) {
+				continue
+			}
+			statuses[file.Language][key] = state.Status(file.Language, key, value)
+		}
+	}
+	return statuses
+}
+
+// ReviewStatusCounts counts the translated values of each language but the default
+// one by review status
+func ReviewStatusCounts(translationFiles []TranslationFile, defaultLanguage string, state *ReviewState) map[string]map[string]int {
+	counts := make(map[string]map[string]int)
+	for language, keys := range TranslationReviewStatuses(translationFiles, defaultLanguage, state) {
+		counts[language] = make(map[string]int)
+		for _, status := range ReviewStatuses {
+			counts[language][status] = 0
+		}
+		for _, status := range keys {
+			counts[language][status]++
+		}
+	}
+	return counts
+}
+
+// ListReviewItems returns the translated values with one of the given statuses,
+// sorted by language and key. An empty language lists every language but the default.
+func ListReviewItems(projectPath string, statuses []string, language string) ([]ReviewItem, error) {
+	backend := DetectBackend(projectPath)
+	defaultLanguage := backend.DefaultLanguage()
+
+	if language == defaultLanguage {
+		return nil, fmt.Errorf("%s is the default language, whose values aren't reviewed", language)
+	}
+
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+	// Pseudo-locales are generated from the default language, not translated
+	translationFiles = withoutPseudoLocales(translationFiles)
+
+	state, err := LoadReviewState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var source map[string]interface{}
+	found := language == ""
+	for _, file := range translationFiles {
+		if file.Language == defaultLanguage {
+			source = file.Data
+		}
+		if file.Language == language {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("language %s not found", language)
+	}
+
+	items := []ReviewItem{}
+	for _, file := range translationFiles {
+		if file.Language == defaultLanguage || (language != "" && file.Language != language) {
+			continue
+		}
+
+		for _, key := range MessageKeys(file.Data) {
+			value, _ := getTranslationValue(file.Data, key)
+			if isEmptyValue(value) {
+				continue
+			}
+
+			status := state.Status(file.Language, key, value)
+			if len(statuses) > 0 && !containsString(statuses, status) {
+				continue
+			}
+
+			sourceValue, _ := getTranslationValue(source, key)
+			items = append(items, ReviewItem{
+				Key:      key,
+				Language: file.Language,
+				Status:   status,
+				Source:   reviewDisplayText(sourceValue),
+				Value:    reviewDisplayText(value),
+			})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Language != items[j].Language {
+			return items[i].Language < items[j].Language
+		}
+		return items[i].Key < items[j].Key
+	})
+
+	return items, nil
+}
+
+// reviewDisplayText formats a value for review, with plural and gender forms one
+// "form: text" per line
+func reviewDisplayText(value interface{}) string {
+	message := ParseMessage(value)
+	if message.Kind != MessageText {
+		return FormatFormsText(message)
+	}
+	return message.Text
+}
+
+// SetReviewStatus records the review status of the current value of a key in a language
+func SetReviewStatus(projectPath, language, key, status string) error {
+	if !IsValidReviewStatus(status) {
+		return fmt.Errorf("invalid review status %q, expected one of: %s", status, strings.Join(ReviewStatuses, ", "))
+	}
+
+	backend := DetectBackend(projectPath)
+	if language == backend.DefaultLanguage() {
+		return fmt.Errorf("%s is the default language, whose values aren't reviewed", language)
+	}
+
+	data, err := readTranslationFile(backend.FilePath(language))
+	if err != nil {
+		return fmt.Errorf("failed to read %s translations: %v", language, err)
+	}
+
+	value, ok := getTranslationValue(data, key)
+	if !ok || isEmptyValue(value) {
+		return fmt.Errorf("key %s has no %s translation", key, language)
+	}
+
+	state, err := LoadReviewState(projectPath)
+	if err != nil {
+		return err
+	}
+
+	state.Set(language, key, value, status)
+	return state.Save()
+}
+
+// MarkReviewStatus records a status for the current values of a key in the given
+// languages, skipping the default language and empty values
+func MarkReviewStatus(projectPath, key string, languages []string, status string) error {
+	if len(languages) == 0 {
+		return nil
+	}
+
+	backend := DetectBackend(projectPath)
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return fmt.Errorf("failed to list translation files: %v", err)
+	}
+
+	state, err := LoadReviewState(projectPath)
+	if err != nil {
+		return err
+	}
+
+	state.Prune(translationFiles)
+	for _, file := range translationFiles {
+		if file.Language == backend.DefaultLanguage() || !containsString(languages, file.Language) {
+			continue
+		}
+		if value, ok := getTranslationValue(file.Data, key); ok && !isEmptyValue(value) {
+			state.Set(file.Language, key, value, status)
+		}
+	}
+
+	return state.Save()
+}
+
+// MarkSourceChanged flags the translations of a key for review after its default
+// language text changed. Machine translations keep their status.
+func MarkSourceChanged(projectPath, key string) error {
+	backend := DetectBackend(projectPath)
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return fmt.Errorf("failed to list translation files: %v", err)
+	}
+
+	state, err := LoadReviewState(projectPath)
+	if err != nil {
+		return err
+	}
+
+	state.Prune(translationFiles)
+	for _, file := range translationFiles {
+		if file.Language == backend.DefaultLanguage() {
+			continue
+		}
+		value, ok := getTranslationValue(file.Data, key)
+		if !ok || isEmptyValue(value) || state.Status(file.Language, key, value) == StatusMachine {
+			continue
+		}
+		state.Set(file.Language, key, value, StatusNeedsReview)
+	}
+
+	return state.Save()
+}
+
+// ReviseTranslation replaces the value of a key in one language with a corrected
+// message, which a person wrote and so counts as reviewed
+func ReviseTranslation(projectPath, language, key string, message Message) error {
+	backend := DetectBackend(projectPath)
+	if language == backend.DefaultLanguage() {
+		return fmt.Errorf("%s is the default language, whose values aren't reviewed", language)
+	}
+
+	if err := setKeyInTranslationFile(backend.FilePath(language), key, backend.FormatMessage(message)); err != nil {
+		return fmt.Errorf("failed to write %s: %v", language, err)
+	}
+
+	state, err := LoadReviewState(projectPath)
+	if err != nil {
+		return err
+	}
+
+	state.Remove(language, key)
+	return state.Save()
+}
@@ -0,0 +1,522 @@
+package localization
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jerinji2016/fdawg/pkg/translate"
+)
+
+// OrphanReportFileName is the file in the state directory collecting keys removed by a sync
+const OrphanReportFileName = "orphaned_translations.json"
+
+// SyncOptions controls how translation files are synced with the default language
+type SyncOptions struct {
+	// Languages limits the sync to these languages; empty syncs every non-default language
+	Languages []string
+
+	// UseSource fills missing keys with the default language's text instead of an
+	// empty value. The copies are marked as needing review.
+	UseSource bool
+
+	// DryRun reports what would change without writing anything
+	DryRun bool
+}
+
+// SyncLanguageResult reports the changes made to one language's file
+type SyncLanguageResult struct {
+	Language string `json:"language"`
+	// Added lists keys missing from the language
+	Added []string `json:"added"`
+	// Repaired lists plural and gender keys that were missing forms
+	Repaired []string `json:"repaired"`
+	// Orphaned lists keys that don't exist in the default language, or whose
+	// value had a different kind, and were moved to the orphan report
+	Orphaned []string `json:"orphaned"`
+	// Changed is true when the file was, or would be, rewritten
+	Changed bool `json:"changed"`
+}
+
+// SyncResult reports a sync of every language with the default language
+type SyncResult struct {
+	DefaultLanguage string               `json:"default_language"`
+	Languages       []SyncLanguageResult `json:"languages"`
+	// OrphanReport is the path of the orphan report, if any keys were orphaned
+	OrphanReport string `json:"orphan_report,omitempty"`
+}
+
+// OrphanReport holds translations removed by syncs, by language and key, so they
+// can be recovered by hand
+type OrphanReport struct {
+	UpdatedAt time.Time                         `json:"updated_at"`
+	Languages map[string]map[string]interface{} `json:"languages"`
+}
+
+// keyOrder is the key order of a translation object, with the order of each nested group
+type keyOrder struct {
+	keys     []string
+	children map[string]*keyOrder
+}
+
+// GetOrphanReportPath returns the path to the orphan report for a Flutter project
+func GetOrphanReportPath(projectPath string) string {
+	return filepath.Join(projectPath, translate.StateDirName, OrphanReportFileName)
+}
+
+// SyncTranslations rebuilds every non-default translation file with the default
+// language's keys, nesting and order. Existing values are kept, missing keys are
+// added and keys the default language doesn't have are moved to the orphan report.
+func SyncTranslations(projectPath string, options SyncOptions) (*SyncResult, error) {
+	backend := DetectBackend(projectPath)
+	defaultLanguage := backend.DefaultLanguage()
+
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+	// Pseudo-locales are generated from the default language, not translated
+	translationFiles = withoutPseudoLocales(translationFiles)
+
+	var source *TranslationFile
+	languages := make(map[string]bool)
+	for i, file := range translationFiles {
+		languages[file.Language] = true
+		if file.Language == defaultLanguage {
+			source = &translationFiles[i]
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("default language file (%s) not found. Please run 'fdawg lang init' first to initialize localization", defaultLanguage)
+	}
+
+	for _, language := range options.Languages {
+		if language == defaultLanguage {
+			return nil, fmt.Errorf("%s is the default language and can't be synced", language)
+		}
+		if !languages[language] {
+			return nil, fmt.Errorf("language %s not found", language)
+		}
+	}
+
+	order, err := readKeyOrder(source.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", source.Path, err)
+	}
+
+	result := &SyncResult{DefaultLanguage: defaultLanguage}
+	orphans := make(map[string]map[string]interface{})
+	copied := make(map[string]map[string]interface{})
+	// Files are only written once the orphaned values are safe in the report
+	var writes []func() error
+
+	for _, file := range translationFiles {
+		if file.Language == defaultLanguage {
+			continue
+		}
+		if len(options.Languages) > 0 && !containsString(options.Languages, file.Language) {
+			continue
+		}
+
+		synced, languageResult, orphaned, filled := syncTranslationData(backend, source.Data, file, options.UseSource)
+		if len(orphaned) > 0 {
+			orphans[file.Language] = orphaned
+		}
+		if len(filled) > 0 {
+			copied[file.Language] = filled
+		}
+
+		if filepath.Ext(file.Path) == ".arb" {
+			// ARB files are always written in key order, so only content changes count
+			languageResult.Changed = len(languageResult.Added) > 0 || len(languageResult.Repaired) > 0 || len(languageResult.Orphaned) > 0
+			if languageResult.Changed {
+				writes = append(writes, func() error {
+					if err := writeTranslationFile(file.Path, synced); err != nil {
+						return fmt.Errorf("failed to write %s: %v", file.Language, err)
+					}
+					return nil
+				})
+			}
+		} else {
+			content, err := encodeOrderedTranslations(synced, order)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %v", file.Language, err)
+			}
+			existing, _ := os.ReadFile(file.Path)
+			languageResult.Changed = !bytes.Equal(bytes.TrimSpace(existing), bytes.TrimSpace(content))
+			if languageResult.Changed {
+				writes = append(writes, func() error {
+					if err := os.WriteFile(file.Path, content, 0644); err != nil {
+						return fmt.Errorf("failed to write %s: %v", file.Language, err)
+					}
+					return nil
+				})
+			}
+		}
+
+		result.Languages = append(result.Languages, languageResult)
+	}
+
+	if options.DryRun {
+		return result, nil
+	}
+
+	// Save the orphaned values before any file drops them, so a failure loses nothing
+	if len(orphans) > 0 {
+		if err := saveOrphanReport(projectPath, orphans); err != nil {
+			return nil, fmt.Errorf("%v; no translation files were changed", err)
+		}
+		result.OrphanReport = GetOrphanReportPath(projectPath)
+	}
+
+	for _, write := range writes {
+		if err := write(); err != nil {
+			return result, err
+		}
+	}
+
+	// Statuses of orphaned keys go with them, and copied source texts need a translator
+	if state, err := LoadReviewState(projectPath); err == nil {
+		if updatedFiles, err := listTranslationFiles(backend); err == nil {
+			state.Prune(updatedFiles)
+		}
+		for language, values := range copied {
+			for key, value := range values {
+				state.Set(language, key, value, StatusNeedsReview)
+			}
+		}
+		if err := state.Save(); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// syncTranslationData builds a language's translations in the shape of the default
+// language's. It returns the new translations, the changes, the orphaned values and
+// the values copied from the source, both by key.
+func syncTranslationData(backend Backend, source map[string]interface{}, file TranslationFile, useSource bool) (map[string]interface{}, SyncLanguageResult, map[string]interface{}, map[string]interface{}) {
+	result := SyncLanguageResult{
+		Language: file.Language,
+		Added:    []string{},
+		Repaired: []string{},
+		Orphaned: []string{},
+	}
+	synced := make(map[string]interface{})
+	orphaned := make(map[string]interface{})
+	copied := make(map[string]interface{})
+
+	sourceKeys := make(map[string]bool)
+	for _, key := range MessageKeys(source) {
+		sourceKeys[key] = true
+		sourceValue, _ := getTranslationValue(source, key)
+		sourceMessage := ParseMessage(sourceValue)
+
+		value, exists := getTranslationValue(file.Data, key)
+		if exists {
+			if nested, ok := value.(map[string]interface{}); ok && !IsMessageMap(nested) && !isSingleFormPlural(nested, sourceMessage.Kind) {
+				// A group where the default language has a message; its keys are orphaned below
+				exists = false
+			}
+		}
+
+		if exists {
+			message := parseMessageAs(value, sourceMessage.Kind)
+			if message.Kind == sourceMessage.Kind {
+				if message.Kind != MessageText {
+					missing := false
+					for _, form := range RequiredForms(message.Kind, file.Language) {
+						if _, ok := message.Forms[form]; !ok {
+							message.Forms[form] = ""
+							missing = true
+						}
+					}
+					if missing {
+						if message.Argument == "" || sourceMessage.Argument != "" {
+							message.Argument = sourceMessage.Argument
+						}
+						value = backend.FormatMessage(message)
+						result.Repaired = append(result.Repaired, key)
+					}
+				}
+				setTranslationValue(synced, key, value)
+				continue
+			}
+
+			orphaned[key] = value
+			result.Orphaned = append(result.Orphaned, key)
+		}
+
+		fresh := syncPlaceholder(sourceMessage, file.Language, useSource)
+		value = backend.FormatMessage(fresh)
+		setTranslationValue(synced, key, value)
+		result.Added = append(result.Added, key)
+		if useSource && !isEmptyValue(value) {
+			copied[key] = value
+		}
+	}
+
+	for _, key := range MessageKeys(file.Data) {
+		if sourceKeys[key] {
+			continue
+		}
+		// The form of a single form plural, e.g. in Japanese, belongs to the message kept above
+		if parent := strings.TrimSuffix(key, ".other"); parent != key && sourceKeys[parent] {
+			if sourceValue, _ := getTranslationValue(source, parent); ParseMessage(sourceValue).Kind == MessagePlural {
+				continue
+			}
+		}
+		value, _ := getTranslationValue(file.Data, key)
+		orphaned[key] = value
+		result.Orphaned = append(result.Orphaned, key)
+	}
+	sort.Strings(result.Orphaned)
+
+	return synced, result, orphaned, copied
+}
+
+// syncPlaceholder returns the value added for a missing key: empty, or the default
+// language's text. Plural and gender messages get the forms the language needs.
+func syncPlaceholder(source Message, language string, useSource bool) Message {
+	if source.Kind == MessageText {
+		if useSource {
+			return Message{Kind: MessageText, Text: source.Text}
+		}
+		return Message{Kind: MessageText}
+	}
+
+	forms := make(map[string]string)
+	for _, form := range RequiredForms(source.Kind, language) {
+		forms[form] = ""
+		if useSource {
+			if text, ok := source.Forms[form]; ok {
+				forms[form] = text
+			} else {
+				forms[form] = source.Forms["other"]
+			}
+		}
+	}
+	if useSource {
+		// Explicit values such as =0 aren't required, but keep the source's
+		for form, text := range source.Forms {
+			if strings.HasPrefix(form, "=") {
+				forms[form] = text
+			}
+		}
+	}
+
+	return Message{Kind: source.Kind, Forms: forms, Argument: source.Argument}
+}
+
+// readKeyOrder reads the order of the keys of a JSON translation file, which
+// decoding into a map loses
+func readKeyOrder(path string) (*keyOrder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("failed to parse JSON: expected an object")
+	}
+
+	return readObjectKeyOrder(decoder)
+}
+
+// readObjectKeyOrder reads the rest of a JSON object whose opening brace was read
+func readObjectKeyOrder(decoder *json.Decoder) (*keyOrder, error) {
+	order := &keyOrder{children: make(map[string]*keyOrder)}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+		key, _ := token.(string)
+		order.keys = append(order.keys, key)
+
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+
+		switch token {
+		case json.Delim('{'):
+			child, err := readObjectKeyOrder(decoder)
+			if err != nil {
+				return nil, err
+			}
+			order.children[key] = child
+		case json.Delim('['):
+			if err := skipJSONArray(decoder); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Closing brace
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+
+	return order, nil
+}
+
+// skipJSONArray skips the rest of a JSON array whose opening bracket was read
+func skipJSONArray(decoder *json.Decoder) error {
+	for depth := 1; depth > 0; {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("failed to parse JSON: %v", err)
+		}
+		switch token {
+		case json.Delim('['), json.Delim('{'):
+			depth++
+		case json.Delim(']'), json.Delim('}'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// encodeOrderedTranslations encodes translations as indented JSON with keys in the
+// given order. Keys the order doesn't list follow in alphabetical order, and plural
+// and gender forms are written in their conventional order.
+func encodeOrderedTranslations(data map[string]interface{}, order *keyOrder) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeOrderedObject(&buf, data, order, ""); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// writeOrderedObject writes one JSON object of translations at the given indent
+func writeOrderedObject(buf *bytes.Buffer, data map[string]interface{}, order *keyOrder, indent string) error {
+	if len(data) == 0 {
+		buf.WriteString("{}")
+		return nil
+	}
+
+	var keys []string
+	if order != nil {
+		for _, key := range order.keys {
+			if _, ok := data[key]; ok && !containsString(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	var rest []string
+	for key := range data {
+		if !containsString(keys, key) {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	if order == nil {
+		if nested := messageMapForms(data); nested != nil {
+			keys = SortForms(nested)
+		}
+	}
+
+	buf.WriteString("{")
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n" + indent + "  ")
+
+		keyJSON, err := encodeJSONValue(key, "")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		buf.Write(keyJSON)
+		buf.WriteString(": ")
+
+		if nested, ok := data[key].(map[string]interface{}); ok {
+			var child *keyOrder
+			if order != nil && !IsMessageMap(nested) {
+				child = order.children[key]
+			}
+			if err := writeOrderedObject(buf, nested, child, indent+"  "); err != nil {
+				return err
+			}
+			continue
+		}
+
+		valueJSON, err := encodeJSONValue(data[key], indent+"  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		buf.Write(valueJSON)
+	}
+	buf.WriteString("\n" + indent + "}")
+
+	return nil
+}
+
+// messageMapForms returns the forms of a plural or gender object, or nil for a group of keys
+func messageMapForms(data map[string]interface{}) map[string]string {
+	if !IsMessageMap(data) {
+		return nil
+	}
+	forms := make(map[string]string)
+	for form, text := range data {
+		forms[form], _ = text.(string)
+	}
+	return forms
+}
+
+// saveOrphanReport merges orphaned values into the orphan report. Values orphaned
+// again replace the earlier ones.
+func saveOrphanReport(projectPath string, orphans map[string]map[string]interface{}) error {
+	path := GetOrphanReportPath(projectPath)
+	report := OrphanReport{Languages: make(map[string]map[string]interface{})}
+
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &report); err != nil {
+			return fmt.Errorf("failed to parse orphan report %s: %v", path, err)
+		}
+		if report.Languages == nil {
+			report.Languages = make(map[string]map[string]interface{})
+		}
+	}
+
+	for language, values := range orphans {
+		if report.Languages[language] == nil {
+			report.Languages[language] = make(map[string]interface{})
+		}
+		for key, value := range values {
+			report.Languages[language][key] = value
+		}
+	}
+	report.UpdatedAt = time.Now()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal orphan report: %v", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write orphan report: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package localization
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// IssueMissingPlaceholder is a placeholder in the default language missing from a translation
+	IssueMissingPlaceholder = "missing_placeholder"
+
+	// IssueExtraPlaceholder is a placeholder in a translation that the default language doesn't have
+	IssueExtraPlaceholder = "extra_placeholder"
+
+	// IssueRenamedPlaceholder is a placeholder that appears under a different name in a translation
+	IssueRenamedPlaceholder = "renamed_placeholder"
+
+	// IssueEmptyValue is a translation with no text
+	IssueEmptyValue = "empty_value"
+
+	// IssueUntranslated is a translation identical to the default language text
+	IssueUntranslated = "untranslated"
+
+	// SeverityError marks issues that break the app at runtime
+	SeverityError = "error"
+
+	// SeverityWarning marks issues that should be reviewed
+	SeverityWarning = "warning"
+)
+
+// positionalPlaceholder is the easy_localization positional argument, replaced in order by tr(args: [...])
+const positionalPlaceholder = "{}"
+
+// placeholderRegex matches positional {} and named {name} placeholders
+var placeholderRegex = regexp.MustCompile(`\{\s*([a-zA-Z_][a-zA-Z0-9_]*)?\s*\}`)
+
+// ValidationIssue describes a problem with one translation of a key
+type ValidationIssue struct {
+	Key      string   `json:"key"`
+	Language string   `json:"language"`
+	Type     string   `json:"type"`
+	Severity string   `json:"severity"`
+	Message  string   `json:"message"`
+	Expected []string `json:"expected,omitempty"`
+	Found    []string `json:"found,omitempty"`
+}
+
+// ValidationReport is the result of checking every translation against the default language
+type ValidationReport struct {
+	DefaultLanguage string            `json:"defaultLanguage"`
+	Languages       []string          `json:"languages"`
+	Keys            int               `json:"keys"`
+	Errors          int               `json:"errors"`
+	Warnings        int               `json:"warnings"`
+	Issues          []ValidationIssue `json:"issues"`
+}
+
+// placeholderSet holds the named placeholders of a value and the number of positional ones
+type placeholderSet struct {
+	named      map[string]bool
+	positional int
+}
+
+// ValidateTranslations checks placeholders and values of every language against the default language
+func ValidateTranslations(projectPath string) (*ValidationReport, error) {
+	backend := DetectBackend(projectPath)
+
+	translationFiles, err := listTranslationFiles(backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation files: %v", err)
+	}
+	// Pseudo-locales are generated from the default language, not translated
+	translationFiles = withoutPseudoLocales(translationFiles)
+
+	if len(translationFiles) == 0 {
+		return nil, fmt.Errorf("no translation files found. Please run 'fdawg lang init' first to initialize localization")
+	}
+
+	return CheckTranslations(translationFiles, backend.DefaultLanguage())
+}
+
+// CheckTranslations compares every translation file to the default language file
+func CheckTranslations(translationFiles []TranslationFile, defaultLanguage string) (*ValidationReport, error) {
+	var source *TranslationFile
+	for i := range translationFiles {
+		if translationFiles[i].Language == defaultLanguage {
+			source = &translationFiles[i]
+			break
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("default language file (%s) not found", defaultLanguage)
+	}
+
+	report := &ValidationReport{
+		DefaultLanguage: defaultLanguage,
+		Issues:          []ValidationIssue{},
+	}
+
+	keys := MessageKeys(source.Data)
+	report.Keys = len(keys)
+
+	for _, file := range translationFiles {
+		report.Languages = append(report.Languages, file.Language)
+		if file.Language == defaultLanguage {
+			continue
+		}
+
+		for _, key := range keys {
+			sourceValue, _ := getTranslationValue(source.Data, key)
+			value, _ := getTranslationValue(file.Data, key)
+			report.Issues = append(report.Issues, checkTranslation(key, file.Language, defaultLanguage, sourceValue, value)...)
+		}
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Severity == SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+
+	return report, nil
+}
+
+// checkTranslation compares one translated value to its default language value
+func checkTranslation(key, language, defaultLanguage string, sourceValue, value interface{}) []ValidationIssue {
+	sourceText := messageText(ParseMessage(sourceValue))
+	text := messageText(ParseMessage(value))
+
+	// Keys without default text have nothing to compare against
+	if strings.TrimSpace(sourceText) == "" {
+		return nil
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return []ValidationIssue{{
+			Key:      key,
+			Language: language,
+			Type:     IssueEmptyValue,
+			Severity: SeverityWarning,
+			Message:  "translation is empty",
+		}}
+	}
+
+	var issues []ValidationIssue
+	issue := func(issueType, severity, message string, expected, found []string) {
+		issues = append(issues, ValidationIssue{
+			Key:      key,
+			Language: language,
+			Type:     issueType,
+			Severity: severity,
+			Message:  message,
+			Expected: expected,
+			Found:    found,
+		})
+	}
+
+	expected := extractPlaceholders(sourceValue)
+	found := extractPlaceholders(value)
+
+	var missing, extra []string
+	for name := range expected.named {
+		if !found.named[name] {
+			missing = append(missing, name)
+		}
+	}
+	for name := range found.named {
+		if !expected.named[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+
+	// A placeholder missing where another unexpected one appears was most likely renamed
+	for len(missing) > 0 && len(extra) > 0 {
+		issue(IssueRenamedPlaceholder, SeverityError,
+			fmt.Sprintf("placeholder {%s} appears to be renamed to {%s}", missing[0], extra[0]),
+			[]string{"{" + missing[0] + "}"}, []string{"{" + extra[0] + "}"})
+		missing, extra = missing[1:], extra[1:]
+	}
+	for _, name := range missing {
+		issue(IssueMissingPlaceholder, SeverityError,
+			fmt.Sprintf("missing placeholder {%s}", name),
+			[]string{"{" + name + "}"}, nil)
+	}
+	for _, name := range extra {
+		issue(IssueExtraPlaceholder, SeverityError,
+			fmt.Sprintf("unexpected placeholder {%s}", name),
+			nil, []string{"{" + name + "}"})
+	}
+
+	if expected.positional > found.positional {
+		issue(IssueMissingPlaceholder, SeverityError,
+			fmt.Sprintf("expected %d {} placeholder(s), found %d", expected.positional, found.positional),
+			repeatPlaceholder(expected.positional), repeatPlaceholder(found.positional))
+	} else if expected.positional < found.positional {
+		issue(IssueExtraPlaceholder, SeverityError,
+			fmt.Sprintf("expected %d {} placeholder(s), found %d", expected.positional, found.positional),
+			repeatPlaceholder(expected.positional), repeatPlaceholder(found.positional))
+	}
+
+	// Regional variants of the same language, such as en_GB and en_US, often share text
+	if text == sourceText && baseLanguage(language) != baseLanguage(defaultLanguage) && hasLetters(placeholderRegex.ReplaceAllString(text, "")) {
+		issue(IssueUntranslated, SeverityWarning,
+			fmt.Sprintf("identical to %s, probably untranslated", defaultLanguage),
+			nil, nil)
+	}
+
+	return issues
+}
+
+// extractPlaceholders returns the placeholders of a value. For plural and gender
+// messages the named placeholders of all forms are combined, and the positional
+// count is the largest of any form, as forms such as "one" often leave it out.
+func extractPlaceholders(value interface{}) placeholderSet {
+	set := placeholderSet{named: make(map[string]bool)}
+
+	message := ParseMessage(value)
+	texts := []string{message.Text}
+	if message.Kind != MessageText {
+		texts = nil
+		for _, form := range SortForms(message.Forms) {
+			texts = append(texts, message.Forms[form])
+		}
+	}
+
+	for _, text := range texts {
+		positional := 0
+		for _, match := range placeholderRegex.FindAllStringSubmatch(text, -1) {
+			if match[1] == "" {
+				positional++
+			} else {
+				set.named[match[1]] = true
+			}
+		}
+		if positional > set.positional {
+			set.positional = positional
+		}
+	}
+
+	return set
+}
+
+// messageText returns all the text of a message, used to compare translations
+func messageText(message Message) string {
+	if message.Kind == MessageText {
+		return message.Text
+	}
+	return FormatFormsText(message)
+}
+
+// repeatPlaceholder returns n positional placeholders
+func repeatPlaceholder(n int) []string {
+	var placeholders []string
+	for i := 0; i < n; i++ {
+		placeholders = append(placeholders, positionalPlaceholder)
+	}
+	return placeholders
+}
+
+// baseLanguage returns the language part of a code, e.g. pt for pt_BR
+func baseLanguage(code string) string {
+	return strings.ToLower(strings.Split(code, "_")[0])
+}
+
+// hasLetters reports whether text contains any letters, so values such as "OK"
+// are checked but "{} / {}" or "100%" are not
+func hasLetters(text string) bool {
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
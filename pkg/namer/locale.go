@@ -0,0 +1,702 @@
+package namer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jerinji2016/fdawg/pkg/localization"
+)
+
+// androidAppNameResource is the string resource fdawg points android:label at
+const androidAppNameResource = "app_name"
+
+// LocalizablePlatforms lists the platforms that support per-locale app names
+var LocalizablePlatforms = []Platform{PlatformAndroid, PlatformIOS, PlatformMacOS}
+
+// LocalizedAppName is the app name shown to users of one locale on one platform
+type LocalizedAppName struct {
+	Locale      string   `json:"locale"`
+	Platform    Platform `json:"platform"`
+	DisplayName string   `json:"display_name"`
+	Path        string   `json:"path"`
+}
+
+// LocalizedAppNameResult reports what setting or removing a localized app name changed
+type LocalizedAppNameResult struct {
+	Locale   string   `json:"locale"`
+	Files    []string `json:"files"`
+	Warnings []string `json:"warnings"`
+}
+
+// fileSnapshot is the content of a file before a change, kept to roll the change back
+type fileSnapshot struct {
+	path    string
+	content []byte
+	existed bool
+}
+
+// IsLocalizablePlatform reports whether a platform supports per-locale app names
+func IsLocalizablePlatform(platform Platform) bool {
+	for _, localizable := range LocalizablePlatforms {
+		if localizable == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeLocale validates a locale against the project's languages and returns it
+// in the format pkg/localization uses, e.g. "pt_BR". Projects without localization
+// accept any known language code.
+func NormalizeLocale(projectPath, locale string) (string, error) {
+	info, err := localization.GetLanguageInfo(locale)
+	if err != nil {
+		return "", err
+	}
+	formatted := info.String()
+
+	if !localization.IsInitialized(projectPath).IsInitialized {
+		return formatted, nil
+	}
+
+	translationFiles, err := localization.ListTranslationFiles(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to list translation files: %v", err)
+	}
+
+	var languages []string
+	for _, file := range translationFiles {
+		if file.Language == formatted {
+			return formatted, nil
+		}
+		languages = append(languages, file.Language)
+	}
+	sort.Strings(languages)
+
+	return "", fmt.Errorf("%s is not one of the project's languages (%s). Add it first with 'fdawg lang add %s'", formatted, strings.Join(languages, ", "), formatted)
+}
+
+// SetLocalizedAppNames writes the app names of request.Locale: Android
+// res/values-<lang>/strings.xml with the manifest label pointed at
+// @string/app_name, and <lang>.lproj/InfoPlist.strings on iOS and macOS.
+// Other platforms have no per-locale names and are reported as warnings.
+func SetLocalizedAppNames(request *SetAppNameRequest) (*LocalizedAppNameResult, error) {
+	if !isFlutterProject(request.ProjectPath) {
+		return nil, fmt.Errorf("not a valid Flutter project: %s", request.ProjectPath)
+	}
+
+	locale, err := NormalizeLocale(request.ProjectPath, request.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	platformsToUpdate, err := resolvePlatformNames(request)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ValidationReport{}
+	for _, platform := range sortedPlatforms(platformsToUpdate) {
+		if IsLocalizablePlatform(platform) {
+			report.add(ValidateAppName(platform, platformsToUpdate[platform])...)
+		}
+	}
+	if err := report.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &LocalizedAppNameResult{
+		Locale:   locale,
+		Files:    []string{},
+		Warnings: []string{},
+	}
+
+	var snapshots []fileSnapshot
+	for _, platform := range sortedPlatforms(platformsToUpdate) {
+		appName := platformsToUpdate[platform]
+
+		if !IsLocalizablePlatform(platform) {
+			if platform == PlatformWeb {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("web: manifest.json and index.html have a single app name, so the %s name was not applied", locale))
+			} else {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: app names can't be localized, so the %s name was not applied", platform, locale))
+			}
+			continue
+		}
+		if !isPlatformAvailable(request.ProjectPath, platform) {
+			restoreSnapshots(snapshots)
+			return nil, fmt.Errorf("platform %s not available in project", platform)
+		}
+
+		var files []string
+		var warnings []string
+		switch platform {
+		case PlatformAndroid:
+			files, err = setAndroidLocalizedAppName(request.ProjectPath, locale, appName, &snapshots)
+		case PlatformIOS, PlatformMacOS:
+			files, warnings, err = setAppleLocalizedAppName(request.ProjectPath, platform, locale, appName, &snapshots)
+		}
+		if err != nil {
+			restoreSnapshots(snapshots)
+			return nil, fmt.Errorf("failed to set %s app name for %s, no files were changed: %v", locale, platform, err)
+		}
+
+		result.Files = append(result.Files, files...)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	if len(result.Files) == 0 {
+		return nil, fmt.Errorf("none of the given platforms support localized app names; supported platforms: android, ios, macos")
+	}
+
+	return result, nil
+}
+
+// RemoveLocalizedAppNames removes the app names of a locale from every platform,
+// deleting the locale's strings.xml and InfoPlist.strings once nothing else is left in them
+func RemoveLocalizedAppNames(projectPath, locale string) (*LocalizedAppNameResult, error) {
+	info, err := localization.GetLanguageInfo(locale)
+	if err != nil {
+		return nil, err
+	}
+	locale = info.String()
+
+	result := &LocalizedAppNameResult{
+		Locale:   locale,
+		Files:    []string{},
+		Warnings: []string{},
+	}
+
+	names, err := GetLocalizedAppNames(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if name.Locale != locale {
+			continue
+		}
+
+		path := filepath.Join(projectPath, filepath.FromSlash(name.Path))
+		if name.Platform == PlatformAndroid {
+			err = removeAndroidStringResource(path, androidAppNameResourceName(projectPath))
+		} else {
+			err = removeInfoPlistStrings(path)
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to update %s: %v", name.Path, err)
+		}
+		result.Files = append(result.Files, name.Path)
+	}
+
+	return result, nil
+}
+
+// GetLocalizedAppNames returns the localized app names set in the project, sorted by locale and platform
+func GetLocalizedAppNames(projectPath string) ([]LocalizedAppName, error) {
+	names := []LocalizedAppName{}
+
+	if isPlatformAvailable(projectPath, PlatformAndroid) {
+		resource := androidAppNameResourceName(projectPath)
+		resDir := filepath.Join(projectPath, "android", "app", "src", "main", "res")
+		dirs, _ := filepath.Glob(filepath.Join(resDir, "values-*"))
+		for _, dir := range dirs {
+			locale, ok := localeFromAndroidQualifier(strings.TrimPrefix(filepath.Base(dir), "values-"))
+			if !ok {
+				continue
+			}
+
+			path := filepath.Join(dir, "strings.xml")
+			content, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			if value, ok := findAndroidStringResource(string(content), resource); ok {
+				names = append(names, LocalizedAppName{
+					Locale:      locale,
+					Platform:    PlatformAndroid,
+					DisplayName: value,
+					Path:        relativePath(projectPath, path),
+				})
+			}
+		}
+	}
+
+	for _, platform := range []Platform{PlatformIOS, PlatformMacOS} {
+		if !isPlatformAvailable(projectPath, platform) {
+			continue
+		}
+
+		dirs, _ := filepath.Glob(filepath.Join(projectPath, string(platform), "Runner", "*.lproj"))
+		for _, dir := range dirs {
+			locale, ok := localeFromAppleDir(strings.TrimSuffix(filepath.Base(dir), ".lproj"))
+			if !ok {
+				continue
+			}
+
+			path := filepath.Join(dir, "InfoPlist.strings")
+			content, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+
+			values := parseStringsFile(string(content))
+			displayName := values["CFBundleDisplayName"]
+			if displayName == "" {
+				displayName = values["CFBundleName"]
+			}
+			if displayName == "" {
+				continue
+			}
+
+			names = append(names, LocalizedAppName{
+				Locale:      locale,
+				Platform:    platform,
+				DisplayName: displayName,
+				Path:        relativePath(projectPath, path),
+			})
+		}
+	}
+
+	platformOrder := make(map[Platform]int)
+	for i, platform := range AllPlatforms() {
+		platformOrder[platform] = i
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Locale != names[j].Locale {
+			return names[i].Locale < names[j].Locale
+		}
+		return platformOrder[names[i].Platform] < platformOrder[names[j].Platform]
+	})
+
+	return names, nil
+}
+
+// Android
+
+// androidManifestPath returns the path to the main AndroidManifest.xml
+func androidManifestPath(projectPath string) string {
+	return filepath.Join(projectPath, "android", "app", "src", "main", "AndroidManifest.xml")
+}
+
+// androidStringsPath returns the path to strings.xml for a resource qualifier, "" being the default resources
+func androidStringsPath(projectPath, qualifier string) string {
+	dir := "values"
+	if qualifier != "" {
+		dir += "-" + qualifier
+	}
+	return filepath.Join(projectPath, "android", "app", "src", "main", "res", dir, "strings.xml")
+}
+
+// applicationLabelRegex matches the android:label attribute of the <application> element
+var applicationLabelRegex = regexp.MustCompile(`(<application\b[^>]*?\bandroid:label=")([^"]*)(")`)
+
+// androidLabelResource returns the string resource the application label refers
+// to, e.g. "app_name" for @string/app_name, or false for a literal label
+func androidLabelResource(manifest string) (string, bool) {
+	matches := applicationLabelRegex.FindStringSubmatch(manifest)
+	if len(matches) < 3 || !strings.HasPrefix(matches[2], "@string/") {
+		return "", false
+	}
+	return strings.TrimPrefix(matches[2], "@string/"), true
+}
+
+// androidAppNameResourceName returns the string resource holding the app name
+func androidAppNameResourceName(projectPath string) string {
+	content, err := os.ReadFile(androidManifestPath(projectPath))
+	if err == nil {
+		if resource, ok := androidLabelResource(string(content)); ok {
+			return resource
+		}
+	}
+	return androidAppNameResource
+}
+
+// setAndroidLocalizedAppName writes the app name to values-<lang>/strings.xml. A literal
+// android:label is first moved to values/strings.xml so the label can point at it.
+func setAndroidLocalizedAppName(projectPath, locale, appName string, snapshots *[]fileSnapshot) ([]string, error) {
+	manifestPath := androidManifestPath(projectPath)
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AndroidManifest.xml: %v", err)
+	}
+	manifest := string(content)
+
+	var files []string
+	resource, ok := androidLabelResource(manifest)
+	if !ok {
+		matches := applicationLabelRegex.FindStringSubmatch(manifest)
+		if matches == nil {
+			return nil, fmt.Errorf("android:label not found on <application> in AndroidManifest.xml")
+		}
+
+		resource = androidAppNameResource
+		defaultPath := androidStringsPath(projectPath, "")
+		if err := snapshotFile(defaultPath, snapshots); err != nil {
+			return nil, err
+		}
+		if err := setAndroidStringResource(defaultPath, resource, unescapeXMLAttribute(matches[2])); err != nil {
+			return nil, err
+		}
+
+		if err := snapshotFile(manifestPath, snapshots); err != nil {
+			return nil, err
+		}
+		manifest = applicationLabelRegex.ReplaceAllString(manifest, "${1}@string/"+resource+"${3}")
+		if err := os.WriteFile(manifestPath, []byte(manifest), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write AndroidManifest.xml: %v", err)
+		}
+		files = append(files, relativePath(projectPath, defaultPath), relativePath(projectPath, manifestPath))
+	}
+
+	path := androidStringsPath(projectPath, androidQualifier(locale))
+	if err := snapshotFile(path, snapshots); err != nil {
+		return nil, err
+	}
+	if err := setAndroidStringResource(path, resource, appName); err != nil {
+		return nil, err
+	}
+
+	return append(files, relativePath(projectPath, path)), nil
+}
+
+// androidStringResourceRegex returns a regex matching a <string> resource by name
+func androidStringResourceRegex(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?s)(<string\s+name="` + regexp.QuoteMeta(name) + `"[^>]*>)(.*?)(</string>)`)
+}
+
+// findAndroidStringResource returns the unescaped value of a string resource
+func findAndroidStringResource(content, name string) (string, bool) {
+	matches := androidStringResourceRegex(name).FindStringSubmatch(content)
+	if matches == nil {
+		return "", false
+	}
+	return unescapeAndroidString(matches[2]), true
+}
+
+// setAndroidStringResource sets a string resource in a strings.xml file, creating the file if needed
+func setAndroidStringResource(path, name, value string) error {
+	escaped := escapeAndroidString(value)
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		content = []byte("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<resources>\n</resources>\n")
+	} else if err != nil {
+		return fmt.Errorf("failed to read %s: %v", filepath.Base(path), err)
+	}
+	updated := string(content)
+
+	regex := androidStringResourceRegex(name)
+	if regex.MatchString(updated) {
+		updated = regex.ReplaceAllStringFunc(updated, func(match string) string {
+			parts := regex.FindStringSubmatch(match)
+			return parts[1] + escaped + parts[3]
+		})
+	} else {
+		end := strings.LastIndex(updated, "</resources>")
+		if end == -1 {
+			return fmt.Errorf("could not find </resources> in %s", path)
+		}
+		updated = updated[:end] + fmt.Sprintf("    <string name=\"%s\">%s</string>\n", name, escaped) + updated[end:]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// removeAndroidStringResource removes a string resource, deleting the file and its
+// directory if no resources are left
+func removeAndroidStringResource(path, name string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	regex := regexp.MustCompile(`(?s)[ \t]*<string\s+name="` + regexp.QuoteMeta(name) + `"[^>]*>.*?</string>[ \t]*\n?`)
+	updated := regex.ReplaceAllString(string(content), "")
+
+	if !regexp.MustCompile(`<(string|plurals|string-array|color|dimen|bool|integer|style|item)\b`).MatchString(updated) {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		os.Remove(filepath.Dir(path)) // Only removed if empty
+		return nil
+	}
+
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// androidQualifier returns the resource qualifier of a locale, e.g. "pt-rBR" for pt_BR
+func androidQualifier(locale string) string {
+	parts := strings.SplitN(locale, "_", 2)
+	if len(parts) == 2 {
+		return parts[0] + "-r" + parts[1]
+	}
+	return parts[0]
+}
+
+// androidQualifierRegex matches a language resource qualifier such as "ja" or "pt-rBR"
+var androidQualifierRegex = regexp.MustCompile(`^([a-z]{2,3})(?:-r([A-Z]{2}))?$`)
+
+// localeFromAndroidQualifier returns the locale of a resource qualifier, or false
+// for qualifiers that aren't a language, such as "night" or "v21"
+func localeFromAndroidQualifier(qualifier string) (string, bool) {
+	matches := androidQualifierRegex.FindStringSubmatch(qualifier)
+	if matches == nil || !localization.IsValidLanguageCode(matches[1]) {
+		return "", false
+	}
+	if matches[2] != "" {
+		return matches[1] + "_" + matches[2], true
+	}
+	return matches[1], true
+}
+
+// escapeAndroidString escapes a value for a strings.xml resource
+func escapeAndroidString(value string) string {
+	value = strings.NewReplacer(
+		`\`, `\\`,
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, `\"`,
+		"'", `\'`,
+		"\n", `\n`,
+	).Replace(value)
+	if strings.HasPrefix(value, "@") || strings.HasPrefix(value, "?") {
+		value = `\` + value
+	}
+	return value
+}
+
+// unescapeAndroidString reverses escapeAndroidString
+func unescapeAndroidString(value string) string {
+	value = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, "'", `\n`, "\n", `\@`, "@", `\?`, "?").Replace(value)
+	return unescapeXMLAttribute(value)
+}
+
+// unescapeXMLAttribute decodes the XML entities in an attribute or text value
+func unescapeXMLAttribute(value string) string {
+	return strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&amp;", "&").Replace(value)
+}
+
+// iOS and macOS
+
+// setAppleLocalizedAppName writes CFBundleDisplayName and CFBundleName to
+// Runner/<lang>.lproj/InfoPlist.strings, warning if the Xcode project doesn't include it
+func setAppleLocalizedAppName(projectPath string, platform Platform, locale, appName string, snapshots *[]fileSnapshot) ([]string, []string, error) {
+	dir := appleLocaleDir(locale)
+	path := filepath.Join(projectPath, string(platform), "Runner", dir+".lproj", "InfoPlist.strings")
+	if err := snapshotFile(path, snapshots); err != nil {
+		return nil, nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read InfoPlist.strings: %v", err)
+	}
+
+	updated := setStringsFileValue(string(content), "CFBundleDisplayName", appName)
+	updated = setStringsFileValue(updated, "CFBundleName", appName)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return nil, nil, fmt.Errorf("failed to write InfoPlist.strings: %v", err)
+	}
+
+	var warnings []string
+	pbxprojPath := filepath.Join(projectPath, string(platform), "Runner.xcodeproj", "project.pbxproj")
+	if pbxproj, err := os.ReadFile(pbxprojPath); err == nil {
+		if !strings.Contains(string(pbxproj), "InfoPlist.strings") {
+			warnings = append(warnings, fmt.Sprintf("%s: add InfoPlist.strings to the Runner target in Xcode and localize it, otherwise the %s name isn't bundled", platform, locale))
+		} else if !xcodeKnowsRegion(string(pbxproj), dir) {
+			warnings = append(warnings, fmt.Sprintf("%s: enable the %s localization of InfoPlist.strings in Xcode, otherwise the %s name isn't bundled", platform, dir, locale))
+		}
+	}
+
+	return []string{relativePath(projectPath, path)}, warnings, nil
+}
+
+// knownRegionsRegex matches the knownRegions list of an Xcode project
+var knownRegionsRegex = regexp.MustCompile(`(?s)knownRegions\s*=\s*\((.*?)\);`)
+
+// xcodeKnowsRegion reports whether a region is in an Xcode project's knownRegions
+func xcodeKnowsRegion(pbxproj, region string) bool {
+	matches := knownRegionsRegex.FindStringSubmatch(pbxproj)
+	if matches == nil {
+		return false
+	}
+	for _, known := range strings.Split(matches[1], ",") {
+		if strings.Trim(strings.TrimSpace(known), `"`) == region {
+			return true
+		}
+	}
+	return false
+}
+
+// appleLocaleDir returns the .lproj name of a locale, e.g. "pt-BR" for pt_BR and
+// "zh-Hans" for zh_CN
+func appleLocaleDir(locale string) string {
+	switch locale {
+	case "zh_CN":
+		return "zh-Hans"
+	case "zh_TW":
+		return "zh-Hant"
+	}
+	return strings.Replace(locale, "_", "-", 1)
+}
+
+// localeFromAppleDir returns the locale of a .lproj name, or false for Base and
+// names that aren't a known language
+func localeFromAppleDir(dir string) (string, bool) {
+	switch dir {
+	case "zh-Hans":
+		return "zh_CN", true
+	case "zh-Hant":
+		return "zh_TW", true
+	}
+
+	parts := strings.SplitN(dir, "-", 2)
+	if !localization.IsValidLanguageCode(parts[0]) || parts[0] != strings.ToLower(parts[0]) {
+		return "", false
+	}
+	if len(parts) == 2 {
+		return parts[0] + "_" + strings.ToUpper(parts[1]), true
+	}
+	return parts[0], true
+}
+
+// stringsFileEntryRegex matches a "key" = "value"; entry of a .strings file
+var stringsFileEntryRegex = regexp.MustCompile(`(?m)^\s*"?([A-Za-z0-9_.-]+)"?\s*=\s*"((?:[^"\\]|\\.)*)"\s*;`)
+
+// parseStringsFile returns the entries of a .strings file
+func parseStringsFile(content string) map[string]string {
+	values := make(map[string]string)
+	for _, matches := range stringsFileEntryRegex.FindAllStringSubmatch(content, -1) {
+		values[matches[1]] = unescapeStringsValue(matches[2])
+	}
+	return values
+}
+
+// setStringsFileValue sets an entry of a .strings file, keeping the other entries and comments
+func setStringsFileValue(content, key, value string) string {
+	entry := fmt.Sprintf("\"%s\" = \"%s\";", key, escapeStringsValue(value))
+
+	regex := regexp.MustCompile(`(?m)^\s*"?` + regexp.QuoteMeta(key) + `"?\s*=\s*"(?:[^"\\]|\\.)*"\s*;`)
+	if regex.MatchString(content) {
+		return regex.ReplaceAllLiteralString(content, entry)
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + entry + "\n"
+}
+
+// removeInfoPlistStrings removes the app name entries from an InfoPlist.strings file,
+// deleting the file and its .lproj directory if nothing else is left
+func removeInfoPlistStrings(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	updated := string(content)
+	for _, key := range []string{"CFBundleDisplayName", "CFBundleName"} {
+		regex := regexp.MustCompile(`(?m)^\s*"?` + regexp.QuoteMeta(key) + `"?\s*=\s*"(?:[^"\\]|\\.)*"\s*;[ \t]*\n?`)
+		updated = regex.ReplaceAllString(updated, "")
+	}
+
+	if len(parseStringsFile(updated)) == 0 {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		os.Remove(filepath.Dir(path)) // Only removed if empty
+		return nil
+	}
+
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// escapeStringsValue escapes a value for a .strings file
+func escapeStringsValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// unescapeStringsValue reverses escapeStringsValue
+func unescapeStringsValue(value string) string {
+	return strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n").Replace(value)
+}
+
+// Helpers
+
+// snapshotFile records a file's content before its first change
+func snapshotFile(path string, snapshots *[]fileSnapshot) error {
+	for _, snapshot := range *snapshots {
+		if snapshot.path == path {
+			return nil
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %v", filepath.Base(path), err)
+	}
+	*snapshots = append(*snapshots, fileSnapshot{path: path, content: content, existed: err == nil})
+	return nil
+}
+
+// restoreSnapshots puts files back as they were, removing the ones that were created
+func restoreSnapshots(snapshots []fileSnapshot) {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		snapshot := snapshots[i]
+		if snapshot.existed {
+			os.WriteFile(snapshot.path, snapshot.content, 0644) // Ignore errors during restore
+		} else {
+			os.Remove(snapshot.path)
+			os.Remove(filepath.Dir(snapshot.path)) // Only removed if empty
+		}
+	}
+}
+
+// sortedPlatforms returns the platforms of a map in AllPlatforms order
+func sortedPlatforms(platforms map[Platform]string) []Platform {
+	var sorted []Platform
+	for _, platform := range AllPlatforms() {
+		if _, ok := platforms[platform]; ok {
+			sorted = append(sorted, platform)
+		}
+	}
+	for platform := range platforms {
+		if !isKnownPlatform(platform) {
+			sorted = append(sorted, platform)
+		}
+	}
+	return sorted
+}
+
+// isKnownPlatform reports whether a platform is one of AllPlatforms
+func isKnownPlatform(platform Platform) bool {
+	for _, known := range AllPlatforms() {
+		if known == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// relativePath returns a path relative to the project with forward slashes
+func relativePath(projectPath, path string) string {
+	rel, err := filepath.Rel(projectPath, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -50,6 +50,7 @@ type SetAppNameRequest struct {
 	ProjectPath string            `json:"project_path"`
 	Universal   string            `json:"universal,omitempty"`
 	Platforms   map[Platform]string `json:"platforms,omitempty"`
+	Locale      string              `json:"locale,omitempty"`
 }
 
 // GetAppNames retrieves app names for specified platforms
@@ -84,21 +85,25 @@ func SetAppNames(request *SetAppNameRequest) error {
 		return fmt.Errorf("not a valid Flutter project: %s", request.ProjectPath)
 	}
 
+	// Localized names go to per-locale resources instead
+	if request.Locale != "" {
+		_, err := SetLocalizedAppNames(request)
+		return err
+	}
+
 	// Determine which platforms to update
-	var platformsToUpdate map[Platform]string
+	platformsToUpdate, err := resolvePlatformNames(request)
+	if err != nil {
+		return err
+	}
 
-	if request.Universal != "" {
-		// Universal update - apply to all available platforms
-		platformsToUpdate = make(map[Platform]string)
-		availablePlatforms := getAvailablePlatforms(request.ProjectPath)
-		for _, platform := range availablePlatforms {
-			platformsToUpdate[platform] = request.Universal
-		}
-	} else if len(request.Platforms) > 0 {
-		// Platform-specific updates
-		platformsToUpdate = request.Platforms
-	} else {
-		return fmt.Errorf("either universal name or platform-specific names must be provided")
+	// Check every name before anything is written
+	report := &ValidationReport{}
+	for _, platform := range sortedPlatforms(platformsToUpdate) {
+		report.add(ValidateAppName(platform, platformsToUpdate[platform])...)
+	}
+	if err := report.Err(); err != nil {
+		return err
 	}
 
 	// Create backups before making changes
@@ -124,6 +129,26 @@ func SetAppNames(request *SetAppNameRequest) error {
 	return nil
 }
 
+// resolvePlatformNames returns the name a request sets on each platform: the
+// universal name on every available platform, or the platform-specific names
+func resolvePlatformNames(request *SetAppNameRequest) (map[Platform]string, error) {
+	if request.Universal != "" {
+		// Universal update - apply to all available platforms
+		platformsToUpdate := make(map[Platform]string)
+		for _, platform := range getAvailablePlatforms(request.ProjectPath) {
+			platformsToUpdate[platform] = request.Universal
+		}
+		return platformsToUpdate, nil
+	}
+
+	if len(request.Platforms) > 0 {
+		// Platform-specific updates
+		return request.Platforms, nil
+	}
+
+	return nil, fmt.Errorf("either universal name or platform-specific names must be provided")
+}
+
 // isFlutterProject checks if the given path is a Flutter project
 func isFlutterProject(projectPath string) bool {
 	pubspecPath := filepath.Join(projectPath, "pubspec.yaml")
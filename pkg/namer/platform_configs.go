@@ -40,7 +40,22 @@ func getAndroidAppName(projectPath string) AppNameInfo {
 		return info
 	}
 
-	info.DisplayName = manifest.Application.Label
+	info.DisplayName = unescapeAndroidEscapes(manifest.Application.Label)
+
+	// A label like @string/app_name is resolved from the default resources
+	if strings.HasPrefix(manifest.Application.Label, "@string/") {
+		resource := strings.TrimPrefix(manifest.Application.Label, "@string/")
+		content, err := os.ReadFile(androidStringsPath(projectPath, ""))
+		if err != nil {
+			info.Error = fmt.Sprintf("Failed to read strings.xml for %s: %v", manifest.Application.Label, err)
+			return info
+		}
+		if value, ok := findAndroidStringResource(string(content), resource); ok {
+			info.DisplayName = value
+			info.InternalName = manifest.Application.Label
+		}
+	}
+
 	return info
 }
 
@@ -53,9 +68,14 @@ func setAndroidAppName(projectPath, appName string) error {
 		return fmt.Errorf("failed to read AndroidManifest.xml: %v", err)
 	}
 
-	// Use regex to replace the android:label attribute
+	// A label pointing at a string resource keeps pointing at it, so localized names still apply
+	if resource, ok := androidLabelResource(string(content)); ok {
+		return setAndroidStringResource(androidStringsPath(projectPath, ""), resource, appName)
+	}
+
+	// Use regex to replace the android:label attribute, escaping the name so & and " don't break the manifest
 	re := regexp.MustCompile(`android:label="[^"]*"`)
-	newContent := re.ReplaceAllString(string(content), fmt.Sprintf(`android:label="%s"`, appName))
+	newContent := re.ReplaceAllLiteralString(string(content), fmt.Sprintf(`android:label="%s"`, escapeAndroidAttribute(appName)))
 
 	// Write back to file
 	return os.WriteFile(manifestPath, []byte(newContent), 0644)
@@ -96,13 +116,13 @@ func parseIOSPlist(plistPath string) (displayName, bundleName string, err error)
 	// Extract CFBundleDisplayName
 	displayNameRe := regexp.MustCompile(`<key>CFBundleDisplayName</key>\s*<string>([^<]*)</string>`)
 	if matches := displayNameRe.FindStringSubmatch(contentStr); len(matches) > 1 {
-		displayName = matches[1]
+		displayName = unescapeXMLAttribute(matches[1])
 	}
 
 	// Extract CFBundleName
 	bundleNameRe := regexp.MustCompile(`<key>CFBundleName</key>\s*<string>([^<]*)</string>`)
 	if matches := bundleNameRe.FindStringSubmatch(contentStr); len(matches) > 1 {
-		bundleName = matches[1]
+		bundleName = unescapeXMLAttribute(matches[1])
 	}
 
 	return displayName, bundleName, nil
@@ -115,14 +135,15 @@ func updateIOSPlist(plistPath, appName string) error {
 	}
 
 	contentStr := string(content)
+	escaped := literalReplacement(escapePlistString(appName))
 
 	// Update CFBundleDisplayName
 	displayNameRe := regexp.MustCompile(`(<key>CFBundleDisplayName</key>\s*<string>)[^<]*</string>`)
-	contentStr = displayNameRe.ReplaceAllString(contentStr, fmt.Sprintf("${1}%s</string>", appName))
+	contentStr = displayNameRe.ReplaceAllString(contentStr, fmt.Sprintf("${1}%s</string>", escaped))
 
 	// Update CFBundleName
 	bundleNameRe := regexp.MustCompile(`(<key>CFBundleName</key>\s*<string>)[^<]*</string>`)
-	contentStr = bundleNameRe.ReplaceAllString(contentStr, fmt.Sprintf("${1}%s</string>", appName))
+	contentStr = bundleNameRe.ReplaceAllString(contentStr, fmt.Sprintf("${1}%s</string>", escaped))
 
 	return os.WriteFile(plistPath, []byte(contentStr), 0644)
 }
@@ -254,7 +275,7 @@ func parseCMakeFile(cmakePath, variable string) (string, error) {
 	for scanner.Scan() {
 		line := scanner.Text()
 		if matches := re.FindStringSubmatch(line); len(matches) > 1 {
-			return matches[1], nil
+			return strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\$`, "$").Replace(matches[1]), nil
 		}
 	}
 
@@ -268,7 +289,7 @@ func updateCMakeFile(cmakePath, variable, value string) error {
 	}
 
 	re := regexp.MustCompile(fmt.Sprintf(`(set\(%s\s+")[^"]+("\))`, variable))
-	newContent := re.ReplaceAllString(string(content), fmt.Sprintf("${1}%s${2}", value))
+	newContent := re.ReplaceAllString(string(content), fmt.Sprintf("${1}%s${2}", literalReplacement(escapeCMakeString(value))))
 
 	return os.WriteFile(cmakePath, []byte(newContent), 0644)
 }
@@ -279,8 +300,9 @@ func updateCMakeProjectName(cmakePath, appName string) error {
 		return err
 	}
 
+	// project() takes an unquoted argument, so names with spaces or symbols are quoted
 	re := regexp.MustCompile(`(project\()[^)]+(\s+LANGUAGES\s+CXX\))`)
-	newContent := re.ReplaceAllString(string(content), fmt.Sprintf("${1}%s${2}", appName))
+	newContent := re.ReplaceAllString(string(content), fmt.Sprintf("${1}%s${2}", literalReplacement(cmakeProjectName(appName))))
 
 	return os.WriteFile(cmakePath, []byte(newContent), 0644)
 }
@@ -334,25 +356,18 @@ func parseWebManifest(manifestPath string) (string, error) {
 }
 
 func updateWebManifest(manifestPath, appName string) error {
-	content, err := os.ReadFile(manifestPath)
+	// Rewrite in place, so the manifest keeps its key order and other fields
+	manifest, err := readJSONObject(manifestPath)
 	if err != nil {
 		return err
 	}
 
-	var manifest map[string]interface{}
-	if err := json.Unmarshal(content, &manifest); err != nil {
-		return err
+	fields := map[string]*string{"name": &appName}
+	// A short_name chosen for home screens is kept; it's only added if there's none
+	if manifest.getString("short_name") == "" {
+		fields["short_name"] = &appName
 	}
-
-	manifest["name"] = appName
-	manifest["short_name"] = appName
-
-	newContent, err := json.MarshalIndent(manifest, "", "    ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(manifestPath, newContent, 0644)
+	return updateWebManifestFields(manifestPath, fields)
 }
 
 func updateWebIndex(indexPath, appName string) error {
@@ -362,41 +377,60 @@ func updateWebIndex(indexPath, appName string) error {
 	}
 
 	contentStr := string(content)
+	escaped := literalReplacement(escapeHTMLText(appName))
 
 	// Update title tag
 	titleRe := regexp.MustCompile(`<title>[^<]*</title>`)
-	contentStr = titleRe.ReplaceAllString(contentStr, fmt.Sprintf("<title>%s</title>", appName))
+	contentStr = titleRe.ReplaceAllString(contentStr, fmt.Sprintf("<title>%s</title>", escaped))
 
 	// Update apple-mobile-web-app-title meta tag
 	metaRe := regexp.MustCompile(`(<meta name="apple-mobile-web-app-title" content=")[^"]*"`)
-	contentStr = metaRe.ReplaceAllString(contentStr, fmt.Sprintf(`${1}%s"`, appName))
+	contentStr = metaRe.ReplaceAllString(contentStr, fmt.Sprintf(`${1}%s"`, escaped))
 
 	return os.WriteFile(indexPath, []byte(contentStr), 0644)
 }
 
 // Backup and restore functions
 
-func createPlatformBackup(projectPath string, platform Platform, backupDir string) error {
-	var filesToBackup []string
+// BackupFiles returns the files, relative to the project, that setting app names
+// on the given platforms may change
+func BackupFiles(projectPath string, platforms []Platform) []string {
+	var files []string
+	for _, platform := range platforms {
+		files = append(files, platformBackupFiles(platform)...)
+	}
+	return files
+}
 
+// platformBackupFiles returns the files that hold a platform's app name
+func platformBackupFiles(platform Platform) []string {
 	switch platform {
 	case PlatformAndroid:
-		filesToBackup = []string{"android/app/src/main/AndroidManifest.xml"}
+		return []string{"android/app/src/main/AndroidManifest.xml", "android/app/src/main/res/values/strings.xml"}
 	case PlatformIOS:
-		filesToBackup = []string{"ios/Runner/Info.plist"}
+		return []string{"ios/Runner/Info.plist"}
 	case PlatformMacOS:
-		filesToBackup = []string{"macos/Runner/Configs/AppInfo.xcconfig"}
+		return []string{"macos/Runner/Configs/AppInfo.xcconfig"}
 	case PlatformLinux:
-		filesToBackup = []string{"linux/CMakeLists.txt"}
+		return []string{"linux/CMakeLists.txt"}
 	case PlatformWindows:
-		filesToBackup = []string{"windows/CMakeLists.txt"}
+		return []string{"windows/CMakeLists.txt"}
 	case PlatformWeb:
-		filesToBackup = []string{"web/manifest.json", "web/index.html"}
+		return []string{"web/manifest.json", "web/index.html"}
 	}
+	return nil
+}
 
-	for _, file := range filesToBackup {
+func createPlatformBackup(projectPath string, platform Platform, backupDir string) error {
+	for _, file := range platformBackupFiles(platform) {
 		srcPath := filepath.Join(projectPath, file)
 		dstPath := filepath.Join(backupDir, file)
+
+		// Optional files, such as strings.xml, may not exist yet
+		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
+			os.Remove(dstPath) // Drop a stale backup so it isn't restored
+			continue
+		}
 		
 		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
 			return err
@@ -411,24 +445,7 @@ func createPlatformBackup(projectPath string, platform Platform, backupDir strin
 }
 
 func restorePlatformBackup(projectPath string, platform Platform, backupDir string) {
-	var filesToRestore []string
-
-	switch platform {
-	case PlatformAndroid:
-		filesToRestore = []string{"android/app/src/main/AndroidManifest.xml"}
-	case PlatformIOS:
-		filesToRestore = []string{"ios/Runner/Info.plist"}
-	case PlatformMacOS:
-		filesToRestore = []string{"macos/Runner/Configs/AppInfo.xcconfig"}
-	case PlatformLinux:
-		filesToRestore = []string{"linux/CMakeLists.txt"}
-	case PlatformWindows:
-		filesToRestore = []string{"windows/CMakeLists.txt"}
-	case PlatformWeb:
-		filesToRestore = []string{"web/manifest.json", "web/index.html"}
-	}
-
-	for _, file := range filesToRestore {
+	for _, file := range platformBackupFiles(platform) {
 		srcPath := filepath.Join(backupDir, file)
 		dstPath := filepath.Join(projectPath, file)
 		copyFile(srcPath, dstPath) // Ignore errors during restore
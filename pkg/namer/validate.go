@@ -0,0 +1,248 @@
+package namer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// SeverityError marks names that would break a build or be rejected by a store; they are never written
+	SeverityError = "error"
+
+	// SeverityWarning marks names that work but should be reviewed
+	SeverityWarning = "warning"
+
+	// AppStoreNameLimit is the maximum length of an app name on the App Store and Mac App Store
+	AppStoreNameLimit = 30
+
+	// PlayStoreTitleLimit is the maximum length of an app title on Google Play
+	PlayStoreTitleLimit = 30
+
+	// WebShortNameLimit is the length above which home screens truncate a web app's short_name
+	WebShortNameLimit = 12
+)
+
+// ValidationIssue describes a problem with an app name on one platform
+type ValidationIssue struct {
+	Platform Platform `json:"platform"`
+	Severity string   `json:"severity"`
+	// Field is the setting the name is written to, e.g. CFBundleDisplayName
+	Field   string `json:"field"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// ValidationReport is the result of checking app names on every platform
+type ValidationReport struct {
+	Errors   int               `json:"errors"`
+	Warnings int               `json:"warnings"`
+	Issues   []ValidationIssue `json:"issues"`
+}
+
+// cmakeIdentifierRegex matches names that can be written to project() without quotes
+var cmakeIdentifierRegex = regexp.MustCompile(`^[A-Za-z0-9_.+-]+$`)
+
+// ValidateAppName checks an app name against the rules of a platform: store length
+// limits, characters that build files can't hold, and file name restrictions for
+// platforms that name the executable after the app
+func ValidateAppName(platform Platform, appName string) []ValidationIssue {
+	var issues []ValidationIssue
+	add := func(severity, field, message string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			Platform: platform,
+			Severity: severity,
+			Field:    field,
+			Name:     appName,
+			Message:  fmt.Sprintf(message, args...),
+		})
+	}
+
+	field := appNameField(platform)
+	length := utf8.RuneCountInString(appName)
+
+	if strings.TrimSpace(appName) == "" {
+		add(SeverityError, field, "App name must not be empty")
+		return issues
+	}
+	if strings.IndexFunc(appName, unicode.IsControl) != -1 {
+		add(SeverityError, field, "App name must not contain line breaks, tabs or other control characters")
+	}
+	if strings.TrimSpace(appName) != appName {
+		add(SeverityWarning, field, "App name starts or ends with whitespace")
+	}
+
+	switch platform {
+	case PlatformAndroid:
+		if length > PlayStoreTitleLimit {
+			add(SeverityWarning, field, "Google Play limits app titles to %d characters (%d given), and launchers truncate long labels", PlayStoreTitleLimit, length)
+		}
+	case PlatformIOS:
+		if length > AppStoreNameLimit {
+			add(SeverityError, field, "The App Store limits app names to %d characters (%d given)", AppStoreNameLimit, length)
+		}
+	case PlatformMacOS:
+		if length > AppStoreNameLimit {
+			add(SeverityError, field, "The Mac App Store limits app names to %d characters (%d given)", AppStoreNameLimit, length)
+		}
+		if strings.ContainsAny(appName, "/:") {
+			add(SeverityError, field, "PRODUCT_NAME names the .app bundle, which can't contain / or :")
+		}
+		if strings.Contains(appName, "$(") || strings.Contains(appName, "${") {
+			add(SeverityError, field, "AppInfo.xcconfig would expand $(...) in the name as a build setting")
+		}
+	case PlatformLinux:
+		if strings.ContainsAny(appName, `"\$;/`) {
+			add(SeverityError, field, `BINARY_NAME is the executable's file name in CMakeLists.txt and can't contain " \ $ ; or /`)
+		}
+		if strings.Contains(appName, " ") {
+			add(SeverityWarning, field, "BINARY_NAME is the executable's file name; spaces have to be quoted in launchers and scripts")
+		}
+	case PlatformWindows:
+		if strings.ContainsAny(appName, `<>:"/\|?*$;`) {
+			add(SeverityError, field, `BINARY_NAME is the executable's file name in CMakeLists.txt and can't contain < > : " / \ | ? * $ or ;`)
+		}
+		if strings.HasSuffix(appName, ".") {
+			add(SeverityError, field, "Windows file names can't end with a dot")
+		}
+	case PlatformWeb:
+		if length > WebShortNameLimit {
+			add(SeverityWarning, "short_name", "short_name is set to the full name if web/manifest.json has none, and home screens truncate names over %d characters (%d given); set a shorter one with 'fdawg namer web set --short-name'", WebShortNameLimit, length)
+		}
+	}
+
+	return issues
+}
+
+// ValidateAppNames checks the names a request would set, on the platforms it would set them
+func ValidateAppNames(request *SetAppNameRequest) (*ValidationReport, error) {
+	platformsToUpdate, err := resolvePlatformNames(request)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ValidationReport{Issues: []ValidationIssue{}}
+	for _, platform := range sortedPlatforms(platformsToUpdate) {
+		// Localized names only apply to Android, iOS and macOS
+		if request.Locale != "" && !IsLocalizablePlatform(platform) {
+			continue
+		}
+		report.add(ValidateAppName(platform, platformsToUpdate[platform])...)
+	}
+
+	return report, nil
+}
+
+// ValidateCurrentAppNames checks the app names the project has now
+func ValidateCurrentAppNames(projectPath string, platforms []Platform) (*ValidationReport, error) {
+	result, err := GetAppNames(projectPath, platforms)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ValidationReport{Issues: []ValidationIssue{}}
+	for _, appName := range result.AppNames {
+		if !appName.Available || appName.Error != "" {
+			continue
+		}
+		report.add(ValidateAppName(appName.Platform, appName.DisplayName)...)
+	}
+
+	return report, nil
+}
+
+// add appends issues and counts them by severity
+func (r *ValidationReport) add(issues ...ValidationIssue) {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+		r.Issues = append(r.Issues, issue)
+	}
+}
+
+// Err returns an error listing the report's errors, or nil if there are none
+func (r *ValidationReport) Err() error {
+	var messages []string
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			messages = append(messages, fmt.Sprintf("%s: %s", issue.Platform, issue.Message))
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("no files were changed: %s", strings.Join(messages, "; "))
+}
+
+// appNameField returns the setting a platform's app name is written to
+func appNameField(platform Platform) string {
+	switch platform {
+	case PlatformAndroid:
+		return "android:label"
+	case PlatformIOS:
+		return "CFBundleDisplayName"
+	case PlatformMacOS:
+		return "PRODUCT_NAME"
+	case PlatformLinux, PlatformWindows:
+		return "BINARY_NAME"
+	case PlatformWeb:
+		return "name"
+	}
+	return string(platform)
+}
+
+// Escaping
+
+// literalReplacement escapes $ so a value can be used in a regexp replacement template
+func literalReplacement(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
+
+// escapeAndroidAttribute escapes a literal string for an AndroidManifest.xml attribute:
+// XML entities for the attribute, and aapt escapes for backslashes and a leading @ or ?
+func escapeAndroidAttribute(value string) string {
+	value = strings.NewReplacer(
+		`\`, `\\`,
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, "&quot;",
+	).Replace(value)
+	if strings.HasPrefix(value, "@") || strings.HasPrefix(value, "?") {
+		value = `\` + value
+	}
+	return value
+}
+
+// unescapeAndroidEscapes reverses the aapt escapes of a decoded string value
+func unescapeAndroidEscapes(value string) string {
+	return strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, "'", `\n`, "\n", `\@`, "@", `\?`, "?").Replace(value)
+}
+
+// escapePlistString escapes text for a plist <string> element
+func escapePlistString(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(value)
+}
+
+// escapeCMakeString escapes text for a quoted CMake argument
+func escapeCMakeString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`).Replace(value)
+}
+
+// cmakeProjectName formats a name for project(), quoting it if it isn't a plain identifier
+func cmakeProjectName(value string) string {
+	if cmakeIdentifierRegex.MatchString(value) {
+		return value
+	}
+	return `"` + escapeCMakeString(value) + `"`
+}
+
+// escapeHTMLText escapes text for an HTML element or a double-quoted attribute
+func escapeHTMLText(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(value)
+}
@@ -0,0 +1,399 @@
+package namer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// WebMetadata is the web app's metadata in web/manifest.json and web/index.html
+type WebMetadata struct {
+	// Manifest fields
+	Name            string `json:"name"`
+	ShortName       string `json:"short_name"`
+	Description     string `json:"description"`
+	ThemeColor      string `json:"theme_color"`
+	BackgroundColor string `json:"background_color"`
+
+	// index.html tags
+	Title           string `json:"title"`
+	AppleTitle      string `json:"apple_title"`
+	MetaDescription string `json:"meta_description"`
+}
+
+// WebMetadataUpdate lists the web metadata fields to change. Nil fields are left as they are;
+// an empty manifest field other than name is removed from manifest.json.
+type WebMetadataUpdate struct {
+	Name            *string `json:"name,omitempty"`
+	ShortName       *string `json:"short_name,omitempty"`
+	Description     *string `json:"description,omitempty"`
+	ThemeColor      *string `json:"theme_color,omitempty"`
+	BackgroundColor *string `json:"background_color,omitempty"`
+	Title           *string `json:"title,omitempty"`
+	AppleTitle      *string `json:"apple_title,omitempty"`
+	MetaDescription *string `json:"meta_description,omitempty"`
+}
+
+// jsonObject is a JSON object that keeps its key order and the raw values of
+// fields it doesn't change
+type jsonObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+	indent string
+}
+
+// hexColorRegex matches CSS hex colors: #rgb, #rgba, #rrggbb and #rrggbbaa
+var hexColorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// webManifestPath returns the path to web/manifest.json
+func webManifestPath(projectPath string) string {
+	return filepath.Join(projectPath, "web", "manifest.json")
+}
+
+// webIndexPath returns the path to web/index.html
+func webIndexPath(projectPath string) string {
+	return filepath.Join(projectPath, "web", "index.html")
+}
+
+// GetWebMetadata reads the web app's metadata from manifest.json and index.html
+func GetWebMetadata(projectPath string) (*WebMetadata, error) {
+	if !isPlatformAvailable(projectPath, PlatformWeb) {
+		return nil, fmt.Errorf("platform web not available in project")
+	}
+
+	manifest, err := readJSONObject(webManifestPath(projectPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest.json: %v", err)
+	}
+
+	index, err := os.ReadFile(webIndexPath(projectPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index.html: %v", err)
+	}
+
+	metadata := &WebMetadata{
+		Name:            manifest.getString("name"),
+		ShortName:       manifest.getString("short_name"),
+		Description:     manifest.getString("description"),
+		ThemeColor:      manifest.getString("theme_color"),
+		BackgroundColor: manifest.getString("background_color"),
+		AppleTitle:      findHTMLMeta(string(index), "apple-mobile-web-app-title"),
+		MetaDescription: findHTMLMeta(string(index), "description"),
+	}
+	if matches := htmlTitleRegex.FindStringSubmatch(string(index)); matches != nil {
+		metadata.Title = html.UnescapeString(matches[2])
+	}
+
+	return metadata, nil
+}
+
+// ValidateWebMetadata checks a web metadata update: the name can't be empty, colors
+// must be CSS hex colors, and a short_name over WebShortNameLimit is truncated on home screens
+func ValidateWebMetadata(update WebMetadataUpdate) *ValidationReport {
+	report := &ValidationReport{Issues: []ValidationIssue{}}
+	add := func(severity, field, name, message string, args ...interface{}) {
+		report.add(ValidationIssue{
+			Platform: PlatformWeb,
+			Severity: severity,
+			Field:    field,
+			Name:     name,
+			Message:  fmt.Sprintf(message, args...),
+		})
+	}
+
+	if update.Name != nil && strings.TrimSpace(*update.Name) == "" {
+		add(SeverityError, "name", *update.Name, "The manifest name must not be empty")
+	}
+	if update.ShortName != nil {
+		if length := utf8.RuneCountInString(*update.ShortName); length > WebShortNameLimit {
+			add(SeverityWarning, "short_name", *update.ShortName, "Home screens truncate short_name over %d characters (%d given)", WebShortNameLimit, length)
+		}
+	}
+	colors := []struct {
+		field string
+		value *string
+	}{
+		{"theme_color", update.ThemeColor},
+		{"background_color", update.BackgroundColor},
+	}
+	for _, color := range colors {
+		if color.value != nil && *color.value != "" && !hexColorRegex.MatchString(*color.value) {
+			add(SeverityError, color.field, *color.value, "%s must be a hex color such as #0175C2", color.field)
+		}
+	}
+
+	return report
+}
+
+// SetWebMetadata writes the given web metadata fields. manifest.json keeps its key
+// order and any fields fdawg doesn't know, and index.html tags that are missing are
+// added to <head>. Both files are restored if either can't be written.
+func SetWebMetadata(projectPath string, update WebMetadataUpdate) (*ValidationReport, error) {
+	if !isPlatformAvailable(projectPath, PlatformWeb) {
+		return nil, fmt.Errorf("platform web not available in project")
+	}
+
+	report := ValidateWebMetadata(update)
+	if err := report.Err(); err != nil {
+		return report, err
+	}
+
+	var snapshots []fileSnapshot
+	for _, path := range []string{webManifestPath(projectPath), webIndexPath(projectPath)} {
+		if err := snapshotFile(path, &snapshots); err != nil {
+			return report, err
+		}
+	}
+
+	if err := updateWebManifestFields(webManifestPath(projectPath), map[string]*string{
+		"name":             update.Name,
+		"short_name":       update.ShortName,
+		"description":      update.Description,
+		"theme_color":      update.ThemeColor,
+		"background_color": update.BackgroundColor,
+	}); err != nil {
+		restoreSnapshots(snapshots)
+		return report, fmt.Errorf("failed to update manifest.json: %v", err)
+	}
+
+	if err := updateWebIndexFields(webIndexPath(projectPath), update); err != nil {
+		restoreSnapshots(snapshots)
+		return report, fmt.Errorf("failed to update index.html: %v", err)
+	}
+
+	return report, nil
+}
+
+// updateWebManifestFields sets manifest fields in order: existing fields keep their
+// place and new ones are appended. Empty values other than name remove the field.
+func updateWebManifestFields(manifestPath string, fields map[string]*string) error {
+	manifest, err := readJSONObject(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range []string{"name", "short_name", "description", "theme_color", "background_color"} {
+		value := fields[field]
+		if value == nil {
+			continue
+		}
+		if *value == "" && field != "name" {
+			manifest.delete(field)
+			continue
+		}
+		if err := manifest.set(field, *value); err != nil {
+			return err
+		}
+	}
+
+	return manifest.write(manifestPath)
+}
+
+// updateWebIndexFields sets the <title> and meta tags of index.html
+func updateWebIndexFields(indexPath string, update WebMetadataUpdate) error {
+	content, err := os.ReadFile(indexPath)
+	if err != nil {
+		return err
+	}
+	updated := string(content)
+
+	if update.Title != nil {
+		title := "<title>" + escapeHTMLText(*update.Title) + "</title>"
+		if htmlTitleRegex.MatchString(updated) {
+			updated = htmlTitleRegex.ReplaceAllLiteralString(updated, title)
+		} else if updated, err = insertIntoHTMLHead(updated, title); err != nil {
+			return err
+		}
+	}
+
+	metas := map[string]*string{
+		"apple-mobile-web-app-title": update.AppleTitle,
+		"description":                update.MetaDescription,
+	}
+	// A theme-color meta tag follows the manifest, if the page has one
+	if update.ThemeColor != nil && *update.ThemeColor != "" && findHTMLMetaRegex("theme-color").MatchString(updated) {
+		metas["theme-color"] = update.ThemeColor
+	}
+
+	for _, name := range []string{"apple-mobile-web-app-title", "description", "theme-color"} {
+		if metas[name] == nil {
+			continue
+		}
+		if updated, err = setHTMLMeta(updated, name, *metas[name]); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(indexPath, []byte(updated), 0644)
+}
+
+// htmlTitleRegex matches the <title> element
+var htmlTitleRegex = regexp.MustCompile(`(<title>)([^<]*)</title>`)
+
+// findHTMLMetaRegex matches a <meta name="..."> tag with its content attribute, in either order
+func findHTMLMetaRegex(name string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(name)
+	return regexp.MustCompile(`<meta\s+(?:name="` + quoted + `"\s+content="([^"]*)"|content="([^"]*)"\s+name="` + quoted + `")[^>]*>`)
+}
+
+// findHTMLMeta returns the content of a <meta name="..."> tag, or an empty string
+func findHTMLMeta(content, name string) string {
+	matches := findHTMLMetaRegex(name).FindStringSubmatch(content)
+	if matches == nil {
+		return ""
+	}
+	return html.UnescapeString(matches[1] + matches[2])
+}
+
+// setHTMLMeta sets the content of a <meta name="..."> tag, adding the tag to <head> if needed
+func setHTMLMeta(content, name, value string) (string, error) {
+	tag := fmt.Sprintf(`<meta name="%s" content="%s">`, name, escapeHTMLText(value))
+
+	regex := findHTMLMetaRegex(name)
+	if regex.MatchString(content) {
+		return regex.ReplaceAllLiteralString(content, tag), nil
+	}
+	return insertIntoHTMLHead(content, tag)
+}
+
+// insertIntoHTMLHead adds a line before </head>, indented like the line above it
+func insertIntoHTMLHead(content, line string) (string, error) {
+	end := strings.Index(content, "</head>")
+	if end == -1 {
+		return "", fmt.Errorf("could not find </head>")
+	}
+
+	// </head> shares its line with other tags, so the tag goes right before it
+	lineStart := strings.LastIndex(content[:end], "\n") + 1
+	if strings.TrimSpace(content[lineStart:end]) != "" || lineStart == 0 {
+		return content[:end] + line + content[end:], nil
+	}
+
+	indent := "  "
+	previousStart := strings.LastIndex(content[:lineStart-1], "\n") + 1
+	previous := content[previousStart : lineStart-1]
+	if trimmed := strings.TrimLeft(previous, " \t"); trimmed != "" {
+		indent = previous[:len(previous)-len(trimmed)]
+	}
+
+	return content[:lineStart] + indent + line + "\n" + content[lineStart:], nil
+}
+
+// JSON object editing
+
+// readJSONObject reads a JSON object file, keeping its key order and indentation
+func readJSONObject(path string) (*jsonObject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	object := &jsonObject{values: make(map[string]json.RawMessage), indent: detectJSONIndent(data)}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+		key, _ := token.(string)
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+
+		if _, ok := object.values[key]; !ok {
+			object.keys = append(object.keys, key)
+		}
+		object.values[key] = value
+	}
+
+	return object, nil
+}
+
+// detectJSONIndent returns the indentation of the first nested line, or four spaces
+func detectJSONIndent(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n")[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "    "
+}
+
+// getString returns a string field, or an empty string
+func (o *jsonObject) getString(key string) string {
+	var value string
+	json.Unmarshal(o.values[key], &value)
+	return value
+}
+
+// set sets a field, appending it if it's new
+func (o *jsonObject) set(key string, value interface{}) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = bytes.TrimRight(buf.Bytes(), "\n")
+	return nil
+}
+
+// delete removes a field
+func (o *jsonObject) delete(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// write writes the object with its key order and indentation
+func (o *jsonObject) write(path string) error {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+
+		var value bytes.Buffer
+		if err := json.Indent(&value, o.values[key], o.indent, o.indent); err != nil {
+			return fmt.Errorf("failed to format %s: %v", key, err)
+		}
+
+		buf.WriteString("\n" + o.indent)
+		buf.Write(keyJSON)
+		buf.WriteString(": ")
+		buf.Write(value.Bytes())
+	}
+	buf.WriteString("\n}\n")
+
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
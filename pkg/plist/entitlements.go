@@ -0,0 +1,98 @@
+package plist
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// EntitlementValue is an entitlement's value in one entitlements file
+type EntitlementValue struct {
+	File  string
+	Value *Value
+}
+
+// GetEntitlement returns a key's value in each of a platform's entitlements files that has it
+func GetEntitlement(projectPath string, platform Platform, key string) ([]EntitlementValue, error) {
+	var values []EntitlementValue
+	for _, path := range EntitlementsPaths(projectPath, platform) {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		doc, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		value, ok, err := doc.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			values = append(values, EntitlementValue{File: path, Value: value})
+		}
+	}
+	return values, nil
+}
+
+// SetEntitlement sets a key in every entitlements file of a platform and returns the
+// files written. The iOS entitlements file is created if needed, along with the build
+// setting that signs the app with it.
+func SetEntitlement(projectPath string, platform Platform, key string, value *Value) ([]string, error) {
+	batch := NewBatch()
+	if err := setEntitlement(batch, projectPath, platform, key, value); err != nil {
+		return nil, err
+	}
+	return saveEntitlements(batch, projectPath)
+}
+
+// DeleteEntitlement removes a key from every entitlements file of a platform and returns the files written
+func DeleteEntitlement(projectPath string, platform Platform, key string) ([]string, error) {
+	batch := NewBatch()
+	for _, path := range EntitlementsPaths(projectPath, platform) {
+		err := batch.Edit(path, false, func(doc *Document) error {
+			_, err := doc.Delete(key)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return batch.Save()
+}
+
+// setEntitlement adds setting a key in a platform's entitlements files to a batch
+func setEntitlement(batch *Batch, projectPath string, platform Platform, key string, value *Value) error {
+	for _, path := range EntitlementsPaths(projectPath, platform) {
+		// macOS projects always have their entitlements files; only iOS gets one created
+		err := batch.Edit(path, platform == PlatformIOS, func(doc *Document) error {
+			return doc.Set(key, value)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveEntitlements saves a batch and, if it created the iOS entitlements file, signs the app with it
+func saveEntitlements(batch *Batch, projectPath string) ([]string, error) {
+	iosPath := EntitlementsPaths(projectPath, PlatformIOS)[0]
+	_, statErr := os.Stat(iosPath)
+
+	written, err := batch.Save()
+	if err != nil {
+		return written, err
+	}
+
+	if os.IsNotExist(statErr) {
+		if _, err := os.Stat(iosPath); err == nil {
+			enabled, err := EnableIOSEntitlements(projectPath)
+			if err != nil {
+				return written, err
+			}
+			if enabled {
+				written = append(written, filepath.Join(projectPath, "ios", "Runner.xcodeproj", "project.pbxproj"))
+			}
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,170 @@
+package plist
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Platform is an Apple platform of a Flutter project
+type Platform string
+
+const (
+	PlatformIOS   Platform = "ios"
+	PlatformMacOS Platform = "macos"
+)
+
+// Platforms are the platforms with plist files, in display order
+var Platforms = []Platform{PlatformIOS, PlatformMacOS}
+
+// iosEntitlementsPath is the entitlements file created for iOS, relative to ios/
+const iosEntitlementsPath = "Runner/Runner.entitlements"
+
+// ParsePlatform converts a platform name to a Platform
+func ParsePlatform(name string) (Platform, error) {
+	for _, platform := range Platforms {
+		if strings.EqualFold(name, string(platform)) {
+			return platform, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported platform %q, use ios or macos", name)
+}
+
+// AvailablePlatforms returns the platforms whose directory exists in the project
+func AvailablePlatforms(projectPath string) []Platform {
+	var platforms []Platform
+	for _, platform := range Platforms {
+		if _, err := os.Stat(InfoPlistPath(projectPath, platform)); err == nil {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms
+}
+
+// InfoPlistPath returns the path to a platform's Info.plist
+func InfoPlistPath(projectPath string, platform Platform) string {
+	return filepath.Join(projectPath, string(platform), "Runner", "Info.plist")
+}
+
+// EntitlementsPaths returns the paths to a platform's entitlements files. macOS has
+// one for debug and profile builds and one for release builds; iOS has one, which
+// may not exist yet.
+func EntitlementsPaths(projectPath string, platform Platform) []string {
+	if platform == PlatformMacOS {
+		return []string{
+			filepath.Join(projectPath, "macos", "Runner", "DebugProfile.entitlements"),
+			filepath.Join(projectPath, "macos", "Runner", "Release.entitlements"),
+		}
+	}
+	return []string{filepath.Join(projectPath, "ios", filepath.FromSlash(iosEntitlementsPath))}
+}
+
+// LoadOrNew loads a plist file, or returns an empty document if it doesn't exist
+func LoadOrNew(path string) (*Document, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return New(), nil
+	}
+	return Load(path)
+}
+
+// Batch collects edits to several plist files and writes them together, so a file
+// that fails to parse or edit leaves all of them untouched
+type Batch struct {
+	documents map[string]*Document
+	originals map[string][]byte
+	order     []string
+}
+
+// NewBatch returns an empty batch of edits
+func NewBatch() *Batch {
+	return &Batch{documents: make(map[string]*Document), originals: make(map[string][]byte)}
+}
+
+// Document returns a file's document as edited so far, loading it on first use.
+// A missing file gives an empty document if create is set, and nil otherwise.
+func (b *Batch) Document(path string, create bool) (*Document, error) {
+	if doc, ok := b.documents[path]; ok {
+		return doc, nil
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) && !create {
+		return nil, nil
+	}
+	doc, err := LoadOrNew(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b.documents[path] = doc
+	b.originals[path] = doc.Bytes()
+	b.order = append(b.order, path)
+	return doc, nil
+}
+
+// Edit applies an edit to a file's document. Missing files are created if create
+// is set and skipped otherwise.
+func (b *Batch) Edit(path string, create bool, edit func(doc *Document) error) error {
+	doc, err := b.Document(path, create)
+	if err != nil || doc == nil {
+		return err
+	}
+	if err := edit(doc); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// Save writes the files whose content changed and returns their paths
+func (b *Batch) Save() ([]string, error) {
+	var written []string
+	for _, path := range b.order {
+		doc := b.documents[path]
+		if bytes.Equal(doc.Bytes(), b.originals[path]) {
+			continue
+		}
+		if err := doc.Save(path); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// EnableIOSEntitlements points the Runner target's builds at the iOS entitlements file,
+// so a newly created file is used when signing. It returns false if the project
+// already sets an entitlements file.
+func EnableIOSEntitlements(projectPath string) (bool, error) {
+	projectFile := filepath.Join(projectPath, "ios", "Runner.xcodeproj", "project.pbxproj")
+	content, err := os.ReadFile(projectFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read project.pbxproj: %v", err)
+	}
+
+	text := string(content)
+	if strings.Contains(text, "CODE_SIGN_ENTITLEMENTS") {
+		return false, nil
+	}
+
+	// Each Runner build configuration sets INFOPLIST_FILE; the setting goes next to it
+	lines := strings.Split(text, "\n")
+	updated := make([]string, 0, len(lines))
+	added := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "INFOPLIST_FILE = Runner/Info.plist;" {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			updated = append(updated, indent+"CODE_SIGN_ENTITLEMENTS = "+iosEntitlementsPath+";")
+			added = true
+		}
+		updated = append(updated, line)
+	}
+	if !added {
+		return false, fmt.Errorf("no Runner build configuration found in project.pbxproj")
+	}
+
+	if err := os.WriteFile(projectFile, []byte(strings.Join(updated, "\n")), 0644); err != nil {
+		return false, fmt.Errorf("failed to write project.pbxproj: %v", err)
+	}
+	return true, nil
+}
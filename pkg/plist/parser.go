@@ -0,0 +1,186 @@
+package plist
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// dateRegex matches plist dates, which are always UTC
+var dateRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)
+
+// base64Regex matches base64 data with whitespace removed
+var base64Regex = regexp.MustCompile(`^[A-Za-z0-9+/]*={0,2}$`)
+
+// parser reads plist XML, recording where each value is in the source
+type parser struct {
+	decoder *xml.Decoder
+	// offset is where the last token started
+	offset int
+}
+
+// next returns the next token, recording where it starts
+func (p *parser) next() (xml.Token, error) {
+	p.offset = int(p.decoder.InputOffset())
+	return p.decoder.Token()
+}
+
+// end returns the position just after the last token
+func (p *parser) end() int {
+	return int(p.decoder.InputOffset())
+}
+
+// parsePlist reads the contents of a <plist> element, which holds one value
+func (p *parser) parsePlist() (*Value, error) {
+	var root *Value
+	for {
+		token, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if root != nil {
+				return nil, fmt.Errorf("<plist> holds more than one value")
+			}
+			if root, err = p.parseValue(t, p.offset); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			return root, nil
+		case xml.CharData:
+			if strings.TrimSpace(string(t)) != "" {
+				return nil, fmt.Errorf("unexpected text in <plist>")
+			}
+		}
+	}
+}
+
+// parseValue reads a value whose start tag has just been read
+func (p *parser) parseValue(start xml.StartElement, startOffset int) (*Value, error) {
+	value := &Value{start: startOffset}
+	name := start.Name.Local
+
+	switch {
+	case scalarTags[name] != "":
+		text, err := p.readText(name)
+		if err != nil {
+			return nil, err
+		}
+		value.Kind = scalarTags[name]
+		value.Text = text
+	case name == "true" || name == "false":
+		if _, err := p.readText(name); err != nil {
+			return nil, err
+		}
+		value.Kind = KindBool
+		value.Text = name
+	case name == "array":
+		value.Kind = KindArray
+		value.openEnd = p.end()
+		if err := p.readArray(value); err != nil {
+			return nil, err
+		}
+	case name == "dict":
+		value.Kind = KindDict
+		value.openEnd = p.end()
+		if err := p.readDict(value); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown element <%s>", name)
+	}
+
+	value.end = p.end()
+	return value, nil
+}
+
+// readText reads the text of an element up to its end tag
+func (p *parser) readText(name string) (string, error) {
+	var sb strings.Builder
+	for {
+		token, err := p.next()
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			sb.Write(t)
+		case xml.StartElement:
+			return "", fmt.Errorf("unexpected <%s> in <%s>", t.Name.Local, name)
+		case xml.EndElement:
+			return sb.String(), nil
+		}
+	}
+}
+
+// readArray reads an array's elements up to its end tag
+func (p *parser) readArray(array *Value) error {
+	for {
+		token, err := p.next()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			item, err := p.parseValue(t, p.offset)
+			if err != nil {
+				return err
+			}
+			array.Items = append(array.Items, item)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// readDict reads a dict's keys and values up to its end tag
+func (p *parser) readDict(dict *Value) error {
+	for {
+		token, err := p.next()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "key" {
+				return fmt.Errorf("expected <key> in <dict>, found <%s>", t.Name.Local)
+			}
+			keyStart := p.offset
+			key, err := p.readText("key")
+			if err != nil {
+				return err
+			}
+
+			value, err := p.readDictValue(key)
+			if err != nil {
+				return err
+			}
+			dict.Entries = append(dict.Entries, &Entry{Key: key, Value: value, keyStart: keyStart})
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// readDictValue reads the value following a key
+func (p *parser) readDictValue(key string) (*Value, error) {
+	for {
+		token, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			return p.parseValue(t, p.offset)
+		case xml.EndElement:
+			return nil, fmt.Errorf("key %s has no value", key)
+		}
+	}
+}
@@ -0,0 +1,535 @@
+package plist
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of a plist value
+type Kind string
+
+const (
+	KindString  Kind = "string"
+	KindInteger Kind = "integer"
+	KindReal    Kind = "real"
+	KindBool    Kind = "bool"
+	KindDate    Kind = "date"
+	KindData    Kind = "data"
+	KindArray   Kind = "array"
+	KindDict    Kind = "dict"
+)
+
+// emptyDocument is a new plist with an empty dict, as Xcode writes it
+const emptyDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+</dict>
+</plist>
+`
+
+// Value is a value in a plist
+type Value struct {
+	Kind Kind
+	// Text is the content of a scalar; booleans are "true" or "false"
+	Text string
+	// Items are the elements of an array
+	Items []*Value
+	// Entries are the keys and values of a dict, in file order
+	Entries []*Entry
+
+	// start and end are the element's position in the document source
+	start, end int
+	// openEnd is just after a container's start tag
+	openEnd int
+}
+
+// Entry is a key and its value in a dict
+type Entry struct {
+	Key   string
+	Value *Value
+
+	// keyStart is the position of the <key> element in the document source
+	keyStart int
+}
+
+// Document is an XML plist. Edits change only the text of the values they
+// touch, so comments, key order and formatting elsewhere are kept.
+type Document struct {
+	source []byte
+	root   *Value
+	// indentUnit is one level of indentation, a tab unless the file uses something else
+	indentUnit string
+}
+
+// scalarTags maps the element names of scalar values to their kinds
+var scalarTags = map[string]Kind{
+	"string":  KindString,
+	"integer": KindInteger,
+	"real":    KindReal,
+	"date":    KindDate,
+	"data":    KindData,
+}
+
+// textEscaper escapes the characters Xcode escapes in plist text
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// New returns a plist document holding an empty dict
+func New() *Document {
+	doc, _ := Parse([]byte(emptyDocument))
+	return doc
+}
+
+// Load reads and parses a plist file
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return doc, nil
+}
+
+// Parse parses an XML plist
+func Parse(data []byte) (*Document, error) {
+	p := &parser{decoder: xml.NewDecoder(bytes.NewReader(data))}
+
+	var root *Value
+	for {
+		token, err := p.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "plist" {
+			return nil, fmt.Errorf("expected <plist>, found <%s>", start.Name.Local)
+		}
+		if root, err = p.parsePlist(); err != nil {
+			return nil, err
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("no plist value found")
+	}
+
+	doc := &Document{source: data, root: root}
+	doc.indentUnit = doc.detectIndentUnit(root)
+	if doc.indentUnit == "" {
+		doc.indentUnit = "\t"
+	}
+	return doc, nil
+}
+
+// Bytes returns the document's XML
+func (d *Document) Bytes() []byte {
+	return d.source
+}
+
+// Save writes the document to a file, creating its directory if needed
+func (d *Document) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, d.source, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// Root returns the document's top-level value
+func (d *Document) Root() *Value {
+	return d.root
+}
+
+// Get returns the value at a key path, such as NSAppTransportSecurity:NSAllowsArbitraryLoads.
+// Array elements are addressed by index. It returns false if there is no such value.
+func (d *Document) Get(keyPath string) (*Value, bool, error) {
+	keys, err := splitKeyPath(keyPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	current := d.root
+	for _, key := range keys {
+		if current = current.child(key); current == nil {
+			return nil, false, nil
+		}
+	}
+	return current, true, nil
+}
+
+// Set sets the value at a key path, adding the key and any missing dicts on the way
+func (d *Document) Set(keyPath string, value *Value) error {
+	keys, err := splitKeyPath(keyPath)
+	if err != nil {
+		return err
+	}
+
+	// Find the deepest container that already exists
+	parent := d.root
+	depth := 0
+	for ; depth < len(keys)-1; depth++ {
+		child := parent.child(keys[depth])
+		if child == nil {
+			break
+		}
+		if child.Kind != KindDict && child.Kind != KindArray {
+			return fmt.Errorf("%s is a %s, not a dict or array", strings.Join(keys[:depth+1], ":"), child.Kind)
+		}
+		parent = child
+	}
+
+	// Wrap the value in the dicts that don't exist yet
+	for i := len(keys) - 1; i > depth; i-- {
+		value = &Value{Kind: KindDict, Entries: []*Entry{{Key: keys[i], Value: value}}}
+	}
+	key := keys[depth]
+
+	switch parent.Kind {
+	case KindDict:
+		if existing := parent.child(key); existing != nil {
+			return d.splice(existing.start, existing.end, value.render(d.lineIndent(existing.start), d.indentUnit))
+		}
+		return d.insert(parent, &Entry{Key: key, Value: value})
+	case KindArray:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index > len(parent.Items) {
+			return fmt.Errorf("invalid index %s for an array of %d items", key, len(parent.Items))
+		}
+		if index < len(parent.Items) {
+			existing := parent.Items[index]
+			return d.splice(existing.start, existing.end, value.render(d.lineIndent(existing.start), d.indentUnit))
+		}
+		return d.insert(parent, &Entry{Value: value})
+	default:
+		return fmt.Errorf("the top-level value is a %s, not a dict or array", parent.Kind)
+	}
+}
+
+// Delete removes the value at a key path. It returns false if there was no such value.
+func (d *Document) Delete(keyPath string) (bool, error) {
+	keys, err := splitKeyPath(keyPath)
+	if err != nil {
+		return false, err
+	}
+
+	parent := d.root
+	for _, key := range keys[:len(keys)-1] {
+		if parent = parent.child(key); parent == nil {
+			return false, nil
+		}
+	}
+
+	last := keys[len(keys)-1]
+	switch parent.Kind {
+	case KindDict:
+		for _, entry := range parent.Entries {
+			if entry.Key == last {
+				return true, d.splice(d.lineStart(entry.keyStart), entry.Value.end, "")
+			}
+		}
+	case KindArray:
+		if index, err := strconv.Atoi(last); err == nil && index >= 0 && index < len(parent.Items) {
+			item := parent.Items[index]
+			return true, d.splice(d.lineStart(item.start), item.end, "")
+		}
+	}
+	return false, nil
+}
+
+// NewString returns a string value
+func NewString(text string) *Value {
+	return &Value{Kind: KindString, Text: text}
+}
+
+// NewBool returns a boolean value
+func NewBool(value bool) *Value {
+	return &Value{Kind: KindBool, Text: strconv.FormatBool(value)}
+}
+
+// NewInteger returns an integer value
+func NewInteger(value int64) *Value {
+	return &Value{Kind: KindInteger, Text: strconv.FormatInt(value, 10)}
+}
+
+// NewArray returns an array holding the given values
+func NewArray(items ...*Value) *Value {
+	return &Value{Kind: KindArray, Items: items}
+}
+
+// NewDict returns an empty dict
+func NewDict() *Value {
+	return &Value{Kind: KindDict}
+}
+
+// ParseValue converts text to a scalar value of the given kind, checking its format
+func ParseValue(kind Kind, text string) (*Value, error) {
+	switch kind {
+	case KindString:
+		return NewString(text), nil
+	case KindBool:
+		switch strings.ToLower(text) {
+		case "true", "yes", "1":
+			return NewBool(true), nil
+		case "false", "no", "0":
+			return NewBool(false), nil
+		}
+		return nil, fmt.Errorf("%q is not a boolean, use true or false", text)
+	case KindInteger:
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", text)
+		}
+		return NewInteger(value), nil
+	case KindReal:
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		return &Value{Kind: KindReal, Text: text}, nil
+	case KindDate:
+		if !dateRegex.MatchString(text) {
+			return nil, fmt.Errorf("%q is not a date like 2024-01-31T12:00:00Z", text)
+		}
+		return &Value{Kind: KindDate, Text: text}, nil
+	case KindData:
+		if !base64Regex.MatchString(strings.Join(strings.Fields(text), "")) {
+			return nil, fmt.Errorf("%q is not base64 data", text)
+		}
+		return &Value{Kind: KindData, Text: text}, nil
+	default:
+		return nil, fmt.Errorf("unknown type %q, use string, bool, integer, real, date or data", kind)
+	}
+}
+
+// IsContainer reports whether the value is an array or dict
+func (v *Value) IsContainer() bool {
+	return v.Kind == KindArray || v.Kind == KindDict
+}
+
+// Keys returns a dict's keys in file order
+func (v *Value) Keys() []string {
+	keys := make([]string, 0, len(v.Entries))
+	for _, entry := range v.Entries {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+// Interface converts the value to Go values for printing or JSON: strings, int64,
+// float64, bool, []interface{} and map[string]interface{}
+func (v *Value) Interface() interface{} {
+	switch v.Kind {
+	case KindInteger:
+		if value, err := strconv.ParseInt(strings.TrimSpace(v.Text), 10, 64); err == nil {
+			return value
+		}
+	case KindReal:
+		if value, err := strconv.ParseFloat(strings.TrimSpace(v.Text), 64); err == nil {
+			return value
+		}
+	case KindBool:
+		return v.Text == "true"
+	case KindArray:
+		items := make([]interface{}, 0, len(v.Items))
+		for _, item := range v.Items {
+			items = append(items, item.Interface())
+		}
+		return items
+	case KindDict:
+		entries := make(map[string]interface{}, len(v.Entries))
+		for _, entry := range v.Entries {
+			entries[entry.Key] = entry.Value.Interface()
+		}
+		return entries
+	}
+	return v.Text
+}
+
+// child returns a dict's value for a key or an array's element for an index
+func (v *Value) child(key string) *Value {
+	switch v.Kind {
+	case KindDict:
+		for _, entry := range v.Entries {
+			if entry.Key == key {
+				return entry.Value
+			}
+		}
+	case KindArray:
+		if index, err := strconv.Atoi(key); err == nil && index >= 0 && index < len(v.Items) {
+			return v.Items[index]
+		}
+	}
+	return nil
+}
+
+// render returns the value's XML, with nested lines indented from indent
+func (v *Value) render(indent, unit string) string {
+	switch v.Kind {
+	case KindBool:
+		return "<" + v.Text + "/>"
+	case KindArray:
+		if len(v.Items) == 0 {
+			return "<array/>"
+		}
+		var sb strings.Builder
+		sb.WriteString("<array>\n")
+		for _, item := range v.Items {
+			sb.WriteString(indent + unit + item.render(indent+unit, unit) + "\n")
+		}
+		sb.WriteString(indent + "</array>")
+		return sb.String()
+	case KindDict:
+		if len(v.Entries) == 0 {
+			return "<dict/>"
+		}
+		var sb strings.Builder
+		sb.WriteString("<dict>\n")
+		for _, entry := range v.Entries {
+			sb.WriteString(entry.render(indent+unit, unit) + "\n")
+		}
+		sb.WriteString(indent + "</dict>")
+		return sb.String()
+	default:
+		return "<" + string(v.Kind) + ">" + textEscaper.Replace(v.Text) + "</" + string(v.Kind) + ">"
+	}
+}
+
+// render returns a dict entry's key and value, each on a line starting with indent
+func (e *Entry) render(indent, unit string) string {
+	return indent + "<key>" + textEscaper.Replace(e.Key) + "</key>\n" + indent + e.Value.render(indent, unit)
+}
+
+// insert adds an entry at the end of a dict, or a value at the end of an array
+func (d *Document) insert(container *Value, entry *Entry) error {
+	// An empty container is written again in full, since it may be <dict/> or on one line
+	if len(container.Entries) == 0 && len(container.Items) == 0 {
+		filled := *container
+		if container.Kind == KindDict {
+			filled.Entries = []*Entry{entry}
+		} else {
+			filled.Items = []*Value{entry.Value}
+		}
+		return d.splice(container.start, container.end, filled.render(d.lineIndent(container.start), d.indentUnit))
+	}
+
+	// Otherwise the new line goes after the last one, indented like it
+	var lastStart, lastEnd int
+	if container.Kind == KindDict {
+		last := container.Entries[len(container.Entries)-1]
+		lastStart, lastEnd = last.keyStart, last.Value.end
+	} else {
+		last := container.Items[len(container.Items)-1]
+		lastStart, lastEnd = last.start, last.end
+	}
+
+	indent := d.lineIndent(lastStart)
+	text := indent + entry.Value.render(indent, d.indentUnit)
+	if container.Kind == KindDict {
+		text = entry.render(indent, d.indentUnit)
+	}
+	return d.splice(lastEnd, lastEnd, "\n"+text)
+}
+
+// splice replaces part of the source and parses the result again
+func (d *Document) splice(start, end int, text string) error {
+	source := make([]byte, 0, len(d.source)+len(text))
+	source = append(source, d.source[:start]...)
+	source = append(source, text...)
+	source = append(source, d.source[end:]...)
+
+	parsed, err := Parse(source)
+	if err != nil {
+		return fmt.Errorf("edit produced an invalid plist: %v", err)
+	}
+	*d = *parsed
+	return nil
+}
+
+// lineIndent returns the whitespace before pos on its line, or "" if pos is not the first thing on the line
+func (d *Document) lineIndent(pos int) string {
+	i := pos
+	for i > 0 && (d.source[i-1] == ' ' || d.source[i-1] == '\t') {
+		i--
+	}
+	if i > 0 && d.source[i-1] != '\n' {
+		return ""
+	}
+	return string(d.source[i:pos])
+}
+
+// lineStart returns where to start removing an element at pos: the newline before
+// it if only whitespace is in between, so the whole line goes
+func (d *Document) lineStart(pos int) int {
+	i := pos
+	for i > 0 && (d.source[i-1] == ' ' || d.source[i-1] == '\t') {
+		i--
+	}
+	if i > 0 && d.source[i-1] == '\n' {
+		i--
+		if i > 0 && d.source[i-1] == '\r' {
+			i--
+		}
+		return i
+	}
+	return pos
+}
+
+// detectIndentUnit returns the indentation a container's children get beyond the container's own
+func (d *Document) detectIndentUnit(v *Value) string {
+	var childStarts []int
+	for _, entry := range v.Entries {
+		childStarts = append(childStarts, entry.keyStart)
+	}
+	for _, item := range v.Items {
+		childStarts = append(childStarts, item.start)
+	}
+
+	if len(childStarts) > 0 {
+		own := d.lineIndent(v.start)
+		child := d.lineIndent(childStarts[0])
+		if len(child) > len(own) && strings.HasPrefix(child, own) {
+			return child[len(own):]
+		}
+	}
+
+	for _, entry := range v.Entries {
+		if unit := d.detectIndentUnit(entry.Value); unit != "" {
+			return unit
+		}
+	}
+	for _, item := range v.Items {
+		if unit := d.detectIndentUnit(item); unit != "" {
+			return unit
+		}
+	}
+	return ""
+}
+
+// splitKeyPath splits a colon-separated key path; a leading colon is allowed, as in PlistBuddy
+func splitKeyPath(keyPath string) ([]string, error) {
+	keys := strings.Split(strings.TrimPrefix(keyPath, ":"), ":")
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("invalid key path %q", keyPath)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,210 @@
+package plist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UsageDescription is a permission whose purpose string must be in Info.plist
+// before the app can ask for it
+type UsageDescription struct {
+	// Name is the short name used on the command line, e.g. camera
+	Name string `json:"name"`
+	// Key is the Info.plist key, e.g. NSCameraUsageDescription
+	Key string `json:"key"`
+	// MacEntitlement is the sandbox entitlement a macOS app also needs, if any
+	MacEntitlement string `json:"mac_entitlement,omitempty"`
+	// IOSOnly is set for permissions macOS doesn't have
+	IOSOnly bool `json:"ios_only,omitempty"`
+}
+
+// UsageDescriptionValue is a usage description set in a platform's Info.plist
+type UsageDescriptionValue struct {
+	Platform    Platform `json:"platform"`
+	Name        string   `json:"name,omitempty"`
+	Key         string   `json:"key"`
+	Description string   `json:"description"`
+}
+
+// UsageResult lists what setting or removing a usage description changed
+type UsageResult struct {
+	Files []string `json:"files"`
+	// Skipped are platforms the permission doesn't exist on
+	Skipped []Platform `json:"skipped,omitempty"`
+}
+
+// usageKeySuffix ends every usage description key
+const usageKeySuffix = "UsageDescription"
+
+// UsageDescriptions are the common permissions, in display order
+var UsageDescriptions = []UsageDescription{
+	{Name: "camera", Key: "NSCameraUsageDescription", MacEntitlement: "com.apple.security.device.camera"},
+	{Name: "microphone", Key: "NSMicrophoneUsageDescription", MacEntitlement: "com.apple.security.device.audio-input"},
+	{Name: "photos", Key: "NSPhotoLibraryUsageDescription", MacEntitlement: "com.apple.security.personal-information.photos-library"},
+	{Name: "photos-add", Key: "NSPhotoLibraryAddUsageDescription", IOSOnly: true},
+	{Name: "location", Key: "NSLocationWhenInUseUsageDescription", MacEntitlement: "com.apple.security.personal-information.location"},
+	{Name: "location-always", Key: "NSLocationAlwaysAndWhenInUseUsageDescription", MacEntitlement: "com.apple.security.personal-information.location"},
+	{Name: "contacts", Key: "NSContactsUsageDescription", MacEntitlement: "com.apple.security.personal-information.addressbook"},
+	{Name: "calendars", Key: "NSCalendarsUsageDescription", MacEntitlement: "com.apple.security.personal-information.calendars"},
+	{Name: "reminders", Key: "NSRemindersUsageDescription", MacEntitlement: "com.apple.security.personal-information.calendars"},
+	{Name: "bluetooth", Key: "NSBluetoothAlwaysUsageDescription", MacEntitlement: "com.apple.security.device.bluetooth"},
+	{Name: "face-id", Key: "NSFaceIDUsageDescription", IOSOnly: true},
+	{Name: "motion", Key: "NSMotionUsageDescription", IOSOnly: true},
+	{Name: "speech", Key: "NSSpeechRecognitionUsageDescription"},
+	{Name: "tracking", Key: "NSUserTrackingUsageDescription"},
+	{Name: "local-network", Key: "NSLocalNetworkUsageDescription", IOSOnly: true},
+	{Name: "media-library", Key: "NSAppleMusicUsageDescription", IOSOnly: true},
+	{Name: "health-share", Key: "NSHealthShareUsageDescription", IOSOnly: true},
+	{Name: "health-update", Key: "NSHealthUpdateUsageDescription", IOSOnly: true},
+	{Name: "nfc", Key: "NFCReaderUsageDescription", IOSOnly: true},
+}
+
+// LookupUsageDescription finds a permission by its short name or Info.plist key. Other
+// keys ending in UsageDescription are accepted as they are, for permissions not in the list.
+func LookupUsageDescription(nameOrKey string) (UsageDescription, error) {
+	for _, usage := range UsageDescriptions {
+		if strings.EqualFold(nameOrKey, usage.Name) || nameOrKey == usage.Key {
+			return usage, nil
+		}
+	}
+
+	if strings.HasSuffix(nameOrKey, usageKeySuffix) && !strings.Contains(nameOrKey, ":") {
+		return UsageDescription{Key: nameOrKey}, nil
+	}
+
+	names := make([]string, 0, len(UsageDescriptions))
+	for _, usage := range UsageDescriptions {
+		names = append(names, usage.Name)
+	}
+	return UsageDescription{}, fmt.Errorf("unknown permission %q, use one of %s or an Info.plist key ending in %s", nameOrKey, strings.Join(names, ", "), usageKeySuffix)
+}
+
+// GetUsageDescriptions returns the usage descriptions set in a platform's Info.plist, sorted by key
+func GetUsageDescriptions(projectPath string, platform Platform) ([]UsageDescriptionValue, error) {
+	doc, err := Load(InfoPlistPath(projectPath, platform))
+	if err != nil {
+		return nil, err
+	}
+	if doc.Root().Kind != KindDict {
+		return nil, fmt.Errorf("Info.plist for %s is not a dict", platform)
+	}
+
+	values := []UsageDescriptionValue{}
+	for _, entry := range doc.Root().Entries {
+		if !strings.HasSuffix(entry.Key, usageKeySuffix) || entry.Value.Kind != KindString {
+			continue
+		}
+		value := UsageDescriptionValue{Platform: platform, Key: entry.Key, Description: entry.Value.Text}
+		if usage, err := LookupUsageDescription(entry.Key); err == nil {
+			value.Name = usage.Name
+		}
+		values = append(values, value)
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Key < values[j].Key
+	})
+	return values, nil
+}
+
+// SetUsageDescription sets a permission's purpose string in the Info.plist of each
+// platform. On macOS the permission's sandbox entitlement is enabled too, since the
+// app can't ask for it without one.
+func SetUsageDescription(projectPath string, platforms []Platform, nameOrKey, description string) (*UsageResult, error) {
+	usage, err := LookupUsageDescription(nameOrKey)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(description) == "" {
+		return nil, fmt.Errorf("description cannot be empty; App Review rejects apps without a clear purpose string")
+	}
+
+	result := &UsageResult{}
+	batch := NewBatch()
+	for _, platform := range platforms {
+		if usage.IOSOnly && platform != PlatformIOS {
+			result.Skipped = append(result.Skipped, platform)
+			continue
+		}
+
+		err := batch.Edit(InfoPlistPath(projectPath, platform), false, func(doc *Document) error {
+			return doc.Set(usage.Key, NewString(description))
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if platform == PlatformMacOS && usage.MacEntitlement != "" {
+			if err := setEntitlement(batch, projectPath, platform, usage.MacEntitlement, NewBool(true)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if result.Files, err = batch.Save(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RemoveUsageDescription removes a permission's purpose string from the Info.plist of
+// each platform. On macOS its entitlement is removed too, unless another permission
+// that is still set needs it.
+func RemoveUsageDescription(projectPath string, platforms []Platform, nameOrKey string) (*UsageResult, error) {
+	usage, err := LookupUsageDescription(nameOrKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UsageResult{}
+	batch := NewBatch()
+	for _, platform := range platforms {
+		if usage.IOSOnly && platform != PlatformIOS {
+			result.Skipped = append(result.Skipped, platform)
+			continue
+		}
+
+		infoPlist, err := batch.Document(InfoPlistPath(projectPath, platform), false)
+		if err != nil {
+			return nil, err
+		}
+		if infoPlist == nil {
+			continue
+		}
+		if _, err := infoPlist.Delete(usage.Key); err != nil {
+			return nil, err
+		}
+
+		if platform != PlatformMacOS || usage.MacEntitlement == "" || entitlementStillNeeded(infoPlist, usage.MacEntitlement) {
+			continue
+		}
+		for _, path := range EntitlementsPaths(projectPath, platform) {
+			err := batch.Edit(path, false, func(doc *Document) error {
+				_, err := doc.Delete(usage.MacEntitlement)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if result.Files, err = batch.Save(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// entitlementStillNeeded reports whether a usage description left in Info.plist needs the entitlement
+func entitlementStillNeeded(infoPlist *Document, entitlement string) bool {
+	for _, usage := range UsageDescriptions {
+		if usage.MacEntitlement != entitlement {
+			continue
+		}
+		if _, ok, _ := infoPlist.Get(usage.Key); ok {
+			return true
+		}
+	}
+	return false
+}
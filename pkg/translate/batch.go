@@ -193,3 +193,55 @@ func HasTranslatableContent(translations map[string]string) bool {
 	}
 	return false
 }
+
+// TranslateForms translates each form of a plural or gender message separately.
+// Target forms missing from the source, such as "few" when translating from English,
+// are translated from the source "other" form.
+func (s *Service) TranslateForms(forms map[string]string, sourceLang, targetLang string, targetForms []string) (map[string]string, error) {
+	if !s.IsEnabled() {
+		return nil, fmt.Errorf("translation service is not enabled")
+	}
+
+	// Explicit value forms such as =0 are kept alongside the target's categories
+	names := append([]string{}, targetForms...)
+	for form := range forms {
+		if strings.HasPrefix(form, "=") {
+			names = append(names, form)
+		}
+	}
+
+	var texts []string
+	var translatedNames []string
+	result := make(map[string]string)
+	for _, name := range names {
+		text := forms[name]
+		if strings.TrimSpace(text) == "" {
+			text = forms["other"]
+		}
+		if strings.TrimSpace(text) == "" {
+			result[name] = ""
+			continue
+		}
+		texts = append(texts, text)
+		translatedNames = append(translatedNames, name)
+	}
+
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("no source text found for language: %s", sourceLang)
+	}
+
+	resp, err := s.BatchTranslate(BatchTranslationRequest{
+		Texts:      texts,
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, name := range translatedNames {
+		result[name] = resp.Translations[i].TranslatedText
+	}
+
+	return result, nil
+}
@@ -6,10 +6,32 @@ import (
 	"github.com/Jerinji2016/fdawg/pkg/config"
 )
 
+// Translation providers
+const (
+	ProviderGoogle         = "google"
+	ProviderDeepL          = "deepl"
+	ProviderLibreTranslate = "libretranslate"
+	ProviderFixture        = "fixture"
+)
+
+// defaultLibreTranslateURL is a LibreTranslate instance running locally with default settings
+const defaultLibreTranslateURL = "http://localhost:5000"
+
 // Config holds the configuration for the translation service
 type Config struct {
 	APIKey  string
 	Enabled bool
+
+	// Provider selects the Translator, defaulting to Google Translate
+	Provider             string
+	DeepLAPIKey          string
+	LibreTranslateURL    string
+	LibreTranslateAPIKey string
+	FixturePath          string
+	RequestsPerSecond    float64
+
+	// ProjectPath locates the translation memory and glossary
+	ProjectPath string
 }
 
 // LoadConfig loads the translation configuration from project config only
@@ -32,20 +54,62 @@ func LoadConfig(projectPath string) (*Config, error) {
 	}
 
 	return &Config{
-		APIKey:  projectConfig.GoogleTranslateAPIKey,
-		Enabled: projectConfig.Enabled,
+		APIKey:               projectConfig.GoogleTranslateAPIKey,
+		Enabled:              projectConfig.Enabled,
+		Provider:             projectConfig.Provider,
+		DeepLAPIKey:          projectConfig.DeepLAPIKey,
+		LibreTranslateURL:    projectConfig.LibreTranslateURL,
+		LibreTranslateAPIKey: projectConfig.LibreTranslateAPIKey,
+		FixturePath:          projectConfig.FixturePath,
+		RequestsPerSecond:    projectConfig.RequestsPerSecond,
+		ProjectPath:          projectPath,
 	}, nil
 }
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
-	if c.Enabled && c.APIKey == "" {
-		return fmt.Errorf("Google Translate API key is required when translation is enabled")
+	switch c.ProviderName() {
+	case ProviderGoogle:
+		if c.Enabled && c.APIKey == "" {
+			return fmt.Errorf("Google Translate API key is required when translation is enabled")
+		}
+	case ProviderDeepL:
+		if c.Enabled && c.DeepLAPIKey == "" {
+			return fmt.Errorf("DeepL API key is required when the deepl provider is selected")
+		}
+	case ProviderFixture:
+		if c.Enabled && c.FixturePath == "" {
+			return fmt.Errorf("fixture path is required when the fixture provider is selected")
+		}
+	case ProviderLibreTranslate:
+	default:
+		return fmt.Errorf("unknown translation provider: %s (use google, deepl, libretranslate or fixture)", c.Provider)
 	}
 	return nil
 }
 
 // IsEnabled returns whether translation is enabled
 func (c *Config) IsEnabled() bool {
-	return c.Enabled && c.APIKey != ""
+	if !c.Enabled {
+		return false
+	}
+
+	switch c.ProviderName() {
+	case ProviderDeepL:
+		return c.DeepLAPIKey != ""
+	case ProviderLibreTranslate:
+		return true
+	case ProviderFixture:
+		return c.FixturePath != ""
+	default:
+		return c.APIKey != ""
+	}
+}
+
+// ProviderName returns the selected provider, defaulting to Google Translate
+func (c *Config) ProviderName() string {
+	if c.Provider == "" {
+		return ProviderGoogle
+	}
+	return c.Provider
 }
@@ -0,0 +1,117 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// deepLURL is the DeepL Pro API endpoint
+	deepLURL = "https://api.deepl.com/v2/translate"
+
+	// deepLFreeURL is the DeepL API Free endpoint, used for keys ending in :fx
+	deepLFreeURL = "https://api-free.deepl.com/v2/translate"
+)
+
+// DeepLTranslator translates with the DeepL API
+type DeepLTranslator struct {
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// deepLResponse is the response from the DeepL translate endpoint
+type deepLResponse struct {
+	Translations []struct {
+		DetectedSourceLanguage string `json:"detected_source_language"`
+		Text                   string `json:"text"`
+	} `json:"translations"`
+}
+
+// Name returns the provider name
+func (t *DeepLTranslator) Name() string {
+	return "DeepL"
+}
+
+// Translate translates texts in a single request
+func (t *DeepLTranslator) Translate(texts []string, sourceLang, targetLang string) ([]string, error) {
+	apiURL := deepLURL
+	if strings.HasSuffix(t.APIKey, ":fx") {
+		apiURL = deepLFreeURL
+	}
+
+	data := url.Values{}
+	for _, text := range texts {
+		data.Add("text", text)
+	}
+	data.Set("source_lang", deepLLanguageCode(sourceLang, false))
+	data.Set("target_lang", deepLLanguageCode(targetLang, true))
+
+	req, err := http.NewRequest(http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.APIKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call DeepL API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError("DeepL", resp, body)
+	}
+
+	var deepLResp deepLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&deepLResp); err != nil {
+		return nil, fmt.Errorf("failed to decode DeepL response: %v", err)
+	}
+
+	translations := make([]string, len(deepLResp.Translations))
+	for i, translation := range deepLResp.Translations {
+		translations[i] = translation.Text
+	}
+
+	return translations, nil
+}
+
+// deepLLanguageCode converts a project language code to DeepL's. Source languages
+// are plain language codes; English and Portuguese targets need a variant.
+func deepLLanguageCode(code string, target bool) string {
+	parts := strings.Split(code, "_")
+	language := strings.ToUpper(parts[0])
+	if !target {
+		return language
+	}
+
+	region := ""
+	if len(parts) > 1 {
+		region = strings.ToUpper(parts[1])
+	}
+
+	switch language {
+	case "EN":
+		if region == "GB" {
+			return "EN-GB"
+		}
+		return "EN-US"
+	case "PT":
+		if region == "BR" {
+			return "PT-BR"
+		}
+		return "PT-PT"
+	case "ZH":
+		if region == "TW" || region == "HK" {
+			return "ZH-HANT"
+		}
+		return "ZH-HANS"
+	}
+
+	return language
+}
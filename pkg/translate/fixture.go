@@ -0,0 +1,60 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FixtureTranslator returns canned translations from a JSON file, so tests and CI
+// can run bulk translation without calling a provider. The file maps target
+// languages to source texts and their translations:
+//
+//	{"fr": {"Hello": "Bonjour"}, "de": {"Hello": "Hallo"}}
+type FixtureTranslator struct {
+	Translations map[string]map[string]string
+}
+
+// LoadFixtureTranslator reads a fixture file
+func LoadFixtureTranslator(path string) (*FixtureTranslator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read translation fixture: %v", err)
+	}
+
+	var translations map[string]map[string]string
+	if err := json.Unmarshal(data, &translations); err != nil {
+		return nil, fmt.Errorf("failed to parse translation fixture %s: %v", path, err)
+	}
+
+	return &FixtureTranslator{Translations: translations}, nil
+}
+
+// Name returns the provider name
+func (t *FixtureTranslator) Name() string {
+	return "fixture"
+}
+
+// Translate looks up each text for the target language, falling back from a
+// regional code such as pt_BR to its language
+func (t *FixtureTranslator) Translate(texts []string, sourceLang, targetLang string) ([]string, error) {
+	translations, ok := t.Translations[targetLang]
+	if !ok {
+		translations, ok = t.Translations[strings.Split(targetLang, "_")[0]]
+	}
+	if !ok {
+		return nil, fmt.Errorf("translation fixture has no translations for %s", targetLang)
+	}
+
+	result := make([]string, len(texts))
+	for i, text := range texts {
+		translated, ok := translations[text]
+		if !ok {
+			return nil, fmt.Errorf("translation fixture has no %s translation for %q", targetLang, text)
+		}
+		result[i] = translated
+	}
+
+	return result, nil
+}
@@ -0,0 +1,207 @@
+package translate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GlossaryFileName is the glossary file in the state directory
+const GlossaryFileName = "glossary.json"
+
+// glossaryTokenRegex matches the tokens protecting glossary terms, allowing for
+// spaces a provider may add inside them
+var glossaryTokenRegex = regexp.MustCompile(`\{\s*#\s*(\d+)\s*\}`)
+
+// GlossaryTerm is a term machine translation must not change. Terms without
+// translations, such as brand names, are kept as is; otherwise the translation
+// for the target language is forced.
+type GlossaryTerm struct {
+	Term         string            `json:"term"`
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// Glossary holds the project's do-not-translate terms and forced translations
+type Glossary struct {
+	path  string
+	Terms []GlossaryTerm `json:"terms"`
+}
+
+// GetGlossaryPath returns the path to the glossary file for a Flutter project
+func GetGlossaryPath(projectPath string) string {
+	return filepath.Join(projectPath, StateDirName, GlossaryFileName)
+}
+
+// LoadGlossary reads the project's glossary, which is empty if the file doesn't exist
+func LoadGlossary(projectPath string) (*Glossary, error) {
+	glossary := &Glossary{path: GetGlossaryPath(projectPath), Terms: []GlossaryTerm{}}
+
+	data, err := os.ReadFile(glossary.path)
+	if os.IsNotExist(err) {
+		return glossary, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read glossary: %v", err)
+	}
+
+	if err := json.Unmarshal(data, glossary); err != nil {
+		return nil, fmt.Errorf("failed to parse glossary %s: %v", glossary.path, err)
+	}
+
+	return glossary, nil
+}
+
+// Save writes the glossary, sorted by term
+func (g *Glossary) Save() error {
+	sort.Slice(g.Terms, func(i, j int) bool {
+		return strings.ToLower(g.Terms[i].Term) < strings.ToLower(g.Terms[j].Term)
+	})
+
+	if err := os.MkdirAll(filepath.Dir(g.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal glossary: %v", err)
+	}
+
+	if err := os.WriteFile(g.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write glossary: %v", err)
+	}
+
+	return nil
+}
+
+// Set adds a term or replaces its translations
+func (g *Glossary) Set(term string, translations map[string]string) error {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return fmt.Errorf("glossary term cannot be empty")
+	}
+
+	for i := range g.Terms {
+		if g.Terms[i].Term == term {
+			g.Terms[i].Translations = translations
+			return nil
+		}
+	}
+
+	g.Terms = append(g.Terms, GlossaryTerm{Term: term, Translations: translations})
+	return nil
+}
+
+// Remove deletes a term, reporting whether it existed
+func (g *Glossary) Remove(term string) bool {
+	for i := range g.Terms {
+		if g.Terms[i].Term == term {
+			g.Terms = append(g.Terms[:i], g.Terms[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Protect replaces glossary terms in text with tokens such as {#0} that providers
+// leave untouched. It returns the protected text and the replacement for each token:
+// the forced translation for targetLang, or the term itself.
+func (g *Glossary) Protect(text, targetLang string) (string, []string) {
+	if g == nil || len(g.Terms) == 0 {
+		return text, nil
+	}
+
+	// Longer terms first, so "Acme Cloud" wins over "Acme"
+	terms := append([]GlossaryTerm{}, g.Terms...)
+	sort.SliceStable(terms, func(i, j int) bool {
+		return len(terms[i].Term) > len(terms[j].Term)
+	})
+
+	var replacements []string
+	for _, term := range terms {
+		regex := glossaryTermRegex(term.Term)
+		if regex == nil {
+			continue
+		}
+
+		replacement := term.Term
+		if translation, ok := term.lookup(targetLang); ok {
+			replacement = translation
+		}
+
+		text = regex.ReplaceAllStringFunc(text, func(string) string {
+			replacements = append(replacements, replacement)
+			return "{#" + strconv.Itoa(len(replacements)-1) + "}"
+		})
+	}
+
+	return text, replacements
+}
+
+// GlossaryFingerprint identifies the glossary terms Protect applied to a text, for
+// the translation memory. It is empty when no terms were applied.
+func GlossaryFingerprint(protected string, replacements []string) string {
+	if len(replacements) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(protected + "\x00" + strings.Join(replacements, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Restore puts the glossary replacements back in place of their tokens
+func (g *Glossary) Restore(text string, replacements []string) string {
+	if len(replacements) == 0 {
+		return text
+	}
+
+	return glossaryTokenRegex.ReplaceAllStringFunc(text, func(token string) string {
+		index, err := strconv.Atoi(glossaryTokenRegex.FindStringSubmatch(token)[1])
+		if err != nil || index >= len(replacements) {
+			return token
+		}
+		return replacements[index]
+	})
+}
+
+// lookup returns the forced translation of a term, falling back from a regional
+// code such as pt_BR to its language
+func (t GlossaryTerm) lookup(targetLang string) (string, bool) {
+	if translation, ok := t.Translations[targetLang]; ok && translation != "" {
+		return translation, true
+	}
+	translation, ok := t.Translations[strings.Split(targetLang, "_")[0]]
+	return translation, ok && translation != ""
+}
+
+// glossaryTermRegex matches a term as a whole word, so "Pro" doesn't match "Product"
+func glossaryTermRegex(term string) *regexp.Regexp {
+	if term == "" {
+		return nil
+	}
+
+	pattern := regexp.QuoteMeta(term)
+	if isWordChar(term[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordChar(term[len(term)-1]) {
+		pattern += `\b`
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	return regex
+}
+
+// isWordChar reports whether a byte is an ASCII letter, digit or underscore
+func isWordChar(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
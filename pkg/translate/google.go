@@ -0,0 +1,83 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// googleTranslateURL is the Google Cloud Translation v2 REST endpoint
+const googleTranslateURL = "https://translation.googleapis.com/language/translate/v2"
+
+// GoogleTranslateResponse represents the response from Google Translate API
+type GoogleTranslateResponse struct {
+	Data struct {
+		Translations []struct {
+			TranslatedText         string `json:"translatedText"`
+			DetectedSourceLanguage string `json:"detectedSourceLanguage,omitempty"`
+		} `json:"translations"`
+	} `json:"data"`
+}
+
+// GoogleTranslator translates with the Google Translate v2 REST API and an API key
+type GoogleTranslator struct {
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// Name returns the provider name
+func (t *GoogleTranslator) Name() string {
+	return "Google Translate"
+}
+
+// Translate translates texts in a single request
+func (t *GoogleTranslator) Translate(texts []string, sourceLang, targetLang string) ([]string, error) {
+	// Convert language codes to Google Translate format
+	source, err := GetGoogleLanguageCode(sourceLang)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported source language: %v", err)
+	}
+
+	target, err := GetGoogleLanguageCode(targetLang)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported target language: %v", err)
+	}
+
+	data := url.Values{}
+	data.Set("key", t.APIKey)
+	for _, text := range texts {
+		data.Add("q", text)
+	}
+	data.Set("source", source)
+	data.Set("target", target)
+	data.Set("format", "text")
+
+	resp, err := t.HTTPClient.PostForm(googleTranslateURL, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Google Translate API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError("Google Translate", resp, body)
+	}
+
+	var googleResp GoogleTranslateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&googleResp); err != nil {
+		return nil, fmt.Errorf("failed to decode Google Translate response: %v", err)
+	}
+
+	if len(googleResp.Data.Translations) == 0 {
+		return nil, fmt.Errorf("no translations returned from Google Translate API")
+	}
+
+	translations := make([]string, len(googleResp.Data.Translations))
+	for i, translation := range googleResp.Data.Translations {
+		translations[i] = translation.TranslatedText
+	}
+
+	return translations, nil
+}
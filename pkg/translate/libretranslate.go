@@ -0,0 +1,79 @@
+package translate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// LibreTranslateTranslator translates with a LibreTranslate instance, such as one
+// running locally with Docker
+type LibreTranslateTranslator struct {
+	URL string
+	// APIKey is only needed by instances that require one
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// libreTranslateRequest is the body of a LibreTranslate translate request
+type libreTranslateRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	APIKey string   `json:"api_key,omitempty"`
+}
+
+// Name returns the provider name
+func (t *LibreTranslateTranslator) Name() string {
+	return "LibreTranslate"
+}
+
+// Translate translates texts in a single request
+func (t *LibreTranslateTranslator) Translate(texts []string, sourceLang, targetLang string) ([]string, error) {
+	body, err := json.Marshal(libreTranslateRequest{
+		Q:      texts,
+		Source: libreTranslateLanguageCode(sourceLang),
+		Target: libreTranslateLanguageCode(targetLang),
+		Format: "text",
+		APIKey: t.APIKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := strings.TrimSuffix(t.URL, "/") + "/translate"
+	resp, err := t.HTTPClient.Post(apiURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call LibreTranslate at %s: %w", t.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError("LibreTranslate", resp, body)
+	}
+
+	// A list of texts is answered with a list of translations
+	var libreResp struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&libreResp); err != nil {
+		return nil, fmt.Errorf("failed to decode LibreTranslate response: %v", err)
+	}
+
+	return libreResp.TranslatedText, nil
+}
+
+// libreTranslateLanguageCode converts a project language code to LibreTranslate's,
+// which uses zt for traditional Chinese
+func libreTranslateLanguageCode(code string) string {
+	switch code {
+	case "zh_TW", "zh_HK":
+		return "zt"
+	}
+	return strings.ToLower(strings.Split(code, "_")[0])
+}
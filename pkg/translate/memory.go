@@ -0,0 +1,238 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// StateDirName is the name of the directory holding fdawg project state
+	StateDirName = ".fdawg"
+
+	// MemoryFileName is the translation memory file in the state directory
+	MemoryFileName = "translation_memory.json"
+
+	// ManualProvider is the provider of memory entries written by a person
+	ManualProvider = "manual"
+)
+
+// memoryFileMutex serializes loads and saves of translation memories within the
+// process, so concurrent saves merge their changes instead of overwriting each other
+var memoryFileMutex sync.Mutex
+
+// MemoryEntry is a cached translation of a source text
+type MemoryEntry struct {
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+	Source     string `json:"source"`
+	Target     string `json:"target"`
+	// Provider is the translator that produced the entry, or "manual" for edited entries
+	Provider string `json:"provider"`
+	// Glossary fingerprints the glossary terms applied to the source, so the entry
+	// isn't reused once those terms change
+	Glossary  string    `json:"glossary,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Memory caches translations so the same text isn't sent to the provider twice
+type Memory struct {
+	path    string
+	entries map[string]MemoryEntry
+	// changes holds the entries set (or deleted, when nil) since the memory was
+	// loaded or saved, which Save applies to the file as it is then
+	changes map[string]*MemoryEntry
+	mutex   sync.Mutex
+}
+
+// GetMemoryPath returns the path to the translation memory file for a Flutter project
+func GetMemoryPath(projectPath string) string {
+	return filepath.Join(projectPath, StateDirName, MemoryFileName)
+}
+
+// LoadMemory reads the project's translation memory, which is empty if the file doesn't exist
+func LoadMemory(projectPath string) (*Memory, error) {
+	memory := &Memory{
+		path:    GetMemoryPath(projectPath),
+		changes: make(map[string]*MemoryEntry),
+	}
+
+	memoryFileMutex.Lock()
+	defer memoryFileMutex.Unlock()
+
+	entries, err := readMemoryEntries(memory.path)
+	if err != nil {
+		return nil, err
+	}
+	memory.entries = entries
+
+	return memory, nil
+}
+
+// readMemoryEntries reads the entries of a translation memory file by key
+func readMemoryEntries(path string) (map[string]MemoryEntry, error) {
+	entries := make(map[string]MemoryEntry)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read translation memory: %v", err)
+	}
+
+	var file struct {
+		Entries []MemoryEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse translation memory %s: %v", path, err)
+	}
+
+	for _, entry := range file.Entries {
+		entries[memoryKey(entry.SourceLang, entry.TargetLang, entry.Source)] = entry
+	}
+
+	return entries, nil
+}
+
+// memoryKey identifies a source text in a language pair
+func memoryKey(sourceLang, targetLang, source string) string {
+	return sourceLang + "\x00" + targetLang + "\x00" + source
+}
+
+// Lookup returns the cached translation of a source text, made with the glossary
+// terms fingerprinted by glossary. Entries written by a person match any glossary.
+func (m *Memory) Lookup(sourceLang, targetLang, source, glossary string) (string, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := m.entries[memoryKey(sourceLang, targetLang, source)]
+	if !ok || (entry.Provider != ManualProvider && entry.Glossary != glossary) {
+		return "", false
+	}
+	return entry.Target, true
+}
+
+// Set adds or replaces the translation of a source text, made with the glossary
+// terms fingerprinted by glossary
+func (m *Memory) Set(sourceLang, targetLang, source, target, provider, glossary string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := memoryKey(sourceLang, targetLang, source)
+	entry := MemoryEntry{
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+		Source:     source,
+		Target:     target,
+		Provider:   provider,
+		Glossary:   glossary,
+		UpdatedAt:  time.Now(),
+	}
+	m.entries[key] = entry
+	m.changes[key] = &entry
+}
+
+// Delete removes the translation of a source text, reporting whether it existed
+func (m *Memory) Delete(sourceLang, targetLang, source string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := memoryKey(sourceLang, targetLang, source)
+	if _, ok := m.entries[key]; !ok {
+		return false
+	}
+	delete(m.entries, key)
+	m.changes[key] = nil
+	return true
+}
+
+// Clear removes every entry translated into targetLang, or all entries if
+// targetLang is empty, returning the number removed
+func (m *Memory) Clear(targetLang string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	removed := 0
+	for key, entry := range m.entries {
+		if targetLang == "" || entry.TargetLang == targetLang {
+			delete(m.entries, key)
+			m.changes[key] = nil
+			removed++
+		}
+	}
+	return removed
+}
+
+// Entries returns the entries sorted by language pair and source text
+func (m *Memory) Entries() []MemoryEntry {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return sortedMemoryEntries(m.entries)
+}
+
+// sortedMemoryEntries returns entries sorted by language pair and source text
+func sortedMemoryEntries(entries map[string]MemoryEntry) []MemoryEntry {
+	sorted := make([]MemoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		sorted = append(sorted, entry)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].SourceLang != sorted[j].SourceLang {
+			return sorted[i].SourceLang < sorted[j].SourceLang
+		}
+		if sorted[i].TargetLang != sorted[j].TargetLang {
+			return sorted[i].TargetLang < sorted[j].TargetLang
+		}
+		return sorted[i].Source < sorted[j].Source
+	})
+	return sorted
+}
+
+// Save writes the memory if it changed since it was loaded. The changes are
+// applied to the file as it is now, so entries saved by others meanwhile are kept.
+func (m *Memory) Save() error {
+	memoryFileMutex.Lock()
+	defer memoryFileMutex.Unlock()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.changes) == 0 {
+		return nil
+	}
+
+	entries, err := readMemoryEntries(m.path)
+	if err != nil {
+		return err
+	}
+	for key, entry := range m.changes {
+		if entry == nil {
+			delete(entries, key)
+		} else {
+			entries[key] = *entry
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{"entries": sortedMemoryEntries(entries)}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal translation memory: %v", err)
+	}
+
+	if err := os.WriteFile(m.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write translation memory: %v", err)
+	}
+
+	m.entries = entries
+	m.changes = make(map[string]*MemoryEntry)
+	return nil
+}
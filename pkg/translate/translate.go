@@ -2,18 +2,31 @@ package translate
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
-// Service provides translation functionality using Google Translate API
+// Service provides translation functionality through a Translator, with optional
+// rate limiting and retries for bulk translation
 type Service struct {
 	config     *Config
 	httpClient *http.Client
+	translator Translator
+
+	retries  int
+	interval time.Duration
+	lastCall time.Time
+	mutex    sync.Mutex
+
+	// memory and glossary are loaded from the project's .fdawg directory
+	memory   *Memory
+	glossary *Glossary
 }
 
 // TranslationRequest represents a translation request
@@ -44,34 +57,55 @@ type BatchTranslationResponse struct {
 	Translations []TranslationResponse `json:"translations"`
 }
 
-// GoogleTranslateResponse represents the response from Google Translate API
-type GoogleTranslateResponse struct {
-	Data struct {
-		Translations []struct {
-			TranslatedText         string `json:"translatedText"`
-			DetectedSourceLanguage string `json:"detectedSourceLanguage,omitempty"`
-		} `json:"translations"`
-	} `json:"data"`
-}
-
 // DetectLanguageResponse represents language detection response
 type DetectLanguageResponse struct {
 	Language   string  `json:"language"`
 	Confidence float64 `json:"confidence"`
 }
 
-// NewService creates a new translation service
+// NewService creates a new translation service using the configured provider
 func NewService(config *Config) (*Service, error) {
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
 
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	translator, err := NewTranslator(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	service := &Service{
+		config:     config,
+		httpClient: httpClient,
+		translator: translator,
+	}
+	service.SetRateLimit(config.RequestsPerSecond)
+
+	if config.ProjectPath != "" {
+		if service.memory, err = LoadMemory(config.ProjectPath); err != nil {
+			return nil, err
+		}
+		if service.glossary, err = LoadGlossary(config.ProjectPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return service, nil
+}
+
+// NewServiceWithTranslator creates a translation service backed by a custom Translator
+func NewServiceWithTranslator(config *Config, translator Translator) *Service {
 	return &Service{
 		config: config,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-	}, nil
+		translator: translator,
+	}
 }
 
 // IsEnabled returns whether the translation service is enabled
@@ -79,57 +113,45 @@ func (s *Service) IsEnabled() bool {
 	return s.config.IsEnabled()
 }
 
-// Translate translates text from source language to target language
-func (s *Service) Translate(req TranslationRequest) (*TranslationResponse, error) {
-	if !s.IsEnabled() {
-		return nil, fmt.Errorf("translation service is not enabled")
-	}
+// ProviderName returns the name of the translator the service uses
+func (s *Service) ProviderName() string {
+	return s.translator.Name()
+}
 
-	// Convert language codes to Google Translate format
-	sourceLang, err := GetGoogleLanguageCode(req.SourceLang)
-	if err != nil {
-		return nil, fmt.Errorf("unsupported source language: %v", err)
-	}
+// SetRateLimit limits calls to the provider to requestsPerSecond. Zero or less disables the limit.
+func (s *Service) SetRateLimit(requestsPerSecond float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	targetLang, err := GetGoogleLanguageCode(req.TargetLang)
-	if err != nil {
-		return nil, fmt.Errorf("unsupported target language: %v", err)
+	s.interval = 0
+	if requestsPerSecond > 0 {
+		s.interval = time.Duration(float64(time.Second) / requestsPerSecond)
 	}
+}
 
-	// Prepare the request to Google Translate API
-	apiURL := "https://translation.googleapis.com/language/translate/v2"
-
-	data := url.Values{}
-	data.Set("key", s.config.APIKey)
-	data.Set("q", req.Text)
-	data.Set("source", sourceLang)
-	data.Set("target", targetLang)
-	data.Set("format", "text")
-
-	resp, err := s.httpClient.PostForm(apiURL, data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call Google Translate API: %v", err)
-	}
-	defer resp.Body.Close()
+// SetRetries sets how many times a failed call is retried when the provider is
+// rate limiting, unavailable or unreachable
+func (s *Service) SetRetries(retries int) {
+	s.retries = retries
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Google Translate API error (status %d): %s", resp.StatusCode, string(body))
+// Translate translates text from source language to target language
+func (s *Service) Translate(req TranslationRequest) (*TranslationResponse, error) {
+	if !s.IsEnabled() {
+		return nil, fmt.Errorf("translation service is not enabled")
 	}
 
-	var googleResp GoogleTranslateResponse
-	if err := json.NewDecoder(resp.Body).Decode(&googleResp); err != nil {
-		return nil, fmt.Errorf("failed to decode Google Translate response: %v", err)
+	translations, err := s.translate([]string{req.Text}, req.SourceLang, req.TargetLang)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(googleResp.Data.Translations) == 0 {
-		return nil, fmt.Errorf("no translations returned from Google Translate API")
+	if len(translations) == 0 {
+		return nil, fmt.Errorf("no translations returned from %s", s.translator.Name())
 	}
 
-	translation := googleResp.Data.Translations[0]
-
 	return &TranslationResponse{
-		TranslatedText: translation.TranslatedText,
+		TranslatedText: translations[0],
 		SourceLang:     req.SourceLang,
 		TargetLang:     req.TargetLang,
 	}, nil
@@ -145,62 +167,143 @@ func (s *Service) BatchTranslate(req BatchTranslationRequest) (*BatchTranslation
 		return &BatchTranslationResponse{Translations: []TranslationResponse{}}, nil
 	}
 
-	// Convert language codes to Google Translate format
-	sourceLang, err := GetGoogleLanguageCode(req.SourceLang)
+	texts, err := s.translate(req.Texts, req.SourceLang, req.TargetLang)
 	if err != nil {
-		return nil, fmt.Errorf("unsupported source language: %v", err)
+		return nil, err
 	}
 
-	targetLang, err := GetGoogleLanguageCode(req.TargetLang)
-	if err != nil {
-		return nil, fmt.Errorf("unsupported target language: %v", err)
+	if len(texts) != len(req.Texts) {
+		return nil, fmt.Errorf("unexpected number of translations returned: expected %d, got %d",
+			len(req.Texts), len(texts))
 	}
 
-	// Prepare the request to Google Translate API
-	apiURL := "https://translation.googleapis.com/language/translate/v2"
+	translations := make([]TranslationResponse, len(texts))
+	for i, text := range texts {
+		translations[i] = TranslationResponse{
+			TranslatedText: text,
+			SourceLang:     req.SourceLang,
+			TargetLang:     req.TargetLang,
+		}
+	}
 
-	data := url.Values{}
-	data.Set("key", s.config.APIKey)
-	for _, text := range req.Texts {
-		data.Add("q", text)
+	return &BatchTranslationResponse{
+		Translations: translations,
+	}, nil
+}
+
+// SetMemory sets the translation memory checked before calling the provider
+func (s *Service) SetMemory(memory *Memory) {
+	s.memory = memory
+}
+
+// SetGlossary sets the glossary of terms protected from translation
+func (s *Service) SetGlossary(glossary *Glossary) {
+	s.glossary = glossary
+}
+
+// translate answers texts from the translation memory where possible and sends
+// the rest to the provider, with glossary terms protected. New translations are
+// added to the memory with a fingerprint of the glossary terms applied, so they
+// aren't reused once those terms change.
+func (s *Service) translate(texts []string, sourceLang, targetLang string) ([]string, error) {
+	results := make([]string, len(texts))
+
+	var pending []int
+	var protected []string
+	var replacements [][]string
+	var fingerprints []string
+	for i, text := range texts {
+		protectedText, textReplacements := s.glossary.Protect(text, targetLang)
+		fingerprint := GlossaryFingerprint(protectedText, textReplacements)
+
+		if s.memory != nil {
+			if target, ok := s.memory.Lookup(sourceLang, targetLang, text, fingerprint); ok {
+				results[i] = target
+				continue
+			}
+		}
+
+		pending = append(pending, i)
+		protected = append(protected, protectedText)
+		replacements = append(replacements, textReplacements)
+		fingerprints = append(fingerprints, fingerprint)
 	}
-	data.Set("source", sourceLang)
-	data.Set("target", targetLang)
-	data.Set("format", "text")
 
-	resp, err := s.httpClient.PostForm(apiURL, data)
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	translations, err := s.callTranslator(protected, sourceLang, targetLang)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call Google Translate API: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Google Translate API error (status %d): %s", resp.StatusCode, string(body))
+	if len(translations) != len(pending) {
+		return nil, fmt.Errorf("unexpected number of translations returned: expected %d, got %d",
+			len(pending), len(translations))
 	}
 
-	var googleResp GoogleTranslateResponse
-	if err := json.NewDecoder(resp.Body).Decode(&googleResp); err != nil {
-		return nil, fmt.Errorf("failed to decode Google Translate response: %v", err)
+	for j, i := range pending {
+		results[i] = s.glossary.Restore(translations[j], replacements[j])
+		if s.memory != nil {
+			s.memory.Set(sourceLang, targetLang, texts[i], results[i], s.translator.Name(), fingerprints[j])
+		}
 	}
 
-	if len(googleResp.Data.Translations) != len(req.Texts) {
-		return nil, fmt.Errorf("unexpected number of translations returned: expected %d, got %d",
-			len(req.Texts), len(googleResp.Data.Translations))
+	if s.memory != nil {
+		if err := s.memory.Save(); err != nil {
+			return nil, err
+		}
 	}
 
-	translations := make([]TranslationResponse, len(googleResp.Data.Translations))
-	for i, translation := range googleResp.Data.Translations {
-		translations[i] = TranslationResponse{
-			TranslatedText: translation.TranslatedText,
-			SourceLang:     req.SourceLang,
-			TargetLang:     req.TargetLang,
+	return results, nil
+}
+
+// callTranslator calls the translator, waiting for the rate limit and retrying
+// failures that are likely to be temporary with an increasing delay
+func (s *Service) callTranslator(texts []string, sourceLang, targetLang string) ([]string, error) {
+	var lastErr error
+	for attempt := 0; attempt <= s.retries; attempt++ {
+		if attempt > 0 {
+			delay := time.Duration(1<<uint(attempt-1)) * time.Second
+			var apiErr *APIError
+			if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > delay {
+				delay = apiErr.RetryAfter
+			}
+			time.Sleep(delay)
+		}
+
+		s.wait()
+
+		translations, err := s.translator.Translate(texts, sourceLang, targetLang)
+		if err == nil {
+			return translations, nil
 		}
+		if !isRetryable(err) {
+			return nil, err
+		}
+		lastErr = err
 	}
 
-	return &BatchTranslationResponse{
-		Translations: translations,
-	}, nil
+	if s.retries == 0 {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("%v (gave up after %d retries)", lastErr, s.retries)
+}
+
+// wait blocks until the rate limit allows another call
+func (s *Service) wait() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.interval <= 0 {
+		return
+	}
+
+	if next := s.lastCall.Add(s.interval); time.Now().Before(next) {
+		time.Sleep(time.Until(next))
+	}
+	s.lastCall = time.Now()
 }
 
 // DetectLanguage detects the language of the given text
@@ -209,6 +312,10 @@ func (s *Service) DetectLanguage(text string) (*DetectLanguageResponse, error) {
 		return nil, fmt.Errorf("translation service is not enabled")
 	}
 
+	if s.config.ProviderName() != ProviderGoogle {
+		return nil, fmt.Errorf("language detection requires the Google Translate provider")
+	}
+
 	if strings.TrimSpace(text) == "" {
 		return nil, fmt.Errorf("text cannot be empty")
 	}
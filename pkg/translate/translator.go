@@ -0,0 +1,84 @@
+package translate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Translator is a machine translation provider
+type Translator interface {
+	// Name identifies the provider in messages
+	Name() string
+
+	// Translate translates texts, keeping their order. Languages are project
+	// language codes such as pt_BR; providers map them to their own codes.
+	Translate(texts []string, sourceLang, targetLang string) ([]string, error)
+}
+
+// NewTranslator creates the Translator selected by the configuration
+func NewTranslator(config *Config, httpClient *http.Client) (Translator, error) {
+	switch config.ProviderName() {
+	case ProviderGoogle:
+		return &GoogleTranslator{APIKey: config.APIKey, HTTPClient: httpClient}, nil
+	case ProviderDeepL:
+		return &DeepLTranslator{APIKey: config.DeepLAPIKey, HTTPClient: httpClient}, nil
+	case ProviderLibreTranslate:
+		baseURL := config.LibreTranslateURL
+		if baseURL == "" {
+			baseURL = defaultLibreTranslateURL
+		}
+		return &LibreTranslateTranslator{URL: baseURL, APIKey: config.LibreTranslateAPIKey, HTTPClient: httpClient}, nil
+	case ProviderFixture:
+		return LoadFixtureTranslator(config.FixturePath)
+	default:
+		return nil, fmt.Errorf("unknown translation provider: %s", config.Provider)
+	}
+}
+
+// Providers returns the names of the built-in translation providers
+func Providers() []string {
+	return []string{ProviderGoogle, ProviderDeepL, ProviderLibreTranslate, ProviderFixture}
+}
+
+// APIError is an unsuccessful HTTP response from a translation provider
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	// RetryAfter is how long the provider asked to wait before retrying, if it said
+	RetryAfter time.Duration
+}
+
+// Error formats the provider, status and response body
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// newAPIError creates an APIError from a response, reading its Retry-After header
+func newAPIError(provider string, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
+
+// isRetryable reports whether a failed call may succeed if tried again: the provider
+// was rate limiting or unavailable, or the network request failed
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProgressBar draws a progress bar on a single terminal line
+type ProgressBar struct {
+	label string
+	width int
+}
+
+// NewProgressBar creates a progress bar with a label and a width in characters
+func NewProgressBar(label string, width int) *ProgressBar {
+	return &ProgressBar{label: label, width: width}
+}
+
+// Update redraws the bar, e.g. "Translating [#####-----] 12/24 (50%)"
+func (p *ProgressBar) Update(done, total int) {
+	percent := 100
+	if total > 0 {
+		percent = done * 100 / total
+	}
+	filled := p.width * percent / 100
+
+	fmt.Printf("\r%s [%s%s] %d/%d (%d%%)", p.label, strings.Repeat("#", filled), strings.Repeat("-", p.width-filled), done, total, percent)
+}
+
+// Finish ends the progress bar line
+func (p *ProgressBar) Finish() {
+	fmt.Println()
+}